@@ -1,5 +1,558 @@
 package config
 
-import "gorm.io/gorm"
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
 
-var DB *gorm.DB
\ No newline at end of file
+	"github.com/joho/godotenv"
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// 应用配置
+//
+// 加载优先级 (高 -> 低): 进程环境变量 > .env 文件 > YAML/TOML 配置文件 > 默认值
+type Config struct {
+	Server    ServerConfig    `yaml:"server" toml:"server"`
+	Database  DatabaseConfig  `yaml:"database" toml:"database"`
+	JWT       JWTConfig       `yaml:"jwt" toml:"jwt"`
+	Mail      MailConfig      `yaml:"mail" toml:"mail"`
+	Account   AccountConfig   `yaml:"account" toml:"account"`
+	OIDC      OIDCConfig      `yaml:"oidc" toml:"oidc"`
+	Scheduler SchedulerConfig `yaml:"scheduler" toml:"scheduler"`
+}
+
+// 服务器配置
+type ServerConfig struct {
+	Host           string   `yaml:"host" toml:"host" env:"HOST"`
+	Port           int      `yaml:"port" toml:"port" env:"PORT"`
+	AllowedOrigins []string `yaml:"allowed_origins" toml:"allowed_origins" env:"CORS_ALLOWED_ORIGINS"`
+	// 可信反向代理的 IP 或 CIDR，只有来自这些地址的请求才按 X-Forwarded-For/X-Real-IP 识别客户端 IP
+	// 默认为空，即不信任任何代理，始终使用连接的来源地址
+	TrustedProxies []string `yaml:"trusted_proxies" toml:"trusted_proxies" env:"TRUSTED_PROXIES"`
+
+	ReadTimeout       time.Duration `yaml:"read_timeout" toml:"read_timeout" env:"SERVER_READ_TIMEOUT"`
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout" toml:"read_header_timeout" env:"SERVER_READ_HEADER_TIMEOUT"`
+	WriteTimeout      time.Duration `yaml:"write_timeout" toml:"write_timeout" env:"SERVER_WRITE_TIMEOUT"`
+	IdleTimeout       time.Duration `yaml:"idle_timeout" toml:"idle_timeout" env:"SERVER_IDLE_TIMEOUT"`
+	MaxHeaderBytes    int           `yaml:"max_header_bytes" toml:"max_header_bytes" env:"SERVER_MAX_HEADER_BYTES"`
+	// 收到 SIGINT/SIGTERM 后等待进行中请求完成的最长时间
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" toml:"shutdown_timeout" env:"SERVER_SHUTDOWN_TIMEOUT"`
+}
+
+// 支持的数据库驱动
+const (
+	DriverMySQL    = "mysql"
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
+// 数据库配置
+type DatabaseConfig struct {
+	// mysql / postgres / sqlite，sqlite 时 Name 为数据库文件路径，":memory:" 表示内存数据库
+	Driver   string `yaml:"driver" toml:"driver" env:"DB_DRIVER"`
+	Host     string `yaml:"host" toml:"host" env:"DB_HOST"`
+	Port     int    `yaml:"port" toml:"port" env:"DB_PORT"`
+	User     string `yaml:"user" toml:"user" env:"DB_USER"`
+	Password string `yaml:"password" toml:"password" env:"DB_PASSWORD"`
+	Name     string `yaml:"name" toml:"name" env:"DB_NAME"`
+	SSLMode  string `yaml:"sslmode" toml:"sslmode" env:"DB_SSLMODE"`
+	// 启动时自动执行未执行的迁移，仅建议在本地开发或内存数据库时开启
+	AutoMigrate bool `yaml:"auto_migrate" toml:"auto_migrate" env:"DB_AUTO_MIGRATE"`
+
+	// 最大打开连接数，0 表示不限制
+	MaxOpenConns int `yaml:"max_open_conns" toml:"max_open_conns" env:"DB_MAX_OPEN_CONNS"`
+	// 最大空闲连接数，0 表示不保留空闲连接，每次请求都重新建立连接
+	MaxIdleConns int `yaml:"max_idle_conns" toml:"max_idle_conns" env:"DB_MAX_IDLE_CONNS"`
+	// 连接最长使用时间，0 表示不限制
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime" toml:"conn_max_lifetime" env:"DB_CONN_MAX_LIFETIME"`
+	// 连接最长空闲时间，0 表示不限制
+	ConnMaxIdleTime time.Duration `yaml:"conn_max_idle_time" toml:"conn_max_idle_time" env:"DB_CONN_MAX_IDLE_TIME"`
+}
+
+// JWT配置
+type JWTConfig struct {
+	// 签名链接令牌的密钥，未配置私钥时也用于 HS256 签名访问令牌
+	Secret string `yaml:"secret" toml:"secret" env:"JWT_SECRET"`
+	// 访问令牌签名私钥 (PEM，RSA 或 Ed25519)，配置后使用 RS256 或 EdDSA 签名
+	PrivateKeyFile string `yaml:"private_key_file" toml:"private_key_file" env:"JWT_PRIVATE_KEY_FILE"`
+	// 额外接受的校验公钥 (PEM)，用于密钥轮换
+	PublicKeyFiles []string `yaml:"public_key_files" toml:"public_key_files" env:"JWT_PUBLIC_KEY_FILES"`
+	// 访问令牌的签发方 (iss) 和受众 (aud)
+	Issuer   string `yaml:"issuer" toml:"issuer" env:"JWT_ISSUER"`
+	Audience string `yaml:"audience" toml:"audience" env:"JWT_AUDIENCE"`
+	// 访问令牌有效期
+	Expire time.Duration `yaml:"expire" toml:"expire" env:"JWT_EXPIRE"`
+	// 刷新令牌有效期
+	RefreshExpire time.Duration `yaml:"refresh_expire" toml:"refresh_expire" env:"JWT_REFRESH_EXPIRE"`
+}
+
+// 支持的邮件发送方式
+const (
+	MailDriverSMTP = "smtp"
+	// 每封邮件写入 Dir 下的一个 .eml 文件，用于本地开发和测试
+	MailDriverFile = "file"
+	// 邮件内容输出到日志
+	MailDriverLog = "log"
+)
+
+// 邮件配置
+type MailConfig struct {
+	Driver string `yaml:"driver" toml:"driver" env:"MAIL_DRIVER"`
+	From   string `yaml:"from" toml:"from" env:"MAIL_FROM"`
+	Dir    string `yaml:"dir" toml:"dir" env:"MAIL_DIR"`
+
+	SMTPHost     string `yaml:"smtp_host" toml:"smtp_host" env:"SMTP_HOST"`
+	SMTPPort     int    `yaml:"smtp_port" toml:"smtp_port" env:"SMTP_PORT"`
+	SMTPUser     string `yaml:"smtp_user" toml:"smtp_user" env:"SMTP_USER"`
+	SMTPPassword string `yaml:"smtp_password" toml:"smtp_password" env:"SMTP_PASSWORD"`
+}
+
+// 账号配置
+type AccountConfig struct {
+	// 前端地址，邮件中的链接指向该地址
+	FrontendURL string `yaml:"frontend_url" toml:"frontend_url" env:"FRONTEND_URL"`
+	// 密码重置链接有效期
+	PasswordResetExpire time.Duration `yaml:"password_reset_expire" toml:"password_reset_expire" env:"PASSWORD_RESET_EXPIRE"`
+	// 邮箱验证链接有效期
+	EmailVerifyExpire time.Duration `yaml:"email_verify_expire" toml:"email_verify_expire" env:"EMAIL_VERIFY_EXPIRE"`
+	// 身份验证器应用中显示的两步验证签发方名称
+	TOTPIssuer string `yaml:"totp_issuer" toml:"totp_issuer" env:"TOTP_ISSUER"`
+
+	// 同一账号连续登录失败多少次后开始锁定
+	LoginMaxFailures int `yaml:"login_max_failures" toml:"login_max_failures" env:"LOGIN_MAX_FAILURES"`
+	// 同一 IP 连续登录失败多少次后开始锁定
+	LoginIPMaxFailures int `yaml:"login_ip_max_failures" toml:"login_ip_max_failures" env:"LOGIN_IP_MAX_FAILURES"`
+	// 首次锁定时长，之后每次失败翻倍
+	LoginLockout time.Duration `yaml:"login_lockout" toml:"login_lockout" env:"LOGIN_LOCKOUT"`
+	// 锁定时长上限
+	LoginMaxLockout time.Duration `yaml:"login_max_lockout" toml:"login_max_lockout" env:"LOGIN_MAX_LOCKOUT"`
+
+	// 密码长度限制，按字符计算
+	PasswordMinLength int `yaml:"password_min_length" toml:"password_min_length" env:"PASSWORD_MIN_LENGTH"`
+	PasswordMaxLength int `yaml:"password_max_length" toml:"password_max_length" env:"PASSWORD_MAX_LENGTH"`
+	// 常见泄露密码列表文件，每行一个明文密码或 SHA-1 摘要 (可带 :次数 后缀)，
+	// 启动时读入内存，最多 100 万条，为空时不检查
+	PasswordBreachedFile string `yaml:"password_breached_file" toml:"password_breached_file" env:"PASSWORD_BREACHED_FILE"`
+
+	// 注销账号时如何处理用户的文章和评论
+	DeletionPolicy string `yaml:"deletion_policy" toml:"deletion_policy" env:"ACCOUNT_DELETION_POLICY"`
+}
+
+// 注销账号时文章和评论的处理方式
+const (
+	// 保留文章和评论，作者改为匿名的已注销用户
+	DeletionAnonymize = "anonymize"
+	// 删除用户的文章、文章下的评论和用户发表的评论
+	DeletionCascade = "cascade"
+)
+
+// OpenID Connect 登录配置
+type OIDCConfig struct {
+	// 身份提供方地址，为空时不启用 OIDC 登录
+	Issuer       string `yaml:"issuer" toml:"issuer" env:"OIDC_ISSUER"`
+	ClientID     string `yaml:"client_id" toml:"client_id" env:"OIDC_CLIENT_ID"`
+	ClientSecret string `yaml:"client_secret" toml:"client_secret" env:"OIDC_CLIENT_SECRET"`
+	// 身份提供方登录完成后的回调地址，通常为前端页面，由前端将 code 和 state 提交给后端
+	RedirectURL string   `yaml:"redirect_url" toml:"redirect_url" env:"OIDC_REDIRECT_URL"`
+	Scopes      []string `yaml:"scopes" toml:"scopes" env:"OIDC_SCOPES"`
+}
+
+// 是否启用 OIDC 登录
+func (o OIDCConfig) Enabled() bool {
+	return o.Issuer != ""
+}
+
+// 后台任务配置
+type SchedulerConfig struct {
+	// 检查到期定时发布文章的间隔，0 表示本实例不执行定时发布
+	PublishInterval time.Duration `yaml:"publish_interval" toml:"publish_interval" env:"SCHEDULER_PUBLISH_INTERVAL"`
+}
+
+// 默认配置
+func Default() *Config {
+	return &Config{
+		Server: ServerConfig{
+			Host:           "localhost",
+			Port:           8080,
+			AllowedOrigins: []string{"http://localhost:3000"},
+
+			ReadTimeout:       15 * time.Second,
+			ReadHeaderTimeout: 5 * time.Second,
+			WriteTimeout:      30 * time.Second,
+			IdleTimeout:       60 * time.Second,
+			MaxHeaderBytes:    1 << 20,
+			ShutdownTimeout:   30 * time.Second,
+		},
+		Database: DatabaseConfig{
+			Driver:  DriverMySQL,
+			Host:    "localhost",
+			User:    "root",
+			Name:    "blog",
+			SSLMode: "disable",
+
+			MaxOpenConns:    25,
+			MaxIdleConns:    10,
+			ConnMaxLifetime: 30 * time.Minute,
+			ConnMaxIdleTime: 5 * time.Minute,
+		},
+		JWT: JWTConfig{
+			Issuer:        "blog-backend",
+			Audience:      "blog-api",
+			Expire:        15 * time.Minute,
+			RefreshExpire: 30 * 24 * time.Hour,
+		},
+		Mail: MailConfig{
+			Driver:   MailDriverLog,
+			From:     "noreply@localhost",
+			Dir:      "mails",
+			SMTPPort: 587,
+		},
+		Account: AccountConfig{
+			FrontendURL:         "http://localhost:3000",
+			PasswordResetExpire: time.Hour,
+			EmailVerifyExpire:   48 * time.Hour,
+			TOTPIssuer:          "Blog",
+
+			LoginMaxFailures:   5,
+			LoginIPMaxFailures: 20,
+			LoginLockout:       time.Minute,
+			LoginMaxLockout:    time.Hour,
+
+			PasswordMinLength: 8,
+			PasswordMaxLength: 128,
+
+			DeletionPolicy: DeletionAnonymize,
+		},
+		OIDC: OIDCConfig{
+			Scopes: []string{"openid", "email", "profile"},
+		},
+		Scheduler: SchedulerConfig{
+			PublishInterval: time.Minute,
+		},
+	}
+}
+
+// 加载配置
+//
+// path 为可选的 YAML (.yaml/.yml) 或 TOML (.toml) 配置文件路径，为空时跳过。
+// envFiles 为要加载的 .env 文件，为空时加载当前目录下的 .env (不存在则忽略)。
+func Load(path string, envFiles ...string) (*Config, error) {
+	cfg := Default()
+
+	if path != "" {
+		if err := loadFile(path, cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := loadDotEnv(envFiles); err != nil {
+		return nil, err
+	}
+
+	if err := applyEnv(reflect.ValueOf(cfg).Elem()); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// 校验配置
+func (c *Config) Validate() error {
+	var problems []string
+
+	if c.Server.Port <= 0 || c.Server.Port > 65535 {
+		problems = append(problems, fmt.Sprintf("PORT 必须在 1-65535 之间，当前为 %d", c.Server.Port))
+	}
+	durations := []struct {
+		name  string
+		value time.Duration
+	}{
+		{"SERVER_READ_TIMEOUT", c.Server.ReadTimeout},
+		{"SERVER_READ_HEADER_TIMEOUT", c.Server.ReadHeaderTimeout},
+		{"SERVER_WRITE_TIMEOUT", c.Server.WriteTimeout},
+		{"SERVER_IDLE_TIMEOUT", c.Server.IdleTimeout},
+		{"DB_CONN_MAX_LIFETIME", c.Database.ConnMaxLifetime},
+		{"DB_CONN_MAX_IDLE_TIME", c.Database.ConnMaxIdleTime},
+		{"SCHEDULER_PUBLISH_INTERVAL", c.Scheduler.PublishInterval},
+	}
+	for _, d := range durations {
+		if d.value < 0 {
+			problems = append(problems, d.name+" 不能为负数")
+		}
+	}
+	if c.Server.MaxHeaderBytes <= 0 {
+		problems = append(problems, "SERVER_MAX_HEADER_BYTES 必须大于 0")
+	}
+	if c.Server.ShutdownTimeout <= 0 {
+		problems = append(problems, "SERVER_SHUTDOWN_TIMEOUT 必须大于 0")
+	}
+	for _, proxy := range c.Server.TrustedProxies {
+		if net.ParseIP(proxy) == nil {
+			if _, _, err := net.ParseCIDR(proxy); err != nil {
+				problems = append(problems, fmt.Sprintf("TRUSTED_PROXIES 中的 %q 不是有效的 IP 或 CIDR", proxy))
+			}
+		}
+	}
+
+	switch c.Database.Driver {
+	case DriverMySQL, DriverPostgres:
+		if c.Database.Host == "" {
+			problems = append(problems, "DB_HOST 不能为空")
+		}
+		if c.Database.Port < 0 || c.Database.Port > 65535 {
+			problems = append(problems, fmt.Sprintf("DB_PORT 必须在 0-65535 之间 (0 表示使用驱动默认端口)，当前为 %d", c.Database.Port))
+		}
+	case DriverSQLite:
+	default:
+		problems = append(problems, fmt.Sprintf("DB_DRIVER 仅支持 mysql/postgres/sqlite，当前为 %q", c.Database.Driver))
+	}
+	if c.Database.Name == "" {
+		problems = append(problems, "DB_NAME 不能为空")
+	}
+	if c.Database.MaxOpenConns < 0 || c.Database.MaxIdleConns < 0 {
+		problems = append(problems, "DB_MAX_OPEN_CONNS 和 DB_MAX_IDLE_CONNS 不能为负数")
+	}
+	if c.Database.MaxOpenConns > 0 && c.Database.MaxIdleConns > c.Database.MaxOpenConns {
+		problems = append(problems, "DB_MAX_IDLE_CONNS 不能大于 DB_MAX_OPEN_CONNS")
+	}
+	if c.JWT.Secret == "" {
+		problems = append(problems, "JWT_SECRET 不能为空")
+	}
+	if len(c.JWT.PublicKeyFiles) > 0 && c.JWT.PrivateKeyFile == "" {
+		problems = append(problems, "设置 JWT_PUBLIC_KEY_FILES 时 JWT_PRIVATE_KEY_FILE 不能为空")
+	}
+	if c.JWT.Issuer == "" {
+		problems = append(problems, "JWT_ISSUER 不能为空")
+	}
+	if c.JWT.Audience == "" {
+		problems = append(problems, "JWT_AUDIENCE 不能为空")
+	}
+	if c.JWT.Expire <= 0 {
+		problems = append(problems, "JWT_EXPIRE 必须大于 0")
+	}
+	if c.JWT.RefreshExpire <= c.JWT.Expire {
+		problems = append(problems, "JWT_REFRESH_EXPIRE 必须大于 JWT_EXPIRE")
+	}
+
+	switch c.Mail.Driver {
+	case MailDriverSMTP:
+		if c.Mail.SMTPHost == "" {
+			problems = append(problems, "MAIL_DRIVER=smtp 时 SMTP_HOST 不能为空")
+		}
+		if c.Mail.SMTPPort <= 0 || c.Mail.SMTPPort > 65535 {
+			problems = append(problems, fmt.Sprintf("SMTP_PORT 必须在 1-65535 之间，当前为 %d", c.Mail.SMTPPort))
+		}
+	case MailDriverFile:
+		if c.Mail.Dir == "" {
+			problems = append(problems, "MAIL_DRIVER=file 时 MAIL_DIR 不能为空")
+		}
+	case MailDriverLog:
+	default:
+		problems = append(problems, fmt.Sprintf("MAIL_DRIVER 仅支持 smtp/file/log，当前为 %q", c.Mail.Driver))
+	}
+	if c.Mail.From == "" {
+		problems = append(problems, "MAIL_FROM 不能为空")
+	}
+	if c.Account.FrontendURL == "" {
+		problems = append(problems, "FRONTEND_URL 不能为空")
+	}
+	if c.Account.PasswordResetExpire <= 0 {
+		problems = append(problems, "PASSWORD_RESET_EXPIRE 必须大于 0")
+	}
+	if c.Account.EmailVerifyExpire <= 0 {
+		problems = append(problems, "EMAIL_VERIFY_EXPIRE 必须大于 0")
+	}
+	if c.Account.TOTPIssuer == "" {
+		problems = append(problems, "TOTP_ISSUER 不能为空")
+	}
+	if c.Account.LoginMaxFailures <= 0 {
+		problems = append(problems, "LOGIN_MAX_FAILURES 必须大于 0")
+	}
+	if c.Account.LoginIPMaxFailures <= 0 {
+		problems = append(problems, "LOGIN_IP_MAX_FAILURES 必须大于 0")
+	}
+	if c.Account.LoginLockout <= 0 {
+		problems = append(problems, "LOGIN_LOCKOUT 必须大于 0")
+	}
+	if c.Account.LoginMaxLockout < c.Account.LoginLockout {
+		problems = append(problems, "LOGIN_MAX_LOCKOUT 不能小于 LOGIN_LOCKOUT")
+	}
+	if c.Account.PasswordMinLength < 6 {
+		problems = append(problems, "PASSWORD_MIN_LENGTH 不能小于 6")
+	}
+	if c.Account.PasswordMaxLength < c.Account.PasswordMinLength {
+		problems = append(problems, "PASSWORD_MAX_LENGTH 不能小于 PASSWORD_MIN_LENGTH")
+	}
+	switch c.Account.DeletionPolicy {
+	case DeletionAnonymize, DeletionCascade:
+	default:
+		problems = append(problems, fmt.Sprintf("ACCOUNT_DELETION_POLICY 仅支持 anonymize/cascade，当前为 %q", c.Account.DeletionPolicy))
+	}
+	if c.OIDC.Enabled() {
+		if c.OIDC.ClientID == "" {
+			problems = append(problems, "启用 OIDC 时 OIDC_CLIENT_ID 不能为空")
+		}
+		if c.OIDC.RedirectURL == "" {
+			problems = append(problems, "启用 OIDC 时 OIDC_REDIRECT_URL 不能为空")
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("配置校验失败: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// 服务监听地址，Host 为空时监听所有网卡
+func (s ServerConfig) Addr() string {
+	return net.JoinHostPort(s.Host, strconv.Itoa(s.Port))
+}
+
+// 数据库连接字符串，未设置端口时使用驱动默认端口
+func (d DatabaseConfig) DSN() string {
+	switch d.Driver {
+	case DriverPostgres:
+		port := d.Port
+		if port == 0 {
+			port = 5432
+		}
+		return fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s TimeZone=Local",
+			d.Host, port, d.User, d.Password, d.Name, d.SSLMode)
+	case DriverSQLite:
+		return d.Name
+	default:
+		port := d.Port
+		if port == 0 {
+			port = 3306
+		}
+		return fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=utf8mb4&parseTime=True&loc=Local",
+			d.User, d.Password, d.Host, port, d.Name)
+	}
+}
+
+// 是否为 SQLite 内存数据库
+func (d DatabaseConfig) InMemory() bool {
+	return d.Driver == DriverSQLite && (d.Name == ":memory:" || strings.Contains(d.Name, "mode=memory"))
+}
+
+// 读取配置文件
+func loadFile(path string, cfg *Config) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("读取配置文件 %s 失败: %w", path, err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, cfg)
+	case ".toml":
+		// go-toml 不支持将 "24h" 这类字符串解码为 time.Duration，
+		// 先解码为通用结构再交给 yaml 解码，两种格式共用同一套字段映射
+		var raw map[string]any
+		if err = toml.Unmarshal(data, &raw); err == nil {
+			if data, err = yaml.Marshal(raw); err == nil {
+				err = yaml.Unmarshal(data, cfg)
+			}
+		}
+	default:
+		return fmt.Errorf("不支持的配置文件格式: %s (仅支持 .yaml/.yml/.toml)", path)
+	}
+	if err != nil {
+		return fmt.Errorf("解析配置文件 %s 失败: %w", path, err)
+	}
+	return nil
+}
+
+// 加载 .env 文件，已存在的环境变量不会被覆盖
+func loadDotEnv(files []string) error {
+	if len(files) == 0 {
+		if _, err := os.Stat(".env"); err != nil {
+			return nil
+		}
+		files = []string{".env"}
+	}
+	if err := godotenv.Load(files...); err != nil {
+		return fmt.Errorf("加载 .env 文件失败: %w", err)
+	}
+	return nil
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// 根据 env 标签用环境变量覆盖配置
+func applyEnv(v reflect.Value) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := v.Field(i)
+		if field.Kind() == reflect.Struct {
+			if err := applyEnv(field); err != nil {
+				return err
+			}
+			continue
+		}
+
+		key := t.Field(i).Tag.Get("env")
+		if key == "" {
+			continue
+		}
+		raw, ok := os.LookupEnv(key)
+		if !ok {
+			continue
+		}
+		if err := setField(field, strings.TrimSpace(raw)); err != nil {
+			return fmt.Errorf("环境变量 %s=%q 无效: %w", key, raw, err)
+		}
+	}
+	return nil
+}
+
+func setField(field reflect.Value, raw string) error {
+	if field.Type() == durationType {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(d))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(raw)
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return err
+		}
+		field.SetInt(n)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Slice:
+		var items []string
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		field.Set(reflect.ValueOf(items))
+	default:
+		return errors.New("不支持的配置类型 " + field.Type().String())
+	}
+	return nil
+}
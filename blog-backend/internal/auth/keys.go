@@ -0,0 +1,156 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// RSA 签名密钥的最小长度
+const minRSABits = 2048
+
+// 公开的 JSON Web Key (RFC 7517)，供其他服务校验访问令牌
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	// RSA
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+	// OKP
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// 校验访问令牌使用的公钥，签名算法由密钥类型决定
+type verificationKey struct {
+	method jwt.SigningMethod
+	key    interface{}
+	jwk    *JWK
+}
+
+// 从 PEM 文件读取 RSA 或 Ed25519 私钥 (PKCS#8 或 PKCS#1)
+func LoadPrivateKey(path string) (crypto.Signer, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var key interface{}
+	switch block.Type {
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("%s: 不支持的私钥类型 %q", path, block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: 解析私钥失败: %w", path, err)
+	}
+
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("%s: 仅支持 RSA 和 Ed25519 私钥", path)
+	}
+	if _, err := newVerificationKey(signer.Public()); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return signer, nil
+}
+
+// 从 PEM 文件读取 RSA 或 Ed25519 公钥 (PKIX 或 PKCS#1)
+func LoadPublicKey(path string) (crypto.PublicKey, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var key interface{}
+	switch block.Type {
+	case "PUBLIC KEY":
+		key, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		key, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("%s: 不支持的公钥类型 %q", path, block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: 解析公钥失败: %w", path, err)
+	}
+	if _, err := newVerificationKey(key); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return key, nil
+}
+
+func readPEM(path string) (*pem.Block, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%s: 不是 PEM 格式", path)
+	}
+	return block, nil
+}
+
+// 根据公钥类型确定签名算法，kid 为公钥的 JWK 指纹 (RFC 7638)
+func newVerificationKey(pub crypto.PublicKey) (*verificationKey, error) {
+	var (
+		method jwt.SigningMethod
+		jwk    *JWK
+		// 计算指纹的必需字段，按字典序排列
+		members interface{}
+	)
+	switch key := pub.(type) {
+	case *rsa.PublicKey:
+		if key.N.BitLen() < minRSABits {
+			return nil, fmt.Errorf("RSA 密钥长度不能小于 %d 位", minRSABits)
+		}
+		method = jwt.SigningMethodRS256
+		jwk = &JWK{
+			Kty: "RSA",
+			N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}
+		members = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{jwk.E, jwk.Kty, jwk.N}
+	case ed25519.PublicKey:
+		method = jwt.SigningMethodEdDSA
+		jwk = &JWK{Kty: "OKP", Crv: "Ed25519", X: base64.RawURLEncoding.EncodeToString(key)}
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+		}{jwk.Crv, jwk.Kty, jwk.X}
+	default:
+		return nil, errors.New("仅支持 RSA 和 Ed25519 密钥")
+	}
+
+	data, err := json.Marshal(members)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(data)
+	jwk.Kid = base64.RawURLEncoding.EncodeToString(sum[:])
+	jwk.Use = "sig"
+	jwk.Alg = method.Alg()
+	return &verificationKey{method: method, key: pub, jwk: jwk}, nil
+}
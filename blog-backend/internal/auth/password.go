@@ -0,0 +1,164 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// 密码哈希算法，哈希结果中包含算法标识和参数
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	// 是否为该算法生成的哈希
+	Identify(encoded string) bool
+	// 校验密码，格式错误时返回 false
+	Verify(encoded, password string) bool
+	// 哈希使用的参数是否弱于当前参数
+	NeedsRehash(encoded string) bool
+}
+
+var (
+	// 新密码使用的算法
+	passwordHasher PasswordHasher = Argon2idHasher{Memory: 19 * 1024, Iterations: 2, Parallelism: 1}
+	// 仍可校验的旧算法，校验成功后应重新哈希
+	legacyHashers = []PasswordHasher{BcryptHasher{}}
+)
+
+// 加密密码
+func HashPassword(password string) (string, error) {
+	return passwordHasher.Hash(password)
+}
+
+// 校验密码是否与加密后的密码匹配，rehash 表示哈希使用了旧算法或旧参数，应在校验成功后重新加密保存
+func CheckPassword(hashed, password string) (ok bool, rehash bool) {
+	if passwordHasher.Identify(hashed) {
+		ok = passwordHasher.Verify(hashed, password)
+		return ok, ok && passwordHasher.NeedsRehash(hashed)
+	}
+	for _, h := range legacyHashers {
+		if h.Identify(hashed) {
+			ok = h.Verify(hashed, password)
+			return ok, ok
+		}
+	}
+	return false, false
+}
+
+const (
+	argon2idPrefix = "$argon2id$"
+	argon2SaltLen  = 16
+	argon2KeyLen   = 32
+)
+
+// argon2id 哈希，格式为 $argon2id$v=19$m=<KiB>,t=<迭代次数>,p=<并行度>$<盐>$<哈希>
+type Argon2idHasher struct {
+	// 内存开销，单位 KiB
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+}
+
+type argon2idHash struct {
+	params Argon2idHasher
+	salt   []byte
+	key    []byte
+}
+
+func (h Argon2idHasher) Hash(password string) (string, error) {
+	salt := make([]byte, argon2SaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(password), salt, h.Iterations, h.Memory, h.Parallelism, argon2KeyLen)
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s", argon2idPrefix, argon2.Version, h.Memory, h.Iterations, h.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+func (h Argon2idHasher) Identify(encoded string) bool {
+	return strings.HasPrefix(encoded, argon2idPrefix)
+}
+
+func (h Argon2idHasher) Verify(encoded, password string) bool {
+	parsed, err := parseArgon2id(encoded)
+	if err != nil {
+		return false
+	}
+	p := parsed.params
+	key := argon2.IDKey([]byte(password), parsed.salt, p.Iterations, p.Memory, p.Parallelism, uint32(len(parsed.key)))
+	return subtle.ConstantTimeCompare(key, parsed.key) == 1
+}
+
+func (h Argon2idHasher) NeedsRehash(encoded string) bool {
+	parsed, err := parseArgon2id(encoded)
+	if err != nil {
+		return true
+	}
+	p := parsed.params
+	return p.Memory < h.Memory || p.Iterations < h.Iterations || p.Parallelism < h.Parallelism || len(parsed.key) < argon2KeyLen
+}
+
+var errInvalidArgon2id = errors.New("无效的 argon2id 哈希")
+
+func parseArgon2id(encoded string) (*argon2idHash, error) {
+	// "", "argon2id", "v=19", "m=...,t=...,p=...", 盐, 哈希
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return nil, errInvalidArgon2id
+	}
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return nil, errInvalidArgon2id
+	}
+	var h argon2idHash
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &h.params.Memory, &h.params.Iterations, &h.params.Parallelism); err != nil {
+		return nil, errInvalidArgon2id
+	}
+	if h.params.Memory == 0 || h.params.Iterations == 0 || h.params.Parallelism == 0 {
+		return nil, errInvalidArgon2id
+	}
+	var err error
+	if h.salt, err = base64.RawStdEncoding.DecodeString(parts[4]); err != nil {
+		return nil, errInvalidArgon2id
+	}
+	if h.key, err = base64.RawStdEncoding.DecodeString(parts[5]); err != nil || len(h.key) == 0 {
+		return nil, errInvalidArgon2id
+	}
+	return &h, nil
+}
+
+// bcrypt 哈希，只用于校验旧密码；bcrypt 只使用密码的前 72 字节
+type BcryptHasher struct {
+	Cost int
+}
+
+func (h BcryptHasher) Hash(password string) (string, error) {
+	cost := h.Cost
+	if cost == 0 {
+		cost = bcrypt.DefaultCost
+	}
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), cost)
+	if err != nil {
+		return "", err
+	}
+	return string(hashed), nil
+}
+
+func (h BcryptHasher) Identify(encoded string) bool {
+	_, err := bcrypt.Cost([]byte(encoded))
+	return err == nil
+}
+
+func (h BcryptHasher) Verify(encoded, password string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password)) == nil
+}
+
+func (h BcryptHasher) NeedsRehash(encoded string) bool {
+	cost, err := bcrypt.Cost([]byte(encoded))
+	return err != nil || cost < h.Cost
+}
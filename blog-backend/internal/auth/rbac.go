@@ -0,0 +1,40 @@
+package auth
+
+import (
+	"blog-backend/internal/models"
+)
+
+// 操作权限
+type Permission string
+
+const (
+	PermCreateArticle    Permission = "articles:create"
+	PermEditAnyArticle   Permission = "articles:edit_any"
+	PermDeleteAnyArticle Permission = "articles:delete_any"
+	PermManageTaxonomy   Permission = "taxonomy:manage"
+	PermCreateComment    Permission = "comments:create"
+	PermDeleteAnyComment Permission = "comments:delete_any"
+	PermManageUsers      Permission = "users:manage"
+	PermReadAuditLog     Permission = "audit:read"
+)
+
+var rolePermissions = map[string][]Permission{
+	models.RoleReader: {PermCreateComment},
+	models.RoleAuthor: {PermCreateComment, PermCreateArticle},
+	models.RoleEditor: {PermCreateComment, PermCreateArticle, PermEditAnyArticle, PermManageTaxonomy},
+	models.RoleAdmin: {
+		PermCreateComment, PermCreateArticle,
+		PermEditAnyArticle, PermDeleteAnyArticle, PermDeleteAnyComment, PermManageTaxonomy,
+		PermManageUsers, PermReadAuditLog,
+	},
+}
+
+// 角色是否拥有指定权限，未知角色没有任何权限
+func Can(role string, perm Permission) bool {
+	for _, p := range rolePermissions[role] {
+		if p == perm {
+			return true
+		}
+	}
+	return false
+}
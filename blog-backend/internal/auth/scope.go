@@ -0,0 +1,43 @@
+package auth
+
+import (
+	"strings"
+)
+
+// 个人访问令牌的权限范围
+type Scope string
+
+const (
+	ScopeProfileRead   Scope = "profile:read"
+	ScopeArticlesWrite Scope = "articles:write"
+	ScopeCommentsWrite Scope = "comments:write"
+)
+
+// 全部可用的权限范围
+var Scopes = []Scope{ScopeProfileRead, ScopeArticlesWrite, ScopeCommentsWrite}
+
+// 个人访问令牌前缀，用于和 JWT 区分
+const PersonalTokenPrefix = "blogpat_"
+
+func ValidScope(scope string) bool {
+	for _, s := range Scopes {
+		if string(s) == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// 生成个人访问令牌，返回明文和用于存储的摘要
+func NewPersonalToken() (string, string, error) {
+	plain, _, err := NewOpaqueToken()
+	if err != nil {
+		return "", "", err
+	}
+	plain = PersonalTokenPrefix + plain
+	return plain, HashToken(plain), nil
+}
+
+func IsPersonalToken(token string) bool {
+	return strings.HasPrefix(token, PersonalTokenPrefix)
+}
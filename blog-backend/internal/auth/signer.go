@@ -0,0 +1,66 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// 签名链接令牌，内容和过期时间都包含在令牌中，无需保存到数据库
+type Signer struct {
+	key []byte
+}
+
+type signedPayload struct {
+	Purpose   string `json:"p"`
+	Subject   string `json:"s"`
+	ExpiresAt int64  `json:"e"`
+}
+
+// 签名密钥由 secret 派生，与访问令牌的签名密钥相互独立
+func NewSigner(secret string) *Signer {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("blog-backend signed links"))
+	return &Signer{key: mac.Sum(nil)}
+}
+
+// 为 subject 生成用途为 purpose 的签名令牌
+func (s *Signer) Sign(purpose, subject string, expiresAt time.Time) string {
+	payload, _ := json.Marshal(signedPayload{Purpose: purpose, Subject: subject, ExpiresAt: expiresAt.Unix()})
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(s.mac(encoded))
+}
+
+// 校验签名、用途和过期时间，返回签名时的 subject
+func (s *Signer) Verify(purpose, token string, now time.Time) (string, error) {
+	encoded, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return "", ErrInvalidToken
+	}
+	given, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || !hmac.Equal(given, s.mac(encoded)) {
+		return "", ErrInvalidToken
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", ErrInvalidToken
+	}
+	var payload signedPayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return "", ErrInvalidToken
+	}
+	if payload.Purpose != purpose || now.Unix() >= payload.ExpiresAt {
+		return "", ErrInvalidToken
+	}
+	return payload.Subject, nil
+}
+
+func (s *Signer) mac(data string) []byte {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
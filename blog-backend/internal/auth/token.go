@@ -0,0 +1,190 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+
+	"blog-backend/config"
+	"blog-backend/internal/models"
+)
+
+var ErrInvalidToken = errors.New("无效的认证令牌")
+
+// 访问令牌中的声明
+type Claims struct {
+	UserID    uint
+	SessionID uint
+	Role      string
+	// 个人访问令牌的ID和权限范围，JWT 为 0 和 nil
+	TokenID uint
+	Scopes  []string
+}
+
+// 是否为个人访问令牌
+func (c *Claims) Personal() bool {
+	return c.TokenID != 0
+}
+
+// 是否允许访问需要指定权限范围的接口，JWT 不受权限范围限制
+func (c *Claims) HasScope(scope Scope) bool {
+	if !c.Personal() {
+		return true
+	}
+	for _, s := range c.Scopes {
+		if s == string(scope) {
+			return true
+		}
+	}
+	return false
+}
+
+// JWT 签发与校验
+//
+// 未配置私钥时使用 JWT_SECRET 进行 HS256 签名；配置私钥后使用 RS256 或 EdDSA 签名并在头部写入 kid，
+// 校验时按 kid 选择公钥，令牌的签名算法必须与公钥一致，签发方和受众必须与配置一致。
+type TokenManager struct {
+	method   jwt.SigningMethod
+	keyID    string
+	signKey  interface{}
+	keys     map[string]*verificationKey
+	jwks     []JWK
+	issuer   string
+	audience string
+	expire   time.Duration
+}
+
+func NewTokenManager(cfg config.JWTConfig) (*TokenManager, error) {
+	m := &TokenManager{
+		keys:     make(map[string]*verificationKey),
+		jwks:     []JWK{},
+		issuer:   cfg.Issuer,
+		audience: cfg.Audience,
+		expire:   cfg.Expire,
+	}
+	if cfg.PrivateKeyFile == "" {
+		m.method = jwt.SigningMethodHS256
+		m.signKey = []byte(cfg.Secret)
+		m.keys[""] = &verificationKey{method: jwt.SigningMethodHS256, key: []byte(cfg.Secret)}
+		return m, nil
+	}
+
+	private, err := LoadPrivateKey(cfg.PrivateKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("读取 JWT 签名私钥失败: %w", err)
+	}
+	current, err := newVerificationKey(private.Public())
+	if err != nil {
+		return nil, err
+	}
+	m.method = current.method
+	m.keyID = current.jwk.Kid
+	m.signKey = private
+	m.keys[current.jwk.Kid] = current
+	m.jwks = append(m.jwks, *current.jwk)
+
+	// 轮换期间仍然接受的公钥
+	for _, path := range cfg.PublicKeyFiles {
+		pub, err := LoadPublicKey(path)
+		if err != nil {
+			return nil, fmt.Errorf("读取 JWT 校验公钥失败: %w", err)
+		}
+		key, err := newVerificationKey(pub)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := m.keys[key.jwk.Kid]; !ok {
+			m.keys[key.jwk.Kid] = key
+			m.jwks = append(m.jwks, *key.jwk)
+		}
+	}
+	return m, nil
+}
+
+// 访问令牌有效期
+func (m *TokenManager) Expire() time.Duration {
+	return m.expire
+}
+
+// 全部校验公钥，签名密钥在前，HS256 时为空
+func (m *TokenManager) JWKS() []JWK {
+	return m.jwks
+}
+
+// 生成访问令牌，sid 为所属登录会话，role 为签发时的用户角色
+func (m *TokenManager) Generate(userID, sessionID uint, role string) (string, error) {
+	now := time.Now()
+	token := jwt.NewWithClaims(m.method, jwt.MapClaims{
+		"iss":     m.issuer,
+		"aud":     m.audience,
+		"user_id": userID,
+		"sid":     sessionID,
+		"role":    role,
+		"iat":     now.Unix(),
+		"exp":     now.Add(m.expire).Unix(),
+	})
+	if m.keyID != "" {
+		token.Header["kid"] = m.keyID
+	}
+
+	return token.SignedString(m.signKey)
+}
+
+// 解析访问令牌
+func (m *TokenManager) Parse(tokenString string) (*Claims, error) {
+	claims := jwt.MapClaims{}
+	parser := jwt.NewParser(jwt.WithValidMethods([]string{m.method.Alg()}))
+	token, err := parser.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, ok := m.keys[kid]
+		// 签名算法由公钥决定，不信任令牌头部声明的算法
+		if !ok || token.Method.Alg() != key.method.Alg() {
+			return nil, ErrInvalidToken
+		}
+		return key.key, nil
+	})
+	if err != nil || !token.Valid {
+		return nil, ErrInvalidToken
+	}
+	if !claims.VerifyIssuer(m.issuer, true) || !claims.VerifyAudience(m.audience, true) ||
+		!claims.VerifyExpiresAt(time.Now().Unix(), true) {
+		return nil, ErrInvalidToken
+	}
+
+	userID, ok := claims["user_id"].(float64)
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+	// 没有会话的令牌无法撤销，不再接受
+	sessionID, ok := claims["sid"].(float64)
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+	role, ok := claims["role"].(string)
+	if !ok || !models.ValidRole(role) {
+		return nil, ErrInvalidToken
+	}
+	return &Claims{UserID: uint(userID), SessionID: uint(sessionID), Role: role}, nil
+}
+
+// 生成随机的不透明令牌，返回明文和用于存储的摘要
+func NewOpaqueToken() (string, string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+	plain := base64.RawURLEncoding.EncodeToString(buf)
+	return plain, HashToken(plain), nil
+}
+
+// 不透明令牌的 SHA-256 摘要
+func HashToken(plain string) string {
+	sum := sha256.Sum256([]byte(plain))
+	return hex.EncodeToString(sum[:])
+}
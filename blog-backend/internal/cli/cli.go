@@ -0,0 +1,135 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"gorm.io/gorm"
+
+	"blog-backend/config"
+	"blog-backend/internal/database"
+)
+
+const usage = `用法: blog-backend [-config 文件] <命令> [参数]
+
+命令:
+  serve                         启动 HTTP 服务 (默认)
+  migrate up|down [N]|status    管理数据库迁移
+  seed                          生成测试用户、文章和评论
+  user create|promote|reset-password|unlock
+                                管理用户
+  export                        导出全部数据为 JSON
+  import                        从 JSON 导入数据
+
+使用 "blog-backend <命令> -h" 查看命令参数。
+`
+
+// 参数错误，由 Run 打印用法
+var errUsage = errors.New("参数错误")
+
+// 命令运行环境
+type App struct {
+	Config *config.Config
+	Stdout io.Writer
+	Stderr io.Writer
+
+	db *gorm.DB
+}
+
+// 打开数据库连接，同一次运行中只打开一次
+func (a *App) DB() (*gorm.DB, error) {
+	if a.db != nil {
+		return a.db, nil
+	}
+	db, err := database.Open(a.Config.Database)
+	if err != nil {
+		return nil, fmt.Errorf("无法连接到数据库: %w", err)
+	}
+	a.db = db
+	return db, nil
+}
+
+// 关闭数据库连接
+func (a *App) Close() error {
+	if a.db == nil {
+		return nil
+	}
+	err := database.Close(a.db)
+	a.db = nil
+	return err
+}
+
+func (a *App) printf(format string, args ...interface{}) {
+	fmt.Fprintf(a.Stdout, format, args...)
+}
+
+type command func(app *App, args []string) error
+
+var commands = map[string]command{
+	"serve":   runServe,
+	"migrate": runMigrate,
+	"seed":    runSeed,
+	"user":    runUser,
+	"export":  runExport,
+	"import":  runImport,
+}
+
+// 解析命令行并执行对应命令，返回进程退出码
+func Run(args []string) int {
+	flags := flag.NewFlagSet("blog-backend", flag.ContinueOnError)
+	configFile := flags.String("config", os.Getenv("CONFIG_FILE"), "YAML/TOML 配置文件路径")
+	flags.Usage = func() { fmt.Fprint(flags.Output(), usage) }
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+
+	name, rest := "serve", []string(nil)
+	if flags.NArg() > 0 {
+		name, rest = flags.Arg(0), flags.Args()[1:]
+	}
+	cmd, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "未知命令: %s\n\n", name)
+		flags.Usage()
+		return 2
+	}
+
+	cfg, err := config.Load(*configFile)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "加载配置失败:", err)
+		return 1
+	}
+
+	app := &App{Config: cfg, Stdout: os.Stdout, Stderr: os.Stderr}
+	defer app.Close()
+
+	if err := cmd(app, rest); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		if errors.Is(err, errUsage) {
+			fmt.Fprintln(os.Stderr, err)
+			flags.Usage()
+			return 2
+		}
+		fmt.Fprintln(os.Stderr, "错误:", err)
+		return 1
+	}
+	return 0
+}
+
+// 创建子命令参数解析器
+func newFlagSet(name, usage string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "用法: blog-backend %s\n\n参数:\n", usage)
+		flags.PrintDefaults()
+	}
+	return flags
+}
@@ -0,0 +1,112 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"gorm.io/gorm"
+
+	"blog-backend/internal/migrations"
+	"blog-backend/internal/models"
+	"blog-backend/internal/repository"
+	"blog-backend/internal/slug"
+)
+
+// 管理数据库迁移
+func runMigrate(app *App, args []string) error {
+	flags := newFlagSet("migrate", "migrate up | down [N] | status")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		return fmt.Errorf("%w: migrate 需要指定 up、down 或 status", errUsage)
+	}
+
+	db, err := app.DB()
+	if err != nil {
+		return err
+	}
+	migrator := migrations.New(db)
+
+	switch flags.Arg(0) {
+	case "up":
+		done, err := migrator.Up()
+		for _, m := range done {
+			app.printf("已执行 %d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(done) == 0 {
+			app.printf("数据库已是最新版本\n")
+		}
+		n, err := regenerateSlugs(db)
+		if err != nil {
+			return fmt.Errorf("生成文章 slug 失败: %w", err)
+		}
+		if n > 0 {
+			app.printf("已为 %d 篇文章生成 slug\n", n)
+		}
+	case "down":
+		steps := 1
+		if flags.NArg() > 1 {
+			if steps, err = strconv.Atoi(flags.Arg(1)); err != nil || steps <= 0 {
+				return fmt.Errorf("%w: 回滚数量必须是正整数", errUsage)
+			}
+		}
+		done, err := migrator.Down(steps)
+		for _, m := range done {
+			app.printf("已回滚 %d_%s\n", m.Version, m.Name)
+		}
+		return err
+	case "status":
+		statuses, err := migrator.Status()
+		if err != nil {
+			return err
+		}
+		for _, s := range statuses {
+			state := "未执行"
+			if s.Applied {
+				state = "已执行 " + s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			app.printf("%6d  %-40s %s\n", s.Version, s.Name, state)
+		}
+	default:
+		return fmt.Errorf("%w: 未知的 migrate 子命令 %s", errUsage, flags.Arg(0))
+	}
+	return nil
+}
+
+// 将迁移 0015 填入的临时 slug (article-<id>) 替换为由标题生成的 slug，返回替换的文章数
+//
+// 标题本身生成 article-<id> 的文章重新生成后保持不变，重复执行没有副作用。
+func regenerateSlugs(db *gorm.DB) (int, error) {
+	ctx := context.Background()
+	var articles []models.Article
+	if err := db.Select("id", "title", "slug").Where("slug LIKE ?", "article-%").Order("id").Find(&articles).Error; err != nil {
+		return 0, err
+	}
+
+	repo := repository.NewArticleRepository(db)
+	n := 0
+	for _, a := range articles {
+		if a.Slug != fmt.Sprintf("article-%d", a.ID) {
+			continue
+		}
+		s, err := slug.Unique(slug.Make(a.Title), func(candidate string) (bool, error) {
+			return repo.SlugTaken(ctx, candidate, a.ID)
+		})
+		if err != nil {
+			return n, err
+		}
+		if s == a.Slug {
+			continue
+		}
+		if err := db.Model(&models.Article{}).Where("id = ?", a.ID).UpdateColumn("slug", s).Error; err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
@@ -0,0 +1,169 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"blog-backend/internal/auth"
+	"blog-backend/internal/models"
+	"blog-backend/internal/repository"
+	"blog-backend/internal/slug"
+)
+
+var (
+	seedTopics = []string{"Go", "React", "MySQL", "Docker", "Kubernetes", "Redis", "Gin", "GORM", "TypeScript", "Linux"}
+	seedTitles = []string{
+		"%s 入门指南",
+		"深入理解 %s",
+		"%s 实战笔记",
+		"%s 常见问题汇总",
+		"用 %s 搭建个人博客",
+		"%s 性能优化技巧",
+	}
+	seedSentences = []string{
+		"这篇文章记录了我在学习过程中遇到的问题和解决方法。",
+		"首先需要准备好开发环境，并确认依赖版本一致。",
+		"官方文档是最好的学习资料，遇到问题时优先查阅。",
+		"实际项目中还需要考虑错误处理和日志记录。",
+		"通过一个完整的例子可以更直观地理解其中的原理。",
+		"最后总结一下本文的要点，欢迎在评论区交流。",
+	}
+	seedComments = []string{
+		"写得很清楚，收藏了！",
+		"请问文中的示例代码有完整版本吗？",
+		"感谢分享，解决了我的问题。",
+		"我在实践中遇到了不同的情况，可以交流一下。",
+		"期待下一篇。",
+	}
+)
+
+// 生成测试数据
+func runSeed(app *App, args []string) error {
+	flags := newFlagSet("seed", "seed [-users N] [-articles N] [-comments N] [-password 密码]")
+	userCount := flags.Int("users", 5, "生成的用户数量")
+	articleCount := flags.Int("articles", 20, "生成的文章数量")
+	commentCount := flags.Int("comments", 50, "生成的评论数量")
+	password := flags.String("password", "password123", "生成用户的登录密码")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *userCount <= 0 || *articleCount < 0 || *commentCount < 0 {
+		return fmt.Errorf("%w: 至少生成 1 个用户，文章和评论数量不能为负数", errUsage)
+	}
+
+	db, err := app.DB()
+	if err != nil {
+		return err
+	}
+
+	hashed, err := auth.HashPassword(*password)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	err = db.Transaction(func(tx *gorm.DB) error {
+		users := make([]models.User, *userCount)
+		for i := range users {
+			name := fmt.Sprintf("user_%06x", rand.IntN(1<<24))
+			users[i] = models.User{
+				Username: name,
+				Email:    name + "@example.com",
+				Password: hashed,
+				Role:     models.RoleAuthor,
+				// 测试用户直接视为已验证邮箱
+				VerifiedAt: &now,
+			}
+		}
+		if err := tx.Create(&users).Error; err != nil {
+			return fmt.Errorf("创建用户失败: %w", err)
+		}
+
+		// 生成的标题经常重复，slug 需要同时避开数据库中和本批次已使用的
+		articleRepo := repository.NewArticleRepository(tx)
+		usedSlugs := make(map[string]bool)
+		slugTaken := func(s string) (bool, error) {
+			if usedSlugs[s] {
+				return true, nil
+			}
+			return articleRepo.SlugTaken(context.Background(), s, 0)
+		}
+
+		// 以文章主题作为标签
+		tags := make(map[string]models.Tag, len(seedTopics))
+		for _, topic := range seedTopics {
+			tag := models.Tag{Name: topic, Slug: slug.Make(topic)}
+			if err := tx.Where("slug = ?", tag.Slug).FirstOrCreate(&tag).Error; err != nil {
+				return fmt.Errorf("创建标签失败: %w", err)
+			}
+			tags[topic] = tag
+		}
+
+		articles := make([]models.Article, *articleCount)
+		for i := range articles {
+			paragraphs := make([]string, 2+rand.IntN(3))
+			for j := range paragraphs {
+				paragraphs[j] = pick(seedSentences) + pick(seedSentences)
+			}
+			topic := pick(seedTopics)
+			title := fmt.Sprintf(pick(seedTitles), topic)
+			articleSlug, err := slug.Unique(slug.Make(title), slugTaken)
+			if err != nil {
+				return err
+			}
+			usedSlugs[articleSlug] = true
+			articles[i] = models.Article{
+				Title:       title,
+				Slug:        articleSlug,
+				Content:     strings.Join(paragraphs, "\n\n"),
+				AuthorID:    pick(users).ID,
+				Views:       rand.IntN(500),
+				Status:      models.ArticlePublished,
+				PublishedAt: &now,
+				Tags:        []models.Tag{tags[topic]},
+			}
+		}
+		if len(articles) > 0 {
+			if err := tx.Omit("Author", "Tags.*").Create(&articles).Error; err != nil {
+				return fmt.Errorf("创建文章失败: %w", err)
+			}
+		}
+
+		if len(articles) == 0 {
+			return nil
+		}
+		comments := make([]models.Comment, *commentCount)
+		for i := range comments {
+			comments[i] = models.Comment{
+				Content:   pick(seedComments),
+				ArticleID: pick(articles).ID,
+				AuthorID:  pick(users).ID,
+			}
+		}
+		if len(comments) > 0 {
+			if err := tx.Omit("Article", "Author").Create(&comments).Error; err != nil {
+				return fmt.Errorf("创建评论失败: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	commentsCreated := *commentCount
+	if *articleCount == 0 {
+		commentsCreated = 0
+	}
+	app.printf("已生成 %d 个用户、%d 篇文章、%d 条评论，用户密码为 %s\n", *userCount, *articleCount, commentsCreated, *password)
+	return nil
+}
+
+func pick[T any](items []T) T {
+	return items[rand.IntN(len(items))]
+}
@@ -0,0 +1,132 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"blog-backend/config"
+	"blog-backend/internal/middleware"
+	"blog-backend/internal/migrations"
+	"blog-backend/internal/repository"
+	"blog-backend/internal/routes"
+	"blog-backend/internal/services"
+)
+
+// 启动 HTTP 服务，收到 SIGINT/SIGTERM 后停止接收新连接并等待进行中的请求完成
+func runServe(app *App, args []string) error {
+	flags := newFlagSet("serve", "serve")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	db, err := app.DB()
+	if err != nil {
+		return err
+	}
+
+	// 检查数据库结构版本
+	migrator := migrations.New(db)
+	if app.Config.Database.AutoMigrate {
+		if _, err := migrator.Up(); err != nil {
+			return fmt.Errorf("数据库迁移失败: %w", err)
+		}
+		if _, err := regenerateSlugs(db); err != nil {
+			return fmt.Errorf("生成文章 slug 失败: %w", err)
+		}
+	}
+	if err := migrator.EnsureUpToDate(); err != nil {
+		return fmt.Errorf("%w，执行 blog-backend migrate up 后再启动服务", err)
+	}
+
+	// 设置路由
+	r := gin.Default()
+	// 未配置可信代理时不信任 X-Forwarded-For，避免伪造客户端 IP 绕过按 IP 的登录限制
+	if err := r.SetTrustedProxies(app.Config.Server.TrustedProxies); err != nil {
+		return fmt.Errorf("可信代理配置无效: %w", err)
+	}
+	// 添加 CORS 中间件
+	r.Use(middleware.CORSMiddleware(app.Config.Server.AllowedOrigins))
+	if err := routes.SetupRoutes(r, db, app.Config); err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	// 退出前等待进行中的后台任务完成，避免连接池关闭时任务仍在执行
+	schedulerDone := make(chan struct{})
+	defer func() {
+		stop()
+		<-schedulerDone
+	}()
+	go func() {
+		defer close(schedulerDone)
+		publisher := services.NewScheduledPublisher(repository.NewArticleRepository(db))
+		runScheduledPublisher(ctx, publisher, app.Config.Scheduler.PublishInterval)
+	}()
+
+	srv := newHTTPServer(app.Config.Server, r)
+	errCh := make(chan error, 1)
+	go func() {
+		log.Printf("服务已启动，监听 %s", srv.Addr)
+		errCh <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errCh:
+		if !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("服务启动失败: %w", err)
+		}
+		return nil
+	case <-ctx.Done():
+	}
+	stop()
+
+	log.Printf("收到退出信号，等待进行中的请求完成 (最长 %s)", app.Config.Server.ShutdownTimeout)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), app.Config.Server.ShutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("服务关闭超时: %w", err)
+	}
+
+	// 连接池在 Run 返回前由 App.Close 关闭
+	log.Println("服务已关闭")
+	return nil
+}
+
+// 周期性发布到期的定时文章，启动时立即执行一次，ctx 取消后返回；interval 为 0 时不执行
+func runScheduledPublisher(ctx context.Context, publisher *services.ScheduledPublisher, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := publisher.PublishDue(ctx, time.Now()); err != nil && ctx.Err() == nil {
+			log.Printf("定时发布失败: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func newHTTPServer(cfg config.ServerConfig, handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              cfg.Addr(),
+		Handler:           handler,
+		ReadTimeout:       cfg.ReadTimeout,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+		MaxHeaderBytes:    cfg.MaxHeaderBytes,
+	}
+}
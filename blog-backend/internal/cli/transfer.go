@@ -0,0 +1,351 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"gorm.io/gorm"
+
+	"blog-backend/config"
+	"blog-backend/internal/models"
+	"blog-backend/internal/slug"
+)
+
+// 导出文件格式版本，字段不兼容变更时递增
+//
+// 版本 2 增加了 users.verified_at，导入版本 1 的文件时视所有用户为已验证。
+// 版本 3 增加了 articles.status 和 articles.published_at，导入更早的文件时视所有文章在创建时发布。
+// 版本 4 增加了 articles.publish_at。
+// 版本 5 增加了 articles.slug 和 article_slugs，导入更早的文件时由标题生成 slug。
+// 版本 6 增加了 tags、article_tags、categories 和 articles.category_id。
+// 版本 7 增加了 series 和 series_articles。
+// 版本 8 增加了 users.totp_secret、users.totp_enabled_at、users.totp_last_step 和 users.deleted_at，
+// 导入更早的文件时按匿名化后的邮箱识别已注销的账号。
+const dumpVersion = 8
+
+type dump struct {
+	Version    int           `json:"version"`
+	ExportedAt time.Time     `json:"exported_at"`
+	Users      []dumpUser    `json:"users"`
+	Articles   []dumpArticle `json:"articles"`
+	Comments   []dumpComment `json:"comments"`
+
+	ArticleSlugs   []dumpArticleSlug   `json:"article_slugs"`
+	Tags           []dumpTag           `json:"tags"`
+	ArticleTags    []dumpArticleTag    `json:"article_tags"`
+	Categories     []dumpCategory      `json:"categories"`
+	Series         []dumpSeries        `json:"series"`
+	SeriesArticles []dumpSeriesArticle `json:"series_articles"`
+}
+
+type dumpUser struct {
+	ID        uint      `json:"id"`
+	Username  string    `json:"username"`
+	Email     string    `json:"email"`
+	Password  string    `json:"password_hash"`
+	Avatar    string    `json:"avatar"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	VerifiedAt *time.Time `json:"verified_at"`
+
+	// 两步验证密钥以明文导出，导出文件需与密码哈希一样妥善保管
+	TOTPSecret    string     `gorm:"column:totp_secret" json:"totp_secret"`
+	TOTPEnabledAt *time.Time `gorm:"column:totp_enabled_at" json:"totp_enabled_at"`
+	TOTPLastStep  int64      `gorm:"column:totp_last_step" json:"totp_last_step"`
+
+	DeletedAt *time.Time `json:"deleted_at"`
+}
+
+type dumpArticle struct {
+	ID        uint      `json:"id"`
+	Title     string    `json:"title"`
+	Content   string    `json:"content"`
+	AuthorID  uint      `json:"author_id"`
+	Views     int       `json:"views"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	Status      string     `json:"status"`
+	PublishedAt *time.Time `json:"published_at"`
+	PublishAt   *time.Time `json:"publish_at"`
+	Slug        string     `json:"slug"`
+	CategoryID  *uint      `json:"category_id"`
+}
+
+type dumpArticleSlug struct {
+	ID        uint      `json:"id"`
+	ArticleID uint      `json:"article_id"`
+	Slug      string    `json:"slug"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type dumpTag struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	Slug      string    `json:"slug"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type dumpArticleTag struct {
+	ArticleID uint `json:"article_id"`
+	TagID     uint `json:"tag_id"`
+}
+
+type dumpCategory struct {
+	ID          uint      `json:"id"`
+	Name        string    `json:"name"`
+	Slug        string    `json:"slug"`
+	Description string    `json:"description"`
+	ParentID    *uint     `json:"parent_id"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type dumpSeries struct {
+	ID          uint      `json:"id"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	AuthorID    uint      `json:"author_id"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type dumpSeriesArticle struct {
+	ArticleID uint `json:"article_id"`
+	SeriesID  uint `json:"series_id"`
+	Position  int  `json:"position"`
+}
+
+type dumpComment struct {
+	ID        uint      `json:"id"`
+	Content   string    `json:"content"`
+	ArticleID uint      `json:"article_id"`
+	AuthorID  uint      `json:"author_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// 按依赖顺序排列的表
+var dumpTables = []string{"users", "categories", "tags", "series", "articles", "article_slugs", "article_tags", "series_articles", "comments"}
+
+// 没有自增ID的关联表
+var dumpJoinTables = map[string]bool{"article_tags": true, "series_articles": true}
+
+// 导出全部数据
+func runExport(app *App, args []string) error {
+	flags := newFlagSet("export", "export [-o 文件]")
+	output := flags.String("o", "", "输出文件，为空时输出到标准输出")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	db, err := app.DB()
+	if err != nil {
+		return err
+	}
+
+	data := dump{Version: dumpVersion, ExportedAt: time.Now()}
+	if err := db.Table("users").Order("id").Find(&data.Users).Error; err != nil {
+		return err
+	}
+	if err := db.Table("articles").Order("id").Find(&data.Articles).Error; err != nil {
+		return err
+	}
+	if err := db.Table("comments").Order("id").Find(&data.Comments).Error; err != nil {
+		return err
+	}
+	if err := db.Table("article_slugs").Order("id").Find(&data.ArticleSlugs).Error; err != nil {
+		return err
+	}
+	if err := db.Table("tags").Order("id").Find(&data.Tags).Error; err != nil {
+		return err
+	}
+	if err := db.Table("article_tags").Order("article_id, tag_id").Find(&data.ArticleTags).Error; err != nil {
+		return err
+	}
+	if err := db.Table("categories").Order("id").Find(&data.Categories).Error; err != nil {
+		return err
+	}
+	if err := db.Table("series").Order("id").Find(&data.Series).Error; err != nil {
+		return err
+	}
+	if err := db.Table("series_articles").Order("series_id, position").Find(&data.SeriesArticles).Error; err != nil {
+		return err
+	}
+
+	var w io.Writer = app.Stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(data); err != nil {
+		return fmt.Errorf("写入导出文件失败: %w", err)
+	}
+
+	if *output != "" {
+		app.printf("已导出 %d 个用户、%d 篇文章、%d 条评论到 %s\n", len(data.Users), len(data.Articles), len(data.Comments), *output)
+	}
+	return nil
+}
+
+// 从导出文件恢复数据，目标数据库必须为空
+func runImport(app *App, args []string) error {
+	flags := newFlagSet("import", "import -i 文件")
+	input := flags.String("i", "", "导入文件 (必填)，- 表示标准输入")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *input == "" {
+		return fmt.Errorf("%w: -i 是必需的", errUsage)
+	}
+
+	var r io.Reader = os.Stdin
+	if *input != "-" {
+		f, err := os.Open(*input)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	var data dump
+	if err := json.NewDecoder(r).Decode(&data); err != nil {
+		return fmt.Errorf("解析导入文件失败: %w", err)
+	}
+	if data.Version < 1 || data.Version > dumpVersion {
+		return fmt.Errorf("不支持的导入文件版本 %d (当前为 %d)", data.Version, dumpVersion)
+	}
+	// 早于角色功能的导出文件没有角色字段，视为 author
+	for i := range data.Users {
+		if data.Users[i].Role == "" {
+			data.Users[i].Role = models.RoleAuthor
+		}
+	}
+	if data.Version < 2 {
+		for i := range data.Users {
+			data.Users[i].VerifiedAt = &data.Users[i].CreatedAt
+		}
+	}
+	if data.Version < 3 {
+		for i := range data.Articles {
+			data.Articles[i].Status = models.ArticlePublished
+			data.Articles[i].PublishedAt = &data.Articles[i].CreatedAt
+		}
+	}
+	if data.Version < 5 {
+		used := make(map[string]bool)
+		for i := range data.Articles {
+			articleSlug, err := slug.Unique(slug.Make(data.Articles[i].Title), func(s string) (bool, error) {
+				return used[s], nil
+			})
+			if err != nil {
+				return err
+			}
+			used[articleSlug] = true
+			data.Articles[i].Slug = articleSlug
+		}
+	}
+	if data.Version < 8 {
+		for i := range data.Users {
+			u := &data.Users[i]
+			if u.Email == models.DeletedUserEmail(u.ID) {
+				u.DeletedAt = &u.UpdatedAt
+			}
+		}
+	}
+
+	db, err := app.DB()
+	if err != nil {
+		return err
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		for _, table := range dumpTables {
+			var count int64
+			if err := tx.Table(table).Count(&count).Error; err != nil {
+				return err
+			}
+			if count > 0 {
+				return fmt.Errorf("表 %s 中已有 %d 条数据，只能导入到空数据库", table, count)
+			}
+		}
+
+		if len(data.Users) > 0 {
+			if err := tx.Table("users").CreateInBatches(data.Users, 500).Error; err != nil {
+				return fmt.Errorf("导入用户失败: %w", err)
+			}
+		}
+		if len(data.Categories) > 0 {
+			if err := tx.Table("categories").CreateInBatches(data.Categories, 500).Error; err != nil {
+				return fmt.Errorf("导入分类失败: %w", err)
+			}
+		}
+		if len(data.Tags) > 0 {
+			if err := tx.Table("tags").CreateInBatches(data.Tags, 500).Error; err != nil {
+				return fmt.Errorf("导入标签失败: %w", err)
+			}
+		}
+		if len(data.Series) > 0 {
+			if err := tx.Table("series").CreateInBatches(data.Series, 500).Error; err != nil {
+				return fmt.Errorf("导入系列失败: %w", err)
+			}
+		}
+		if len(data.Articles) > 0 {
+			if err := tx.Table("articles").CreateInBatches(data.Articles, 500).Error; err != nil {
+				return fmt.Errorf("导入文章失败: %w", err)
+			}
+		}
+		if len(data.ArticleSlugs) > 0 {
+			if err := tx.Table("article_slugs").CreateInBatches(data.ArticleSlugs, 500).Error; err != nil {
+				return fmt.Errorf("导入文章历史 slug 失败: %w", err)
+			}
+		}
+		if len(data.ArticleTags) > 0 {
+			if err := tx.Table("article_tags").CreateInBatches(data.ArticleTags, 500).Error; err != nil {
+				return fmt.Errorf("导入文章标签失败: %w", err)
+			}
+		}
+		if len(data.SeriesArticles) > 0 {
+			if err := tx.Table("series_articles").CreateInBatches(data.SeriesArticles, 500).Error; err != nil {
+				return fmt.Errorf("导入系列文章失败: %w", err)
+			}
+		}
+		if len(data.Comments) > 0 {
+			if err := tx.Table("comments").CreateInBatches(data.Comments, 500).Error; err != nil {
+				return fmt.Errorf("导入评论失败: %w", err)
+			}
+		}
+
+		// PostgreSQL 的自增序列不会随显式写入的 ID 前进，需要手动校正
+		if app.Config.Database.Driver == config.DriverPostgres {
+			for _, table := range dumpTables {
+				if dumpJoinTables[table] {
+					continue
+				}
+				sql := fmt.Sprintf("SELECT setval(pg_get_serial_sequence('%[1]s', 'id'), COALESCE(MAX(id), 1)) FROM %[1]s", table)
+				if err := tx.Exec(sql).Error; err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	app.printf("已导入 %d 个用户、%d 篇文章、%d 条评论\n", len(data.Users), len(data.Articles), len(data.Comments))
+	return nil
+}
@@ -0,0 +1,262 @@
+package cli
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"blog-backend/internal/auth"
+	"blog-backend/internal/models"
+	"blog-backend/internal/services"
+)
+
+// 管理用户
+func runUser(app *App, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%w: user 需要指定 create、promote、reset-password 或 unlock", errUsage)
+	}
+
+	switch args[0] {
+	case "create":
+		return runUserCreate(app, args[1:])
+	case "promote":
+		return runUserPromote(app, args[1:])
+	case "reset-password":
+		return runUserResetPassword(app, args[1:])
+	case "unlock":
+		return runUserUnlock(app, args[1:])
+	default:
+		return fmt.Errorf("%w: 未知的 user 子命令 %s", errUsage, args[0])
+	}
+}
+
+// 创建用户
+func runUserCreate(app *App, args []string) error {
+	flags := newFlagSet("user create", "user create -username 名称 -email 邮箱 [-password 密码] [-role 角色]")
+	username := flags.String("username", "", "用户名 (必填)")
+	email := flags.String("email", "", "邮箱 (必填)")
+	password := flags.String("password", "", "密码，为空时随机生成并打印")
+	role := flags.String("role", models.RoleAuthor, "角色")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *username == "" || *email == "" {
+		return fmt.Errorf("%w: -username 和 -email 是必需的", errUsage)
+	}
+	if models.ReservedUsername(*username) {
+		return fmt.Errorf("%w: 用户名不能以 deleted- 开头", errUsage)
+	}
+	if !models.ValidRole(*role) {
+		return fmt.Errorf("%w: 无效的角色 %s", errUsage, *role)
+	}
+
+	db, err := app.DB()
+	if err != nil {
+		return err
+	}
+
+	plain, generated, err := passwordOrRandom(app, *password, *username, *email)
+	if err != nil {
+		return err
+	}
+	hashed, err := auth.HashPassword(plain)
+	if err != nil {
+		return err
+	}
+
+	// 管理员创建的用户无需再验证邮箱
+	now := time.Now()
+	user := models.User{Username: *username, Email: *email, Password: hashed, Role: *role, VerifiedAt: &now}
+	if err := db.Create(&user).Error; err != nil {
+		return fmt.Errorf("创建用户失败: %w", err)
+	}
+
+	app.printf("已创建用户 #%d %s <%s> (%s)\n", user.ID, user.Username, user.Email, user.Role)
+	if generated {
+		app.printf("初始密码: %s\n", plain)
+	}
+	return nil
+}
+
+// 修改用户角色
+func runUserPromote(app *App, args []string) error {
+	flags := newFlagSet("user promote", "user promote -email 邮箱 [-role 角色]")
+	email := flags.String("email", "", "用户邮箱 (必填)")
+	role := flags.String("role", models.RoleAdmin, "目标角色")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *email == "" {
+		return fmt.Errorf("%w: -email 是必需的", errUsage)
+	}
+	if !models.ValidRole(*role) {
+		return fmt.Errorf("%w: 无效的角色 %s", errUsage, *role)
+	}
+
+	db, err := app.DB()
+	if err != nil {
+		return err
+	}
+	user, err := findUserByEmail(db, *email)
+	if err != nil {
+		return err
+	}
+
+	if user.Role == *role {
+		app.printf("用户 %s 的角色已是 %s\n", user.Email, *role)
+		return nil
+	}
+
+	// 撤销现有会话使新角色立即生效，并记录审计日志
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&user).Update("role", *role).Error; err != nil {
+			return err
+		}
+		err := tx.Model(&models.Session{}).
+			Where("user_id = ? AND revoked_at IS NULL", user.ID).
+			Update("revoked_at", time.Now()).Error
+		if err != nil {
+			return err
+		}
+		return tx.Create(&models.AuditLog{
+			ActorRole:  "cli",
+			Action:     models.AuditUserRole,
+			TargetType: "user",
+			TargetID:   user.ID,
+			OwnerID:    user.ID,
+			Detail:     fmt.Sprintf("%s -> %s", user.Role, *role),
+		}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("更新角色失败: %w", err)
+	}
+	app.printf("用户 %s 的角色已设置为 %s\n", user.Email, *role)
+	return nil
+}
+
+// 重置用户密码
+func runUserResetPassword(app *App, args []string) error {
+	flags := newFlagSet("user reset-password", "user reset-password -email 邮箱 [-password 新密码]")
+	email := flags.String("email", "", "用户邮箱 (必填)")
+	password := flags.String("password", "", "新密码，为空时随机生成并打印")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *email == "" {
+		return fmt.Errorf("%w: -email 是必需的", errUsage)
+	}
+
+	db, err := app.DB()
+	if err != nil {
+		return err
+	}
+	user, err := findUserByEmail(db, *email)
+	if err != nil {
+		return err
+	}
+
+	plain, generated, err := passwordOrRandom(app, *password, user.Username, user.Email)
+	if err != nil {
+		return err
+	}
+	hashed, err := auth.HashPassword(plain)
+	if err != nil {
+		return err
+	}
+	// 撤销全部登录会话并删除个人访问令牌，持有旧凭据的人无法继续访问
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&user).Update("password", hashed).Error; err != nil {
+			return err
+		}
+		err := tx.Model(&models.Session{}).
+			Where("user_id = ? AND revoked_at IS NULL", user.ID).
+			Update("revoked_at", time.Now()).Error
+		if err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", user.ID).Delete(&models.PersonalToken{}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("重置密码失败: %w", err)
+	}
+
+	app.printf("用户 %s 的密码已重置，全部登录会话和个人访问令牌已失效\n", user.Email)
+	if generated {
+		app.printf("新密码: %s\n", plain)
+	}
+	return nil
+}
+
+// 解除用户的登录锁定
+func runUserUnlock(app *App, args []string) error {
+	flags := newFlagSet("user unlock", "user unlock -email 邮箱")
+	email := flags.String("email", "", "用户邮箱 (必填)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *email == "" {
+		return fmt.Errorf("%w: -email 是必需的", errUsage)
+	}
+
+	db, err := app.DB()
+	if err != nil {
+		return err
+	}
+	user, err := findUserByEmail(db, *email)
+	if err != nil {
+		return err
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("subject = ?", models.AccountThrottle(user.Email)).Delete(&models.LoginThrottle{}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Create(&models.AuditLog{
+			ActorRole:  "cli",
+			Action:     models.AuditUserUnlock,
+			TargetType: "user",
+			TargetID:   user.ID,
+			OwnerID:    user.ID,
+		}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("解锁失败: %w", err)
+	}
+	app.printf("用户 %s 已解锁\n", user.Email)
+	return nil
+}
+
+func findUserByEmail(db *gorm.DB, email string) (models.User, error) {
+	var user models.User
+	if err := db.Where("email = ?", email).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return user, fmt.Errorf("用户 %s 不存在", email)
+		}
+		return user, err
+	}
+	return user, nil
+}
+
+// 未指定密码时生成随机密码，指定的密码需要符合密码规则
+func passwordOrRandom(app *App, password string, personal ...string) (string, bool, error) {
+	if password != "" {
+		policy, err := services.NewPasswordPolicy(app.Config.Account)
+		if err != nil {
+			return "", false, err
+		}
+		if err := policy.Validate(password, personal...); err != nil {
+			return "", false, fmt.Errorf("密码不符合要求: %w", err)
+		}
+		return password, false, nil
+	}
+	buf := make([]byte, 12)
+	if _, err := rand.Read(buf); err != nil {
+		return "", false, err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), true, nil
+}
@@ -0,0 +1,119 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"blog-backend/internal/services"
+)
+
+// 管理后台控制器，仅管理员可访问
+type AdminController struct {
+	admin *services.AdminService
+}
+
+func NewAdminController(admin *services.AdminService) *AdminController {
+	return &AdminController{admin: admin}
+}
+
+type ChangeRoleInput struct {
+	Role string `json:"role" binding:"required"`
+}
+
+// 获取用户列表
+func (ctrl *AdminController) GetUsers(c *gin.Context) {
+	page, limit, offset := parsePagination(c)
+
+	users, total, err := ctrl.admin.ListUsers(c.Request.Context(), offset, limit)
+	if err != nil {
+		respondError(c, err, "服务器内部错误")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"users":      users,
+			"pagination": newPagination(page, limit, total),
+		},
+	})
+}
+
+// 修改用户角色
+func (ctrl *AdminController) UpdateUserRole(c *gin.Context) {
+	actor, exists := currentActor(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "message": "未授权访问", "error_code": "UNAUTHORIZED"})
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "无效的用户ID", "error_code": "INVALID_INPUT"})
+		return
+	}
+
+	var input ChangeRoleInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "输入参数无效", "error_code": "INVALID_INPUT"})
+		return
+	}
+
+	user, err := ctrl.admin.ChangeRole(c.Request.Context(), actor, uint(id), input.Role)
+	if err != nil {
+		respondError(c, err, "更新失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "角色已更新",
+		"data":    user,
+	})
+}
+
+// 解除用户的登录锁定
+func (ctrl *AdminController) UnlockUser(c *gin.Context) {
+	actor, exists := currentActor(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "message": "未授权访问", "error_code": "UNAUTHORIZED"})
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "无效的用户ID", "error_code": "INVALID_INPUT"})
+		return
+	}
+
+	if err := ctrl.admin.UnlockUser(c.Request.Context(), actor, uint(id)); err != nil {
+		respondError(c, err, "解锁失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "账号已解锁",
+	})
+}
+
+// 获取审计日志
+func (ctrl *AdminController) GetAuditLogs(c *gin.Context) {
+	page, limit, offset := parsePagination(c)
+
+	logs, total, err := ctrl.admin.ListAuditLogs(c.Request.Context(), offset, limit)
+	if err != nil {
+		respondError(c, err, "服务器内部错误")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"logs":       logs,
+			"pagination": newPagination(page, limit, total),
+		},
+	})
+}
@@ -1,104 +1,158 @@
 package controllers
 
 import (
+	"context"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
-	"gorm.io/gorm"
 
-	"blog-backend/config"
 	"blog-backend/internal/models"
+	"blog-backend/internal/services"
 )
 
+// 文章控制器
+type ArticleController struct {
+	articles *services.ArticleService
+	series   *services.SeriesService
+}
+
+func NewArticleController(articles *services.ArticleService, series *services.SeriesService) *ArticleController {
+	return &ArticleController{articles: articles, series: series}
+}
+
+// 文章详情，附带所属系列的上一篇和下一篇
+type articleDetail struct {
+	*models.Article
+	// 不属于任何系列时为 null
+	Series *services.SeriesNavigation `json:"series"`
+}
+
 type CreateArticleInput struct {
 	Title   string `json:"title" binding:"required"`
 	Content string `json:"content" binding:"required"`
+	// draft 或 published，为空时直接发布
+	Status string `json:"status"`
+	// 定时发布时间 (RFC 3339)，设置后保存为草稿并在到期后自动发布
+	PublishAt *time.Time `json:"publish_at"`
+	// 标签名称，不存在的标签自动创建
+	Tags       []string `json:"tags"`
+	CategoryID *uint    `json:"category_id"`
 }
 
 type UpdateArticleInput struct {
-	Title   string `json:"title"`
-	Content string `json:"content"`
+	Title     string     `json:"title"`
+	Content   string     `json:"content"`
+	PublishAt *time.Time `json:"publish_at"`
+	// 不传时保持不变，传空数组时清除全部标签
+	Tags []string `json:"tags"`
+	// 不传时保持不变，为 0 时取消分类
+	CategoryID *uint `json:"category_id"`
 }
 
-type Pagination struct {
-	Page       int `json:"page"`
-	Limit      int `json:"limit"`
-	Total      int64 `json:"total"`
-	TotalPages int `json:"total_pages"`
-}
-
-// 获取文章列表
-func GetArticles(c *gin.Context) {
-	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
-
-	if page <= 0 {
-		page = 1
-	}
-	if limit <= 0 || limit > 100 {
-		limit = 10
+// 获取文章列表，默认只返回已发布的文章，可按标签和分类 slug 筛选
+func (ctrl *ArticleController) GetArticles(c *gin.Context) {
+	page, limit, offset := parsePagination(c)
+
+	// 未登录时为零值
+	viewer, _ := currentActor(c)
+	articles, total, err := ctrl.articles.List(c.Request.Context(), viewer, services.ArticleQuery{
+		Status:   c.Query("status"),
+		Tag:      c.Query("tag"),
+		Category: c.Query("category"),
+	}, offset, limit)
+	if err != nil {
+		respondError(c, err, "服务器内部错误")
+		return
 	}
 
-	offset := (page - 1) * limit
-
-	var articles []models.Article
-	var total int64
-
-	// 获取文章总数
-	config.DB.Model(&models.Article{}).Count(&total)
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"articles":   articles,
+			"pagination": newPagination(page, limit, total),
+		},
+	})
+}
 
-	// 获取文章列表，预加载作者信息
-	if err := config.DB.Preload("Author").Offset(offset).Limit(limit).Order("created_at DESC").Find(&articles).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "服务器内部错误", "error_code": "INTERNAL_ERROR"})
+// 获取当前用户的草稿
+func (ctrl *ArticleController) GetDrafts(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "message": "未授权访问", "error_code": "UNAUTHORIZED"})
 		return
 	}
 
-	totalPages := int(total)/limit + 1
-	if int(total)%limit == 0 {
-		totalPages = int(total) / limit
+	page, limit, offset := parsePagination(c)
+
+	articles, total, err := ctrl.articles.ListDrafts(c.Request.Context(), userID.(uint), offset, limit)
+	if err != nil {
+		respondError(c, err, "服务器内部错误")
+		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"data": gin.H{
 			"articles":   articles,
-			"pagination": Pagination{Page: page, Limit: limit, Total: total, TotalPages: totalPages},
+			"pagination": newPagination(page, limit, total),
 		},
 	})
 }
 
 // 获取文章详情
-func GetArticle(c *gin.Context) {
+func (ctrl *ArticleController) GetArticle(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "无效的文章ID", "error_code": "INVALID_INPUT"})
 		return
 	}
 
-	var article models.Article
-	// 预加载作者信息
-	if err := config.DB.Preload("Author").First(&article, id).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"success": false, "message": "文章不存在", "error_code": "NOT_FOUND"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "服务器内部错误", "error_code": "INTERNAL_ERROR"})
+	// 查询文章并增加浏览量，未登录时 viewer 为零值
+	viewer, _ := currentActor(c)
+	article, err := ctrl.articles.View(c.Request.Context(), viewer, uint(id))
+	if err != nil {
+		respondError(c, err, "服务器内部错误")
 		return
 	}
 
-	// 增加浏览量
-	article.Views++
-	config.DB.Save(&article)
+	ctrl.respondDetail(c, viewer, article)
+}
+
+// 按 slug 获取文章详情，以前使用过的 slug 永久重定向到当前 slug
+func (ctrl *ArticleController) GetArticleBySlug(c *gin.Context) {
+	viewer, _ := currentActor(c)
+	article, moved, err := ctrl.articles.ViewBySlug(c.Request.Context(), viewer, c.Param("slug"))
+	if err != nil {
+		respondError(c, err, "服务器内部错误")
+		return
+	}
+	if moved {
+		c.Redirect(http.StatusMovedPermanently, strings.TrimSuffix(c.Request.URL.Path, c.Param("slug"))+article.Slug)
+		return
+	}
+
+	ctrl.respondDetail(c, viewer, article)
+}
+
+// 返回文章详情及系列导航，导航中只包括 viewer 可见的文章
+func (ctrl *ArticleController) respondDetail(c *gin.Context, viewer services.Actor, article *models.Article) {
+	nav, err := ctrl.series.Navigation(c.Request.Context(), viewer, article.ID)
+	if err != nil {
+		respondError(c, err, "服务器内部错误")
+		return
+	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
-		"data":    article,
+		"data":    articleDetail{Article: article, Series: nav},
 	})
 }
 
 // 创建文章
-func CreateArticle(c *gin.Context) {
+func (ctrl *ArticleController) CreateArticle(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
 		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "message": "未授权访问", "error_code": "UNAUTHORIZED"})
@@ -111,14 +165,16 @@ func CreateArticle(c *gin.Context) {
 		return
 	}
 
-	article := models.Article{
-		Title:    input.Title,
-		Content:  input.Content,
-		AuthorID: userID.(uint),
-	}
-
-	if err := config.DB.Create(&article).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "创建失败", "error_code": "INTERNAL_ERROR"})
+	article, err := ctrl.articles.Create(c.Request.Context(), userID.(uint), services.CreateArticleParams{
+		Title:      input.Title,
+		Content:    input.Content,
+		Status:     input.Status,
+		PublishAt:  input.PublishAt,
+		Tags:       input.Tags,
+		CategoryID: input.CategoryID,
+	})
+	if err != nil {
+		respondError(c, err, "创建失败")
 		return
 	}
 
@@ -130,8 +186,8 @@ func CreateArticle(c *gin.Context) {
 }
 
 // 更新文章
-func UpdateArticle(c *gin.Context) {
-	userID, exists := c.Get("user_id")
+func (ctrl *ArticleController) UpdateArticle(c *gin.Context) {
+	actor, exists := currentActor(c)
 	if !exists {
 		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "message": "未授权访问", "error_code": "UNAUTHORIZED"})
 		return
@@ -143,38 +199,21 @@ func UpdateArticle(c *gin.Context) {
 		return
 	}
 
-	var article models.Article
-	if err := config.DB.First(&article, id).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"success": false, "message": "文章不存在", "error_code": "NOT_FOUND"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "服务器内部错误", "error_code": "INTERNAL_ERROR"})
-		return
-	}
-
-	// 检查是否有权限更新文章
-	if article.AuthorID != userID.(uint) {
-		c.JSON(http.StatusForbidden, gin.H{"success": false, "message": "权限不足", "error_code": "FORBIDDEN"})
-		return
-	}
-
 	var input UpdateArticleInput
 	if err := c.ShouldBindJSON(&input); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "输入参数无效", "error_code": "INVALID_INPUT"})
 		return
 	}
 
-	// 更新文章信息
-	if input.Title != "" {
-		article.Title = input.Title
-	}
-	if input.Content != "" {
-		article.Content = input.Content
-	}
-
-	if err := config.DB.Save(&article).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "更新失败", "error_code": "INTERNAL_ERROR"})
+	article, err := ctrl.articles.Update(c.Request.Context(), actor, uint(id), services.UpdateArticleParams{
+		Title:      input.Title,
+		Content:    input.Content,
+		PublishAt:  input.PublishAt,
+		Tags:       input.Tags,
+		CategoryID: input.CategoryID,
+	})
+	if err != nil {
+		respondError(c, err, "更新失败")
 		return
 	}
 
@@ -185,9 +224,23 @@ func UpdateArticle(c *gin.Context) {
 	})
 }
 
-// 删除文章
-func DeleteArticle(c *gin.Context) {
-	userID, exists := c.Get("user_id")
+// 发布文章
+func (ctrl *ArticleController) PublishArticle(c *gin.Context) {
+	ctrl.changeStatus(c, ctrl.articles.Publish, "发布成功")
+}
+
+// 撤回文章为草稿
+func (ctrl *ArticleController) UnpublishArticle(c *gin.Context) {
+	ctrl.changeStatus(c, ctrl.articles.Unpublish, "已撤回为草稿")
+}
+
+// 归档文章
+func (ctrl *ArticleController) ArchiveArticle(c *gin.Context) {
+	ctrl.changeStatus(c, ctrl.articles.Archive, "归档成功")
+}
+
+func (ctrl *ArticleController) changeStatus(c *gin.Context, change func(context.Context, services.Actor, uint) (*models.Article, error), message string) {
+	actor, exists := currentActor(c)
 	if !exists {
 		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "message": "未授权访问", "error_code": "UNAUTHORIZED"})
 		return
@@ -199,24 +252,35 @@ func DeleteArticle(c *gin.Context) {
 		return
 	}
 
-	var article models.Article
-	if err := config.DB.First(&article, id).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"success": false, "message": "文章不存在", "error_code": "NOT_FOUND"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "服务器内部错误", "error_code": "INTERNAL_ERROR"})
+	article, err := change(c.Request.Context(), actor, uint(id))
+	if err != nil {
+		respondError(c, err, "操作失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": message,
+		"data":    article,
+	})
+}
+
+// 删除文章
+func (ctrl *ArticleController) DeleteArticle(c *gin.Context) {
+	actor, exists := currentActor(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "message": "未授权访问", "error_code": "UNAUTHORIZED"})
 		return
 	}
 
-	// 检查是否有权限删除文章
-	if article.AuthorID != userID.(uint) {
-		c.JSON(http.StatusForbidden, gin.H{"success": false, "message": "权限不足", "error_code": "FORBIDDEN"})
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "无效的文章ID", "error_code": "INVALID_INPUT"})
 		return
 	}
 
-	if err := config.DB.Delete(&article).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "删除失败", "error_code": "INTERNAL_ERROR"})
+	if err := ctrl.articles.Delete(c.Request.Context(), actor, uint(id)); err != nil {
+		respondError(c, err, "删除失败")
 		return
 	}
 
@@ -224,4 +288,4 @@ func DeleteArticle(c *gin.Context) {
 		"success": true,
 		"message": "删除成功",
 	})
-}
\ No newline at end of file
+}
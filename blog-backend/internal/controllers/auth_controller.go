@@ -2,22 +2,30 @@ package controllers
 
 import (
 	"net/http"
-	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v4"
-	"golang.org/x/crypto/bcrypt"
-	"gorm.io/gorm"
 
-	"blog-backend/config"
 	"blog-backend/internal/models"
+	"blog-backend/internal/services"
 	"blog-backend/internal/utils"
 )
 
+// 认证控制器
+type AuthController struct {
+	auth      *services.AuthService
+	sessions  *services.SessionService
+	accounts  *services.AccountService
+	twoFactor *services.TwoFactorService
+}
+
+func NewAuthController(auth *services.AuthService, sessions *services.SessionService, accounts *services.AccountService, twoFactor *services.TwoFactorService) *AuthController {
+	return &AuthController{auth: auth, sessions: sessions, accounts: accounts, twoFactor: twoFactor}
+}
+
 type RegisterInput struct {
 	Username string `json:"username" binding:"required"`
 	Email    string `json:"email" binding:"required,email"`
-	Password string `json:"password" binding:"required,min=6"`
+	Password string `json:"password" binding:"required"`
 }
 
 type LoginInput struct {
@@ -25,120 +33,255 @@ type LoginInput struct {
 	Password string `json:"password" binding:"required"`
 }
 
+type RefreshInput struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+type ForgotPasswordInput struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type ResetPasswordInput struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+type VerifyEmailInput struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type TwoFactorLoginInput struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	// 身份验证器中的验证码或恢复码
+	Code string `json:"code" binding:"required"`
+}
+
 type AuthResponse struct {
-	Token string      `json:"token"`
-	User  models.User `json:"user,omitempty"`
+	services.TokenPair
+	User *models.User `json:"user,omitempty"`
+}
+
+// 开启两步验证的用户登录时的响应
+type TwoFactorRequiredResponse struct {
+	TwoFactorRequired bool `json:"two_factor_required"`
+	services.TwoFactorChallenge
 }
 
 // 用户注册
-func Register(c *gin.Context) {
+func (ctrl *AuthController) Register(c *gin.Context) {
 	var input RegisterInput
 	if err := c.ShouldBindJSON(&input); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": utils.GetValidationError(err), "error_code": "INVALID_INPUT"})
 		return
 	}
 
-	// 检查用户是否已存在
-	var existingUser models.User
-	if err := config.DB.Where("email = ? OR username = ?", input.Email, input.Username).First(&existingUser).Error; err != nil && err != gorm.ErrRecordNotFound {
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "服务器内部错误", "error_code": "INTERNAL_ERROR"})
+	_, tokens, err := ctrl.auth.Register(c.Request.Context(), services.RegisterParams{
+		Username: input.Username,
+		Email:    input.Email,
+		Password: input.Password,
+	}, clientInfo(c))
+	if err != nil {
+		respondError(c, err, "注册失败")
 		return
 	}
-	if existingUser.ID != 0 {
-		c.JSON(http.StatusConflict, gin.H{"success": false, "message": "用户名或邮箱已存在", "error_code": "INVALID_INPUT"})
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "注册成功",
+		"data":    AuthResponse{TokenPair: *tokens},
+	})
+}
+
+// 用户登录
+func (ctrl *AuthController) Login(c *gin.Context) {
+	var input LoginInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": utils.GetValidationError(err), "error_code": "INVALID_INPUT"})
 		return
 	}
 
-	// 加密密码
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(input.Password), bcrypt.DefaultCost)
+	result, err := ctrl.auth.Login(c.Request.Context(), input.Email, input.Password, clientInfo(c))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "服务器内部错误", "error_code": "INTERNAL_ERROR"})
+		respondError(c, err, "服务器内部错误")
 		return
 	}
+	respondLogin(c, result)
+}
 
-	// 创建用户
-	user := models.User{
-		Username: input.Username,
-		Email:    input.Email,
-		Password: string(hashedPassword),
-		Avatar:   "",
+// 输出登录结果，开启两步验证时只返回登录挑战
+func respondLogin(c *gin.Context, result *services.LoginResult) {
+	if result.Challenge != nil {
+		c.JSON(http.StatusOK, gin.H{
+			"success": true,
+			"message": "请输入两步验证码",
+			"data":    TwoFactorRequiredResponse{TwoFactorRequired: true, TwoFactorChallenge: *result.Challenge},
+		})
+		return
 	}
 
-	if err := config.DB.Create(&user).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "注册失败", "error_code": "INTERNAL_ERROR"})
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "登录成功",
+		"data":    AuthResponse{TokenPair: *result.Tokens, User: result.User},
+	})
+}
+
+// 使用两步验证码完成登录
+func (ctrl *AuthController) LoginTwoFactor(c *gin.Context) {
+	var input TwoFactorLoginInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": utils.GetValidationError(err), "error_code": "INVALID_INPUT"})
 		return
 	}
 
-	// 生成JWT token
-	token, err := generateToken(user.ID)
+	user, tokens, err := ctrl.twoFactor.CompleteLogin(c.Request.Context(), input.ChallengeToken, input.Code, clientInfo(c))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "注册成功但token生成失败", "error_code": "INTERNAL_ERROR"})
+		respondError(c, err, "服务器内部错误")
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
-		"message": "注册成功",
-		"data":    AuthResponse{Token: token},
+		"message": "登录成功",
+		"data":    AuthResponse{TokenPair: *tokens, User: user},
 	})
 }
 
-// 用户登录
-func Login(c *gin.Context) {
-	var input LoginInput
+// 刷新访问令牌
+func (ctrl *AuthController) Refresh(c *gin.Context) {
+	var input RefreshInput
 	if err := c.ShouldBindJSON(&input); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": utils.GetValidationError(err), "error_code": "INVALID_INPUT"})
 		return
 	}
 
-	// 查找用户
-	var user models.User
-	if err := config.DB.Where("email = ?", input.Email).First(&user).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusUnauthorized, gin.H{"success": false, "message": "邮箱或密码错误", "error_code": "UNAUTHORIZED"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "服务器内部错误", "error_code": "INTERNAL_ERROR"})
+	tokens, err := ctrl.sessions.Refresh(c.Request.Context(), input.RefreshToken, clientInfo(c))
+	if err != nil {
+		respondError(c, err, "服务器内部错误")
 		return
 	}
 
-	// 验证密码
-	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(input.Password)); err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "message": "邮箱或密码错误", "error_code": "UNAUTHORIZED"})
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "刷新成功",
+		"data":    tokens,
+	})
+}
+
+// 退出登录，撤销当前会话
+func (ctrl *AuthController) Logout(c *gin.Context) {
+	sessionID, exists := c.Get("session_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "message": "未授权访问", "error_code": "UNAUTHORIZED"})
 		return
 	}
 
-	// 生成JWT token
-	token, err := generateToken(user.ID)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "登录成功但token生成失败", "error_code": "INTERNAL_ERROR"})
+	if err := ctrl.sessions.Revoke(c.Request.Context(), sessionID.(uint)); err != nil {
+		respondError(c, err, "退出登录失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "已退出登录",
+	})
+}
+
+// 发送密码重置邮件，邮箱是否注册都返回相同的响应
+func (ctrl *AuthController) ForgotPassword(c *gin.Context) {
+	var input ForgotPasswordInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": utils.GetValidationError(err), "error_code": "INVALID_INPUT"})
 		return
 	}
 
-	// 隐藏密码字段
-	user.Password = ""
+	if err := ctrl.accounts.ForgotPassword(c.Request.Context(), input.Email); err != nil {
+		respondError(c, err, "发送邮件失败")
+		return
+	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
-		"message": "登录成功",
-		"data": gin.H{
-			"token": token,
-			"user":  user,
-		},
+		"message": "如果该邮箱已注册，重置链接已发送到邮箱",
 	})
 }
 
-// 生成JWT token
-func generateToken(userID uint) (string, error) {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"user_id": userID,
-		"exp":     time.Now().Add(time.Hour * 24).Unix(),
+// 使用重置令牌设置新密码
+func (ctrl *AuthController) ResetPassword(c *gin.Context) {
+	var input ResetPasswordInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": utils.GetValidationError(err), "error_code": "INVALID_INPUT"})
+		return
+	}
+
+	if err := ctrl.accounts.ResetPassword(c.Request.Context(), input.Token, input.Password); err != nil {
+		respondError(c, err, "重置密码失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "密码已重置，请重新登录",
 	})
+}
 
-	tokenString, err := token.SignedString([]byte("your_jwt_secret_key"))
+// 验证邮箱
+func (ctrl *AuthController) VerifyEmail(c *gin.Context) {
+	var input VerifyEmailInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": utils.GetValidationError(err), "error_code": "INVALID_INPUT"})
+		return
+	}
+
+	user, err := ctrl.accounts.VerifyEmail(c.Request.Context(), input.Token)
 	if err != nil {
-		return "", err
+		respondError(c, err, "验证失败")
+		return
 	}
 
-	return tokenString, nil
-}
\ No newline at end of file
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "邮箱验证成功",
+		"data":    user,
+	})
+}
+
+// 重新发送验证邮件
+func (ctrl *AuthController) ResendVerification(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "message": "未授权访问", "error_code": "UNAUTHORIZED"})
+		return
+	}
+
+	if err := ctrl.accounts.ResendVerification(c.Request.Context(), userID.(uint)); err != nil {
+		respondError(c, err, "发送邮件失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "验证邮件已发送",
+	})
+}
+
+// 确认邮箱变更
+func (ctrl *AuthController) ConfirmEmailChange(c *gin.Context) {
+	var input VerifyEmailInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": utils.GetValidationError(err), "error_code": "INVALID_INPUT"})
+		return
+	}
+
+	user, err := ctrl.accounts.ConfirmEmailChange(c.Request.Context(), input.Token)
+	if err != nil {
+		respondError(c, err, "修改邮箱失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "邮箱已修改",
+		"data":    user,
+	})
+}
@@ -0,0 +1,127 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"blog-backend/internal/services"
+	"blog-backend/internal/utils"
+)
+
+// 分类控制器
+type CategoryController struct {
+	categories *services.CategoryService
+}
+
+func NewCategoryController(categories *services.CategoryService) *CategoryController {
+	return &CategoryController{categories: categories}
+}
+
+type CreateCategoryInput struct {
+	Name string `json:"name" binding:"required"`
+	// 为空时由名称生成
+	Slug        string `json:"slug"`
+	Description string `json:"description"`
+	ParentID    *uint  `json:"parent_id"`
+}
+
+type UpdateCategoryInput struct {
+	Name        string `json:"name"`
+	Slug        string `json:"slug"`
+	Description string `json:"description"`
+	// 不传时保持不变，为 0 时移动为顶级分类
+	ParentID *uint `json:"parent_id"`
+}
+
+// 获取分类树
+func (ctrl *CategoryController) GetCategories(c *gin.Context) {
+	tree, err := ctrl.categories.Tree(c.Request.Context())
+	if err != nil {
+		respondError(c, err, "服务器内部错误")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    tree,
+	})
+}
+
+// 创建分类
+func (ctrl *CategoryController) CreateCategory(c *gin.Context) {
+	var input CreateCategoryInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": utils.GetValidationError(err), "error_code": "INVALID_INPUT"})
+		return
+	}
+
+	category, err := ctrl.categories.Create(c.Request.Context(), services.CreateCategoryParams{
+		Name:        input.Name,
+		Slug:        input.Slug,
+		Description: input.Description,
+		ParentID:    input.ParentID,
+	})
+	if err != nil {
+		respondError(c, err, "创建失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "创建成功",
+		"data":    category,
+	})
+}
+
+// 修改分类
+func (ctrl *CategoryController) UpdateCategory(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "无效的分类ID", "error_code": "INVALID_INPUT"})
+		return
+	}
+
+	var input UpdateCategoryInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "输入参数无效", "error_code": "INVALID_INPUT"})
+		return
+	}
+
+	category, err := ctrl.categories.Update(c.Request.Context(), uint(id), services.UpdateCategoryParams{
+		Name:        input.Name,
+		Slug:        input.Slug,
+		Description: input.Description,
+		ParentID:    input.ParentID,
+	})
+	if err != nil {
+		respondError(c, err, "更新失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "更新成功",
+		"data":    category,
+	})
+}
+
+// 删除分类
+func (ctrl *CategoryController) DeleteCategory(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "无效的分类ID", "error_code": "INVALID_INPUT"})
+		return
+	}
+
+	if err := ctrl.categories.Delete(c.Request.Context(), uint(id)); err != nil {
+		respondError(c, err, "删除失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "删除成功",
+	})
+}
@@ -0,0 +1,83 @@
+package controllers
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"blog-backend/internal/services"
+)
+
+type Pagination struct {
+	Page       int   `json:"page"`
+	Limit      int   `json:"limit"`
+	Total      int64 `json:"total"`
+	TotalPages int   `json:"total_pages"`
+}
+
+// 解析分页参数，返回页码、每页数量和偏移量
+func parsePagination(c *gin.Context) (int, int, int) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	if page <= 0 {
+		page = 1
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 10
+	}
+
+	return page, limit, (page - 1) * limit
+}
+
+func newPagination(page, limit int, total int64) Pagination {
+	totalPages := int(total)/limit + 1
+	if int(total)%limit == 0 {
+		totalPages = int(total) / limit
+	}
+	return Pagination{Page: page, Limit: limit, Total: total, TotalPages: totalPages}
+}
+
+// 当前登录用户及其角色，由 AuthMiddleware 写入上下文
+func currentActor(c *gin.Context) (services.Actor, bool) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		return services.Actor{}, false
+	}
+	return services.Actor{UserID: userID.(uint), Role: c.GetString("role")}, true
+}
+
+// 请求的客户端信息
+func clientInfo(c *gin.Context) services.ClientInfo {
+	return services.ClientInfo{UserAgent: c.Request.UserAgent(), IP: c.ClientIP()}
+}
+
+// 输出错误响应，业务错误按类型映射状态码，其他错误返回 fallback 信息
+func respondError(c *gin.Context, err error, fallback string) {
+	e := services.AsError(err)
+	if e == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": fallback, "error_code": "INTERNAL_ERROR"})
+		return
+	}
+
+	status, code := http.StatusBadRequest, "INVALID_INPUT"
+	switch e.Kind {
+	case services.KindUnauthorized:
+		status, code = http.StatusUnauthorized, "UNAUTHORIZED"
+	case services.KindForbidden:
+		status, code = http.StatusForbidden, "FORBIDDEN"
+	case services.KindNotFound:
+		status, code = http.StatusNotFound, "NOT_FOUND"
+	case services.KindConflict:
+		status = http.StatusConflict
+	case services.KindNotVerified:
+		status, code = http.StatusForbidden, "EMAIL_NOT_VERIFIED"
+	case services.KindTooManyRequests:
+		status, code = http.StatusTooManyRequests, "TOO_MANY_ATTEMPTS"
+		// 向上取整，避免客户端在锁定结束前重试
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(e.RetryAfter.Seconds()))))
+	}
+	c.JSON(status, gin.H{"success": false, "message": e.Message, "error_code": code})
+}
@@ -0,0 +1,24 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"blog-backend/internal/auth"
+)
+
+// 访问令牌公钥控制器
+type KeyController struct {
+	tokens *auth.TokenManager
+}
+
+func NewKeyController(tokens *auth.TokenManager) *KeyController {
+	return &KeyController{tokens: tokens}
+}
+
+// 以标准 JWKS 格式返回访问令牌的校验公钥，供其他服务校验令牌
+func (ctrl *KeyController) GetJWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, gin.H{"keys": ctrl.tokens.JWKS()})
+}
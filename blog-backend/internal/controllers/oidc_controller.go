@@ -0,0 +1,54 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"blog-backend/internal/services"
+	"blog-backend/internal/utils"
+)
+
+// OpenID Connect 登录控制器
+type OIDCController struct {
+	oidc *services.OIDCService
+}
+
+func NewOIDCController(oidc *services.OIDCService) *OIDCController {
+	return &OIDCController{oidc: oidc}
+}
+
+type OIDCCallbackInput struct {
+	Code  string `json:"code" binding:"required"`
+	State string `json:"state" binding:"required"`
+}
+
+// 获取身份提供方的授权地址
+func (ctrl *OIDCController) Authorize(c *gin.Context) {
+	authURL, err := ctrl.oidc.Begin(c.Request.Context())
+	if err != nil {
+		respondError(c, err, "无法连接身份提供方")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    gin.H{"authorization_url": authURL},
+	})
+}
+
+// 使用回调中的授权码完成登录
+func (ctrl *OIDCController) Callback(c *gin.Context) {
+	var input OIDCCallbackInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": utils.GetValidationError(err), "error_code": "INVALID_INPUT"})
+		return
+	}
+
+	result, err := ctrl.oidc.Complete(c.Request.Context(), input.Code, input.State, clientInfo(c))
+	if err != nil {
+		respondError(c, err, "服务器内部错误")
+		return
+	}
+	respondLogin(c, result)
+}
@@ -0,0 +1,134 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"blog-backend/internal/models"
+	"blog-backend/internal/services"
+	"blog-backend/internal/utils"
+)
+
+// 个人访问令牌控制器
+type PersonalTokenController struct {
+	tokens *services.PersonalTokenService
+}
+
+func NewPersonalTokenController(tokens *services.PersonalTokenService) *PersonalTokenController {
+	return &PersonalTokenController{tokens: tokens}
+}
+
+type CreatePersonalTokenInput struct {
+	Name   string   `json:"name" binding:"required"`
+	Scopes []string `json:"scopes" binding:"required"`
+	// 为空表示永不过期
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+type PersonalTokenResponse struct {
+	ID         uint       `json:"id"`
+	Name       string     `json:"name"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	// 令牌明文，只在创建时返回
+	Token string `json:"token,omitempty"`
+}
+
+func newPersonalTokenResponse(t *models.PersonalToken) PersonalTokenResponse {
+	return PersonalTokenResponse{
+		ID:         t.ID,
+		Name:       t.Name,
+		Scopes:     t.ScopeList(),
+		ExpiresAt:  t.ExpiresAt,
+		LastUsedAt: t.LastUsedAt,
+		CreatedAt:  t.CreatedAt,
+	}
+}
+
+// 获取当前用户的个人访问令牌
+func (ctrl *PersonalTokenController) GetTokens(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "message": "未授权访问", "error_code": "UNAUTHORIZED"})
+		return
+	}
+
+	tokens, err := ctrl.tokens.List(c.Request.Context(), userID.(uint))
+	if err != nil {
+		respondError(c, err, "服务器内部错误")
+		return
+	}
+
+	data := make([]PersonalTokenResponse, 0, len(tokens))
+	for i := range tokens {
+		data = append(data, newPersonalTokenResponse(&tokens[i]))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    data,
+	})
+}
+
+// 创建个人访问令牌
+func (ctrl *PersonalTokenController) CreateToken(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "message": "未授权访问", "error_code": "UNAUTHORIZED"})
+		return
+	}
+
+	var input CreatePersonalTokenInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": utils.GetValidationError(err), "error_code": "INVALID_INPUT"})
+		return
+	}
+
+	token, plain, err := ctrl.tokens.Create(c.Request.Context(), userID.(uint), services.CreatePersonalTokenParams{
+		Name:      input.Name,
+		Scopes:    input.Scopes,
+		ExpiresAt: input.ExpiresAt,
+	})
+	if err != nil {
+		respondError(c, err, "创建令牌失败")
+		return
+	}
+
+	data := newPersonalTokenResponse(token)
+	data.Token = plain
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "令牌已创建，请立即复制保存，之后将无法再次查看",
+		"data":    data,
+	})
+}
+
+// 撤销个人访问令牌
+func (ctrl *PersonalTokenController) DeleteToken(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "message": "未授权访问", "error_code": "UNAUTHORIZED"})
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "无效的令牌ID", "error_code": "INVALID_INPUT"})
+		return
+	}
+
+	if err := ctrl.tokens.Revoke(c.Request.Context(), userID.(uint), uint(id)); err != nil {
+		respondError(c, err, "撤销令牌失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "令牌已撤销",
+	})
+}
@@ -0,0 +1,203 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"blog-backend/internal/services"
+	"blog-backend/internal/utils"
+)
+
+// 系列文章控制器
+type SeriesController struct {
+	series *services.SeriesService
+}
+
+func NewSeriesController(series *services.SeriesService) *SeriesController {
+	return &SeriesController{series: series}
+}
+
+type CreateSeriesInput struct {
+	Title       string `json:"title" binding:"required"`
+	Description string `json:"description"`
+	// 按顺序排列的文章ID
+	ArticleIDs []uint `json:"article_ids"`
+}
+
+type UpdateSeriesInput struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+type SeriesArticlesInput struct {
+	// 按顺序排列的全部文章ID，不在列表中的文章移出系列
+	ArticleIDs []uint `json:"article_ids" binding:"required"`
+}
+
+// 获取系列列表
+func (ctrl *SeriesController) GetSeriesList(c *gin.Context) {
+	page, limit, offset := parsePagination(c)
+
+	series, total, err := ctrl.series.List(c.Request.Context(), offset, limit)
+	if err != nil {
+		respondError(c, err, "服务器内部错误")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"series":     series,
+			"pagination": newPagination(page, limit, total),
+		},
+	})
+}
+
+// 获取系列详情及其中的文章
+func (ctrl *SeriesController) GetSeries(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "无效的系列ID", "error_code": "INVALID_INPUT"})
+		return
+	}
+
+	// 未登录时 viewer 为零值
+	viewer, _ := currentActor(c)
+	series, err := ctrl.series.Get(c.Request.Context(), viewer, uint(id))
+	if err != nil {
+		respondError(c, err, "服务器内部错误")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    series,
+	})
+}
+
+// 创建系列
+func (ctrl *SeriesController) CreateSeries(c *gin.Context) {
+	actor, exists := currentActor(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "message": "未授权访问", "error_code": "UNAUTHORIZED"})
+		return
+	}
+
+	var input CreateSeriesInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": utils.GetValidationError(err), "error_code": "INVALID_INPUT"})
+		return
+	}
+
+	series, err := ctrl.series.Create(c.Request.Context(), actor, services.CreateSeriesParams{
+		Title:       input.Title,
+		Description: input.Description,
+		ArticleIDs:  input.ArticleIDs,
+	})
+	if err != nil {
+		respondError(c, err, "创建失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "创建成功",
+		"data":    series,
+	})
+}
+
+// 修改系列标题和简介
+func (ctrl *SeriesController) UpdateSeries(c *gin.Context) {
+	actor, exists := currentActor(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "message": "未授权访问", "error_code": "UNAUTHORIZED"})
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "无效的系列ID", "error_code": "INVALID_INPUT"})
+		return
+	}
+
+	var input UpdateSeriesInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "输入参数无效", "error_code": "INVALID_INPUT"})
+		return
+	}
+
+	series, err := ctrl.series.Update(c.Request.Context(), actor, uint(id), services.UpdateSeriesParams{
+		Title:       input.Title,
+		Description: input.Description,
+	})
+	if err != nil {
+		respondError(c, err, "更新失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "更新成功",
+		"data":    series,
+	})
+}
+
+// 设置系列中的文章及顺序
+func (ctrl *SeriesController) SetSeriesArticles(c *gin.Context) {
+	actor, exists := currentActor(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "message": "未授权访问", "error_code": "UNAUTHORIZED"})
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "无效的系列ID", "error_code": "INVALID_INPUT"})
+		return
+	}
+
+	var input SeriesArticlesInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": utils.GetValidationError(err), "error_code": "INVALID_INPUT"})
+		return
+	}
+
+	series, err := ctrl.series.SetArticles(c.Request.Context(), actor, uint(id), input.ArticleIDs)
+	if err != nil {
+		respondError(c, err, "更新失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "更新成功",
+		"data":    series,
+	})
+}
+
+// 删除系列，其中的文章保留
+func (ctrl *SeriesController) DeleteSeries(c *gin.Context) {
+	actor, exists := currentActor(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "message": "未授权访问", "error_code": "UNAUTHORIZED"})
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "无效的系列ID", "error_code": "INVALID_INPUT"})
+		return
+	}
+
+	if err := ctrl.series.Delete(c.Request.Context(), actor, uint(id)); err != nil {
+		respondError(c, err, "删除失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "删除成功",
+	})
+}
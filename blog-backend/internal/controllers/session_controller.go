@@ -0,0 +1,109 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"blog-backend/internal/services"
+)
+
+// 登录会话控制器
+type SessionController struct {
+	sessions *services.SessionService
+}
+
+func NewSessionController(sessions *services.SessionService) *SessionController {
+	return &SessionController{sessions: sessions}
+}
+
+type SessionResponse struct {
+	ID         uint      `json:"id"`
+	UserAgent  string    `json:"user_agent"`
+	IP         string    `json:"ip"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	// 是否为发起本次请求的会话
+	Current bool `json:"current"`
+}
+
+// 获取当前用户的登录会话列表
+func (ctrl *SessionController) GetSessions(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "message": "未授权访问", "error_code": "UNAUTHORIZED"})
+		return
+	}
+	currentID, _ := c.Get("session_id")
+
+	sessions, err := ctrl.sessions.List(c.Request.Context(), userID.(uint))
+	if err != nil {
+		respondError(c, err, "服务器内部错误")
+		return
+	}
+
+	data := make([]SessionResponse, 0, len(sessions))
+	for _, s := range sessions {
+		data = append(data, SessionResponse{
+			ID:         s.ID,
+			UserAgent:  s.UserAgent,
+			IP:         s.IP,
+			CreatedAt:  s.CreatedAt,
+			LastSeenAt: s.LastSeenAt,
+			ExpiresAt:  s.ExpiresAt,
+			Current:    s.ID == currentID,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    data,
+	})
+}
+
+// 撤销指定会话
+func (ctrl *SessionController) DeleteSession(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "message": "未授权访问", "error_code": "UNAUTHORIZED"})
+		return
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "无效的会话ID", "error_code": "INVALID_INPUT"})
+		return
+	}
+
+	if err := ctrl.sessions.RevokeForUser(c.Request.Context(), userID.(uint), uint(id)); err != nil {
+		respondError(c, err, "退出登录失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "已退出该设备",
+	})
+}
+
+// 退出全部设备，包括当前会话
+func (ctrl *SessionController) DeleteAllSessions(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "message": "未授权访问", "error_code": "UNAUTHORIZED"})
+		return
+	}
+
+	if err := ctrl.sessions.RevokeAll(c.Request.Context(), userID.(uint), 0); err != nil {
+		respondError(c, err, "退出登录失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "已退出全部设备",
+	})
+}
@@ -0,0 +1,105 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"blog-backend/internal/services"
+	"blog-backend/internal/utils"
+)
+
+// 标签控制器
+type TagController struct {
+	tags *services.TagService
+}
+
+func NewTagController(tags *services.TagService) *TagController {
+	return &TagController{tags: tags}
+}
+
+type TagInput struct {
+	Name string `json:"name" binding:"required"`
+}
+
+// 获取标签云
+func (ctrl *TagController) GetTags(c *gin.Context) {
+	tags, err := ctrl.tags.List(c.Request.Context())
+	if err != nil {
+		respondError(c, err, "服务器内部错误")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    tags,
+	})
+}
+
+// 创建标签
+func (ctrl *TagController) CreateTag(c *gin.Context) {
+	var input TagInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": utils.GetValidationError(err), "error_code": "INVALID_INPUT"})
+		return
+	}
+
+	tag, err := ctrl.tags.Create(c.Request.Context(), input.Name)
+	if err != nil {
+		respondError(c, err, "创建失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "创建成功",
+		"data":    tag,
+	})
+}
+
+// 重命名标签
+func (ctrl *TagController) UpdateTag(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "无效的标签ID", "error_code": "INVALID_INPUT"})
+		return
+	}
+
+	var input TagInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": utils.GetValidationError(err), "error_code": "INVALID_INPUT"})
+		return
+	}
+
+	tag, err := ctrl.tags.Rename(c.Request.Context(), uint(id), input.Name)
+	if err != nil {
+		respondError(c, err, "更新失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "更新成功",
+		"data":    tag,
+	})
+}
+
+// 删除标签
+func (ctrl *TagController) DeleteTag(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "无效的标签ID", "error_code": "INVALID_INPUT"})
+		return
+	}
+
+	if err := ctrl.tags.Delete(c.Request.Context(), uint(id)); err != nil {
+		respondError(c, err, "删除失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "删除成功",
+	})
+}
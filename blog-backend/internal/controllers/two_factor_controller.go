@@ -0,0 +1,133 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"blog-backend/internal/services"
+	"blog-backend/internal/utils"
+)
+
+// 两步验证设置控制器
+type TwoFactorController struct {
+	twoFactor *services.TwoFactorService
+}
+
+func NewTwoFactorController(twoFactor *services.TwoFactorService) *TwoFactorController {
+	return &TwoFactorController{twoFactor: twoFactor}
+}
+
+type PasswordConfirmInput struct {
+	Password string `json:"password" binding:"required"`
+}
+
+type TwoFactorCodeInput struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// 生成两步验证密钥
+func (ctrl *TwoFactorController) Enroll(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "message": "未授权访问", "error_code": "UNAUTHORIZED"})
+		return
+	}
+
+	var input PasswordConfirmInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": utils.GetValidationError(err), "error_code": "INVALID_INPUT"})
+		return
+	}
+
+	enrollment, err := ctrl.twoFactor.Enroll(c.Request.Context(), userID.(uint), input.Password, clientInfo(c))
+	if err != nil {
+		respondError(c, err, "服务器内部错误")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "请使用身份验证器扫描二维码，并输入验证码完成开启",
+		"data":    enrollment,
+	})
+}
+
+// 确认开启两步验证
+func (ctrl *TwoFactorController) Confirm(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "message": "未授权访问", "error_code": "UNAUTHORIZED"})
+		return
+	}
+
+	var input TwoFactorCodeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": utils.GetValidationError(err), "error_code": "INVALID_INPUT"})
+		return
+	}
+
+	codes, err := ctrl.twoFactor.Confirm(c.Request.Context(), userID.(uint), input.Code)
+	if err != nil {
+		respondError(c, err, "服务器内部错误")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "两步验证已开启，请妥善保存恢复码",
+		"data":    gin.H{"recovery_codes": codes},
+	})
+}
+
+// 关闭两步验证
+func (ctrl *TwoFactorController) Disable(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "message": "未授权访问", "error_code": "UNAUTHORIZED"})
+		return
+	}
+
+	var input PasswordConfirmInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": utils.GetValidationError(err), "error_code": "INVALID_INPUT"})
+		return
+	}
+
+	if err := ctrl.twoFactor.Disable(c.Request.Context(), userID.(uint), input.Password, clientInfo(c)); err != nil {
+		respondError(c, err, "服务器内部错误")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "两步验证已关闭",
+	})
+}
+
+// 重新生成恢复码
+func (ctrl *TwoFactorController) RegenerateRecoveryCodes(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "message": "未授权访问", "error_code": "UNAUTHORIZED"})
+		return
+	}
+
+	var input PasswordConfirmInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": utils.GetValidationError(err), "error_code": "INVALID_INPUT"})
+		return
+	}
+
+	codes, err := ctrl.twoFactor.RegenerateRecoveryCodes(c.Request.Context(), userID.(uint), input.Password, clientInfo(c))
+	if err != nil {
+		respondError(c, err, "服务器内部错误")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "恢复码已重新生成，之前的恢复码已失效",
+		"data":    gin.H{"recovery_codes": codes},
+	})
+}
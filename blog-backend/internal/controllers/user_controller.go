@@ -1,41 +1,56 @@
 package controllers
 
 import (
+	"bytes"
+	"fmt"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
-	"gorm.io/gorm"
 
-	"blog-backend/config"
-	"blog-backend/internal/models"
+	"blog-backend/internal/services"
+	"blog-backend/internal/utils"
 )
 
+// 用户控制器
+type UserController struct {
+	users    *services.UserService
+	accounts *services.AccountService
+	exports  *services.ExportService
+}
+
+func NewUserController(users *services.UserService, accounts *services.AccountService, exports *services.ExportService) *UserController {
+	return &UserController{users: users, accounts: accounts, exports: exports}
+}
+
 type UpdateUserInput struct {
 	Username string `json:"username"`
 	Avatar   string `json:"avatar"`
 }
 
+type ChangePasswordInput struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required"`
+}
+
+type ChangeEmailInput struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewEmail        string `json:"new_email" binding:"required,email"`
+}
+
 // 获取当前用户信息
-func GetCurrentUser(c *gin.Context) {
+func (ctrl *UserController) GetCurrentUser(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
 		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "message": "未授权访问", "error_code": "UNAUTHORIZED"})
 		return
 	}
 
-	var user models.User
-	if err := config.DB.First(&user, userID).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"success": false, "message": "用户不存在", "error_code": "NOT_FOUND"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "服务器内部错误", "error_code": "INTERNAL_ERROR"})
+	user, err := ctrl.users.Get(c.Request.Context(), userID.(uint))
+	if err != nil {
+		respondError(c, err, "服务器内部错误")
 		return
 	}
 
-	// 隐藏密码字段
-	user.Password = ""
-
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"data":    user,
@@ -43,7 +58,7 @@ func GetCurrentUser(c *gin.Context) {
 }
 
 // 更新用户信息
-func UpdateCurrentUser(c *gin.Context) {
+func (ctrl *UserController) UpdateCurrentUser(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
 		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "message": "未授权访问", "error_code": "UNAUTHORIZED"})
@@ -56,35 +71,121 @@ func UpdateCurrentUser(c *gin.Context) {
 		return
 	}
 
-	var user models.User
-	if err := config.DB.First(&user, userID).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			c.JSON(http.StatusNotFound, gin.H{"success": false, "message": "用户不存在", "error_code": "NOT_FOUND"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "服务器内部错误", "error_code": "INTERNAL_ERROR"})
+	user, err := ctrl.users.UpdateProfile(c.Request.Context(), userID.(uint), services.UpdateProfileParams{
+		Username: input.Username,
+		Avatar:   input.Avatar,
+	})
+	if err != nil {
+		respondError(c, err, "更新失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "更新成功",
+		"data":    user,
+	})
+}
+
+// 修改密码，其他设备上的登录随即失效
+func (ctrl *UserController) ChangePassword(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "message": "未授权访问", "error_code": "UNAUTHORIZED"})
+		return
+	}
+	sessionID, _ := c.Get("session_id")
+
+	var input ChangePasswordInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": utils.GetValidationError(err), "error_code": "INVALID_INPUT"})
+		return
+	}
+
+	err := ctrl.accounts.ChangePassword(c.Request.Context(), userID.(uint), sessionID.(uint), input.CurrentPassword, input.NewPassword, clientInfo(c))
+	if err != nil {
+		respondError(c, err, "修改密码失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "密码已修改",
+	})
+}
+
+// 发起邮箱变更，确认链接发送到新邮箱
+func (ctrl *UserController) ChangeEmail(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "message": "未授权访问", "error_code": "UNAUTHORIZED"})
+		return
+	}
+	sessionID, _ := c.Get("session_id")
+
+	var input ChangeEmailInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": utils.GetValidationError(err), "error_code": "INVALID_INPUT"})
 		return
 	}
 
-	// 更新用户信息
-	if input.Username != "" {
-		user.Username = input.Username
+	err := ctrl.accounts.RequestEmailChange(c.Request.Context(), userID.(uint), sessionID.(uint), input.CurrentPassword, input.NewEmail, clientInfo(c))
+	if err != nil {
+		respondError(c, err, "发送邮件失败")
+		return
 	}
-	if input.Avatar != "" {
-		user.Avatar = input.Avatar
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "确认邮件已发送到新邮箱",
+	})
+}
+
+// 下载个人数据压缩包，包含资料、文章和评论
+func (ctrl *UserController) ExportData(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "message": "未授权访问", "error_code": "UNAUTHORIZED"})
+		return
 	}
 
-	if err := config.DB.Save(&user).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "更新失败", "error_code": "INTERNAL_ERROR"})
+	export, err := ctrl.exports.Export(c.Request.Context(), userID.(uint))
+	if err != nil {
+		respondError(c, err, "导出失败")
+		return
+	}
+	var buf bytes.Buffer
+	if err := export.WriteArchive(&buf); err != nil {
+		respondError(c, err, "导出失败")
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, export.Filename()))
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, "application/zip", buf.Bytes())
+}
+
+// 注销账号，需要验证当前密码
+func (ctrl *UserController) DeleteCurrentUser(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"success": false, "message": "未授权访问", "error_code": "UNAUTHORIZED"})
 		return
 	}
 
-	// 隐藏密码字段
-	user.Password = ""
+	var input PasswordConfirmInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": utils.GetValidationError(err), "error_code": "INVALID_INPUT"})
+		return
+	}
+
+	if err := ctrl.accounts.DeleteAccount(c.Request.Context(), userID.(uint), input.Password, clientInfo(c)); err != nil {
+		respondError(c, err, "注销失败")
+		return
+	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
-		"message": "更新成功",
-		"data":    user,
+		"message": "账号已注销",
 	})
 }
@@ -0,0 +1,34 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+)
+
+// 将邮件写入目录，每封邮件一个 .eml 文件，文件名按发送顺序递增
+type FileMailer struct {
+	from string
+	dir  string
+	seq  atomic.Int64
+}
+
+func NewFileMailer(from, dir string) *FileMailer {
+	return &FileMailer{from: from, dir: dir}
+}
+
+func (m *FileMailer) Send(ctx context.Context, msg Message) error {
+	if err := os.MkdirAll(m.dir, 0o755); err != nil {
+		return fmt.Errorf("创建邮件目录失败: %w", err)
+	}
+
+	now := time.Now()
+	name := fmt.Sprintf("%s-%04d.eml", now.Format("20060102-150405.000000"), m.seq.Add(1))
+	if err := os.WriteFile(filepath.Join(m.dir, name), encode(m.from, msg, now), 0o644); err != nil {
+		return fmt.Errorf("写入邮件失败: %w", err)
+	}
+	return nil
+}
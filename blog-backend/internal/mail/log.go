@@ -0,0 +1,20 @@
+package mail
+
+import (
+	"context"
+	"log"
+)
+
+// 将邮件内容输出到日志，用于未配置邮件服务的本地开发环境
+type LogMailer struct {
+	from string
+}
+
+func NewLogMailer(from string) *LogMailer {
+	return &LogMailer{from: from}
+}
+
+func (m *LogMailer) Send(ctx context.Context, msg Message) error {
+	log.Printf("发送邮件 (未实际投递)\nFrom: %s\nTo: %s\nSubject: %s\n\n%s", m.from, msg.To, msg.Subject, msg.Body)
+	return nil
+}
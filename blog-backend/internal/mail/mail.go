@@ -0,0 +1,51 @@
+// Package mail 提供可替换的邮件发送实现
+package mail
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"time"
+
+	"blog-backend/config"
+)
+
+// 纯文本邮件
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// 邮件发送接口
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// 根据配置创建邮件发送器，驱动名已由 config.Validate 校验
+func New(cfg config.MailConfig) Mailer {
+	switch cfg.Driver {
+	case config.MailDriverSMTP:
+		return NewSMTPMailer(cfg)
+	case config.MailDriverFile:
+		return NewFileMailer(cfg.From, cfg.Dir)
+	default:
+		return NewLogMailer(cfg.From)
+	}
+}
+
+// 按 RFC 5322 编码邮件，主题使用 UTF-8 编码
+func encode(from string, msg Message, date time.Time) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.BEncoding.Encode("UTF-8", msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	buf.WriteString("\r\n")
+	buf.WriteString(msg.Body)
+	return buf.Bytes()
+}
@@ -1,18 +1,42 @@
 package middleware
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v4"
+
+	"blog-backend/internal/auth"
 )
 
-// JWT认证中间件
-func AuthMiddleware() gin.HandlerFunc {
+// 访问令牌校验
+type Authenticator interface {
+	Authenticate(ctx context.Context, accessToken string) (*auth.Claims, error)
+}
+
+// 认证中间件，接受 JWT 和个人访问令牌，令牌所属会话被撤销后令牌立即失效
+//
+// 个人访问令牌只能访问声明了 scopes 的路由，并且需要拥有其中全部权限范围。
+func AuthMiddleware(authenticator Authenticator, scopes ...auth.Scope) gin.HandlerFunc {
+	return authMiddleware(authenticator, scopes, false)
+}
+
+// 可选认证中间件，用于公开的只读接口。未提供认证信息时以匿名身份继续，
+// 个人访问令牌缺少所需权限范围时同样以匿名身份继续，其他情况与 AuthMiddleware 相同
+func OptionalAuthMiddleware(authenticator Authenticator, scopes ...auth.Scope) gin.HandlerFunc {
+	return authMiddleware(authenticator, scopes, true)
+}
+
+func authMiddleware(authenticator Authenticator, scopes []auth.Scope, optional bool) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
+			if optional {
+				c.Next()
+				return
+			}
 			c.JSON(http.StatusUnauthorized, gin.H{"success": false, "message": "未提供认证信息", "error_code": "UNAUTHORIZED"})
 			c.Abort()
 			return
@@ -25,25 +49,43 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			return []byte("your_jwt_secret_key"), nil
-		})
-
-		if err != nil || !token.Valid {
+		claims, err := authenticator.Authenticate(c.Request.Context(), tokenString)
+		if errors.Is(err, auth.ErrInvalidToken) {
 			c.JSON(http.StatusUnauthorized, gin.H{"success": false, "message": "无效的认证令牌", "error_code": "UNAUTHORIZED"})
 			c.Abort()
 			return
 		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"success": false, "message": "服务器内部错误", "error_code": "INTERNAL_ERROR"})
+			c.Abort()
+			return
+		}
 
-		if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-			userID := uint(claims["user_id"].(float64))
-			c.Set("user_id", userID)
-		} else {
-			c.JSON(http.StatusUnauthorized, gin.H{"success": false, "message": "无效的认证令牌", "error_code": "UNAUTHORIZED"})
+		if claims.Personal() && !allowScopes(claims, scopes) {
+			if optional {
+				c.Next()
+				return
+			}
+			c.JSON(http.StatusForbidden, gin.H{"success": false, "message": "访问令牌的权限范围不足", "error_code": "FORBIDDEN"})
 			c.Abort()
 			return
 		}
 
+		c.Set("user_id", claims.UserID)
+		c.Set("session_id", claims.SessionID)
+		c.Set("role", claims.Role)
 		c.Next()
 	}
-}
\ No newline at end of file
+}
+
+func allowScopes(claims *auth.Claims, scopes []auth.Scope) bool {
+	if len(scopes) == 0 {
+		return false
+	}
+	for _, scope := range scopes {
+		if !claims.HasScope(scope) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"blog-backend/internal/auth"
+)
+
+// 要求当前用户为指定角色之一，需放在 AuthMiddleware 之后
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role := c.GetString("role")
+		for _, r := range roles {
+			if r == role {
+				c.Next()
+				return
+			}
+		}
+		forbid(c)
+	}
+}
+
+// 要求当前用户的角色拥有指定权限，需放在 AuthMiddleware 之后
+func RequirePermission(perm auth.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !auth.Can(c.GetString("role"), perm) {
+			forbid(c)
+			return
+		}
+		c.Next()
+	}
+}
+
+func forbid(c *gin.Context) {
+	c.JSON(http.StatusForbidden, gin.H{"success": false, "message": "权限不足", "error_code": "FORBIDDEN"})
+	c.Abort()
+}
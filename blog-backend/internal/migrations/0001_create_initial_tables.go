@@ -0,0 +1,64 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type user0001 struct {
+	ID        uint   `gorm:"primaryKey"`
+	Username  string `gorm:"size:50;not null;unique"`
+	Email     string `gorm:"size:100;not null;unique"`
+	Password  string `gorm:"size:255;not null"`
+	Avatar    string `gorm:"size:255"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (user0001) TableName() string { return "users" }
+
+type article0001 struct {
+	ID        uint   `gorm:"primaryKey"`
+	Title     string `gorm:"size:200;not null"`
+	Content   string `gorm:"type:text;not null"`
+	AuthorID  uint   `gorm:"not null;index"`
+	Views     int    `gorm:"default:0"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (article0001) TableName() string { return "articles" }
+
+type comment0001 struct {
+	ID        uint   `gorm:"primaryKey"`
+	Content   string `gorm:"type:text;not null"`
+	ArticleID uint   `gorm:"not null;index"`
+	AuthorID  uint   `gorm:"not null;index"`
+	CreatedAt time.Time
+}
+
+func (comment0001) TableName() string { return "comments" }
+
+// 初始表结构
+//
+// 之前的版本在启动时通过 AutoMigrate 建表，已存在的表会被跳过，
+// 这样旧数据库执行本迁移后即可纳入版本管理。
+var createInitialTables = Migration{
+	Version: 1,
+	Name:    "create_initial_tables",
+	Up: func(tx *gorm.DB) error {
+		for _, table := range []interface{}{&user0001{}, &article0001{}, &comment0001{}} {
+			if tx.Migrator().HasTable(table) {
+				continue
+			}
+			if err := tx.Migrator().CreateTable(table); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&comment0001{}, &article0001{}, &user0001{})
+	},
+}
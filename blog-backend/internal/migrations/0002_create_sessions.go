@@ -0,0 +1,40 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type session0002 struct {
+	ID        uint `gorm:"primaryKey"`
+	UserID    uint `gorm:"not null;index"`
+	RevokedAt *time.Time
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (session0002) TableName() string { return "sessions" }
+
+type refreshToken0002 struct {
+	ID        uint      `gorm:"primaryKey"`
+	SessionID uint      `gorm:"not null;index"`
+	TokenHash string    `gorm:"size:64;not null;uniqueIndex"`
+	ExpiresAt time.Time `gorm:"not null"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+func (refreshToken0002) TableName() string { return "refresh_tokens" }
+
+// 登录会话与刷新令牌
+var createSessions = Migration{
+	Version: 2,
+	Name:    "create_sessions",
+	Up: func(tx *gorm.DB) error {
+		return tx.Migrator().CreateTable(&session0002{}, &refreshToken0002{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&refreshToken0002{}, &session0002{})
+	},
+}
@@ -0,0 +1,43 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type session0003 struct {
+	UserAgent  string `gorm:"size:255"`
+	IP         string `gorm:"size:45"`
+	LastSeenAt time.Time
+	ExpiresAt  time.Time
+}
+
+func (session0003) TableName() string { return "sessions" }
+
+// 会话的设备信息、最后活跃时间和过期时间
+var addSessionDetails = Migration{
+	Version: 3,
+	Name:    "add_session_details",
+	Up: func(tx *gorm.DB) error {
+		for _, column := range []string{"UserAgent", "IP", "LastSeenAt", "ExpiresAt"} {
+			if err := tx.Migrator().AddColumn(&session0003{}, column); err != nil {
+				return err
+			}
+		}
+
+		// 已有会话的过期时间取最新刷新令牌的过期时间
+		if err := tx.Exec("UPDATE sessions SET last_seen_at = updated_at, expires_at = (SELECT MAX(expires_at) FROM refresh_tokens WHERE refresh_tokens.session_id = sessions.id)").Error; err != nil {
+			return err
+		}
+		return tx.Exec("UPDATE sessions SET expires_at = created_at WHERE expires_at IS NULL").Error
+	},
+	Down: func(tx *gorm.DB) error {
+		for _, column := range []string{"UserAgent", "IP", "LastSeenAt", "ExpiresAt"} {
+			if err := dropColumn(tx, &session0003{}, column); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
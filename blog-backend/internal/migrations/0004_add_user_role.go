@@ -0,0 +1,23 @@
+package migrations
+
+import (
+	"gorm.io/gorm"
+)
+
+type user0004 struct {
+	Role string `gorm:"size:20;not null;default:author"`
+}
+
+func (user0004) TableName() string { return "users" }
+
+// 用户角色，已有用户默认为 author
+var addUserRole = Migration{
+	Version: 4,
+	Name:    "add_user_role",
+	Up: func(tx *gorm.DB) error {
+		return tx.Migrator().AddColumn(&user0004{}, "Role")
+	},
+	Down: func(tx *gorm.DB) error {
+		return dropColumn(tx, &user0004{}, "Role")
+	},
+}
@@ -0,0 +1,33 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type auditLog0005 struct {
+	ID         uint   `gorm:"primaryKey"`
+	ActorID    uint   `gorm:"not null;index"`
+	ActorRole  string `gorm:"size:20;not null"`
+	Action     string `gorm:"size:50;not null;index"`
+	TargetType string `gorm:"size:50;not null"`
+	TargetID   uint   `gorm:"not null"`
+	OwnerID    uint   `gorm:"not null"`
+	Detail     string `gorm:"size:500"`
+	CreatedAt  time.Time
+}
+
+func (auditLog0005) TableName() string { return "audit_logs" }
+
+// 越权操作审计日志
+var createAuditLogs = Migration{
+	Version: 5,
+	Name:    "create_audit_logs",
+	Up: func(tx *gorm.DB) error {
+		return tx.Migrator().CreateTable(&auditLog0005{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&auditLog0005{})
+	},
+}
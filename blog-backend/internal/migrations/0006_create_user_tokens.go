@@ -0,0 +1,31 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type userToken0006 struct {
+	ID        uint      `gorm:"primaryKey"`
+	UserID    uint      `gorm:"not null;index"`
+	Purpose   string    `gorm:"size:30;not null"`
+	TokenHash string    `gorm:"size:64;not null;uniqueIndex"`
+	ExpiresAt time.Time `gorm:"not null"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+func (userToken0006) TableName() string { return "user_tokens" }
+
+// 密码重置等邮件一次性令牌
+var createUserTokens = Migration{
+	Version: 6,
+	Name:    "create_user_tokens",
+	Up: func(tx *gorm.DB) error {
+		return tx.Migrator().CreateTable(&userToken0006{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&userToken0006{})
+	},
+}
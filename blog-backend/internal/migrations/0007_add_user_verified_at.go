@@ -0,0 +1,28 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type user0007 struct {
+	VerifiedAt *time.Time
+}
+
+func (user0007) TableName() string { return "users" }
+
+// 邮箱验证状态，已有用户视为已验证
+var addUserVerifiedAt = Migration{
+	Version: 7,
+	Name:    "add_user_verified_at",
+	Up: func(tx *gorm.DB) error {
+		if err := tx.Migrator().AddColumn(&user0007{}, "VerifiedAt"); err != nil {
+			return err
+		}
+		return tx.Exec("UPDATE users SET verified_at = created_at").Error
+	},
+	Down: func(tx *gorm.DB) error {
+		return dropColumn(tx, &user0007{}, "VerifiedAt")
+	},
+}
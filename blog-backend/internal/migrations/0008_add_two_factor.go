@@ -0,0 +1,50 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type user0008 struct {
+	TOTPEnabledAt *time.Time `gorm:"column:totp_enabled_at"`
+	TOTPSecret    string     `gorm:"column:totp_secret;size:64"`
+	TOTPLastStep  int64      `gorm:"column:totp_last_step;not null;default:0"`
+}
+
+func (user0008) TableName() string { return "users" }
+
+type recoveryCode0008 struct {
+	ID        uint   `gorm:"primaryKey"`
+	UserID    uint   `gorm:"not null;index"`
+	CodeHash  string `gorm:"size:64;not null"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+func (recoveryCode0008) TableName() string { return "recovery_codes" }
+
+// TOTP 两步验证与恢复码
+var addTwoFactor = Migration{
+	Version: 8,
+	Name:    "add_two_factor",
+	Up: func(tx *gorm.DB) error {
+		for _, column := range []string{"TOTPEnabledAt", "TOTPSecret", "TOTPLastStep"} {
+			if err := tx.Migrator().AddColumn(&user0008{}, column); err != nil {
+				return err
+			}
+		}
+		return tx.Migrator().CreateTable(&recoveryCode0008{})
+	},
+	Down: func(tx *gorm.DB) error {
+		if err := tx.Migrator().DropTable(&recoveryCode0008{}); err != nil {
+			return err
+		}
+		for _, column := range []string{"TOTPEnabledAt", "TOTPSecret", "TOTPLastStep"} {
+			if err := dropColumn(tx, &user0008{}, column); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
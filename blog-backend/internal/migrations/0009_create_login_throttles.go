@@ -0,0 +1,29 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type loginThrottle0009 struct {
+	ID           uint   `gorm:"primaryKey"`
+	Subject      string `gorm:"size:255;not null;uniqueIndex"`
+	Failures     int    `gorm:"not null;default:0"`
+	LockedUntil  *time.Time
+	LastFailedAt time.Time `gorm:"not null"`
+}
+
+func (loginThrottle0009) TableName() string { return "login_throttles" }
+
+// 按账号和 IP 记录登录失败次数
+var createLoginThrottles = Migration{
+	Version: 9,
+	Name:    "create_login_throttles",
+	Up: func(tx *gorm.DB) error {
+		return tx.Migrator().CreateTable(&loginThrottle0009{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&loginThrottle0009{})
+	},
+}
@@ -0,0 +1,32 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type personalToken0010 struct {
+	ID         uint   `gorm:"primaryKey"`
+	UserID     uint   `gorm:"not null;index"`
+	Name       string `gorm:"size:100;not null"`
+	TokenHash  string `gorm:"size:64;not null;uniqueIndex"`
+	Scopes     string `gorm:"size:255;not null"`
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	CreatedAt  time.Time
+}
+
+func (personalToken0010) TableName() string { return "personal_tokens" }
+
+// 个人访问令牌
+var createPersonalTokens = Migration{
+	Version: 10,
+	Name:    "create_personal_tokens",
+	Up: func(tx *gorm.DB) error {
+		return tx.Migrator().CreateTable(&personalToken0010{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&personalToken0010{})
+	},
+}
@@ -0,0 +1,46 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type userIdentity0011 struct {
+	ID        uint   `gorm:"primaryKey"`
+	UserID    uint   `gorm:"not null;index"`
+	Issuer    string `gorm:"size:255;not null;uniqueIndex:idx_identity_subject"`
+	Subject   string `gorm:"size:255;not null;uniqueIndex:idx_identity_subject"`
+	Email     string `gorm:"size:100"`
+	CreatedAt time.Time
+}
+
+func (userIdentity0011) TableName() string { return "user_identities" }
+
+type pendingLogin0011 struct {
+	ID           uint      `gorm:"primaryKey"`
+	StateHash    string    `gorm:"size:64;not null;uniqueIndex"`
+	CodeVerifier string    `gorm:"size:128;not null"`
+	Nonce        string    `gorm:"size:64;not null"`
+	ExpiresAt    time.Time `gorm:"not null;index"`
+	CreatedAt    time.Time
+}
+
+func (pendingLogin0011) TableName() string { return "pending_logins" }
+
+// OIDC 外部身份关联和登录请求
+var createUserIdentities = Migration{
+	Version: 11,
+	Name:    "create_user_identities",
+	Up: func(tx *gorm.DB) error {
+		for _, table := range []interface{}{&userIdentity0011{}, &pendingLogin0011{}} {
+			if err := tx.Migrator().CreateTable(table); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&pendingLogin0011{}, &userIdentity0011{})
+	},
+}
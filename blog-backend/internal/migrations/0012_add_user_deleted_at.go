@@ -0,0 +1,25 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type user0012 struct {
+	DeletedAt *time.Time
+}
+
+func (user0012) TableName() string { return "users" }
+
+// 账号注销时间，匿名化注销的用户保留为已注销状态
+var addUserDeletedAt = Migration{
+	Version: 12,
+	Name:    "add_user_deleted_at",
+	Up: func(tx *gorm.DB) error {
+		return tx.Migrator().AddColumn(&user0012{}, "DeletedAt")
+	},
+	Down: func(tx *gorm.DB) error {
+		return dropColumn(tx, &user0012{}, "DeletedAt")
+	},
+}
@@ -0,0 +1,43 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type article0013 struct {
+	Status      string `gorm:"size:20;not null;default:published;index"`
+	PublishedAt *time.Time
+}
+
+func (article0013) TableName() string { return "articles" }
+
+// 文章状态和发布时间，已有文章视为在创建时发布
+var addArticleStatus = Migration{
+	Version: 13,
+	Name:    "add_article_status",
+	Up: func(tx *gorm.DB) error {
+		m := tx.Migrator()
+		if err := m.AddColumn(&article0013{}, "Status"); err != nil {
+			return err
+		}
+		if err := m.AddColumn(&article0013{}, "PublishedAt"); err != nil {
+			return err
+		}
+		if err := m.CreateIndex(&article0013{}, "Status"); err != nil {
+			return err
+		}
+		return tx.Exec("UPDATE articles SET status = 'published', published_at = created_at").Error
+	},
+	Down: func(tx *gorm.DB) error {
+		m := tx.Migrator()
+		if err := m.DropIndex(&article0013{}, "Status"); err != nil {
+			return err
+		}
+		if err := dropColumn(tx, &article0013{}, "PublishedAt"); err != nil {
+			return err
+		}
+		return dropColumn(tx, &article0013{}, "Status")
+	},
+}
@@ -0,0 +1,33 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type article0014 struct {
+	PublishAt *time.Time `gorm:"index"`
+}
+
+func (article0014) TableName() string { return "articles" }
+
+// 草稿的定时发布时间
+var addArticlePublishAt = Migration{
+	Version: 14,
+	Name:    "add_article_publish_at",
+	Up: func(tx *gorm.DB) error {
+		m := tx.Migrator()
+		if err := m.AddColumn(&article0014{}, "PublishAt"); err != nil {
+			return err
+		}
+		return m.CreateIndex(&article0014{}, "PublishAt")
+	},
+	Down: func(tx *gorm.DB) error {
+		m := tx.Migrator()
+		if err := m.DropIndex(&article0014{}, "PublishAt"); err != nil {
+			return err
+		}
+		return dropColumn(tx, &article0014{}, "PublishAt")
+	},
+}
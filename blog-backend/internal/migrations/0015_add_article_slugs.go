@@ -0,0 +1,64 @@
+package migrations
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type article0015 struct {
+	ID   uint
+	Slug string `gorm:"size:100;not null;default:'';uniqueIndex"`
+}
+
+func (article0015) TableName() string { return "articles" }
+
+type articleSlug0015 struct {
+	ID        uint   `gorm:"primaryKey"`
+	ArticleID uint   `gorm:"not null;index"`
+	Slug      string `gorm:"size:100;not null;uniqueIndex"`
+	CreatedAt time.Time
+}
+
+func (articleSlug0015) TableName() string { return "article_slugs" }
+
+// 文章 slug 和历史 slug
+//
+// 已有文章先使用 article-<id> 作为临时 slug，迁移不依赖会随版本变化的 slug 生成规则，
+// 执行迁移后由 migrate up 按当前规则从标题重新生成。
+var addArticleSlugs = Migration{
+	Version: 15,
+	Name:    "add_article_slugs",
+	Up: func(tx *gorm.DB) error {
+		m := tx.Migrator()
+		if err := m.AddColumn(&article0015{}, "Slug"); err != nil {
+			return err
+		}
+
+		var articles []article0015
+		if err := tx.Select("id").Order("id").Find(&articles).Error; err != nil {
+			return err
+		}
+		for _, a := range articles {
+			if err := tx.Model(&article0015{}).Where("id = ?", a.ID).Update("slug", fmt.Sprintf("article-%d", a.ID)).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := m.CreateIndex(&article0015{}, "Slug"); err != nil {
+			return err
+		}
+		return m.CreateTable(&articleSlug0015{})
+	},
+	Down: func(tx *gorm.DB) error {
+		m := tx.Migrator()
+		if err := m.DropTable(&articleSlug0015{}); err != nil {
+			return err
+		}
+		if err := m.DropIndex(&article0015{}, "Slug"); err != nil {
+			return err
+		}
+		return dropColumn(tx, &article0015{}, "Slug")
+	},
+}
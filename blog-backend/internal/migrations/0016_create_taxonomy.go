@@ -0,0 +1,67 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type tag0016 struct {
+	ID        uint   `gorm:"primaryKey"`
+	Name      string `gorm:"size:50;not null"`
+	Slug      string `gorm:"size:100;not null;uniqueIndex"`
+	CreatedAt time.Time
+}
+
+func (tag0016) TableName() string { return "tags" }
+
+type articleTag0016 struct {
+	ArticleID uint `gorm:"primaryKey;autoIncrement:false"`
+	TagID     uint `gorm:"primaryKey;autoIncrement:false;index"`
+}
+
+func (articleTag0016) TableName() string { return "article_tags" }
+
+type category0016 struct {
+	ID          uint   `gorm:"primaryKey"`
+	Name        string `gorm:"size:50;not null"`
+	Slug        string `gorm:"size:100;not null;uniqueIndex"`
+	Description string `gorm:"size:500"`
+	ParentID    *uint  `gorm:"index"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (category0016) TableName() string { return "categories" }
+
+type article0016 struct {
+	CategoryID *uint `gorm:"index"`
+}
+
+func (article0016) TableName() string { return "articles" }
+
+// 标签、分类和文章所属分类
+var createTaxonomy = Migration{
+	Version: 16,
+	Name:    "create_taxonomy",
+	Up: func(tx *gorm.DB) error {
+		m := tx.Migrator()
+		if err := m.CreateTable(&tag0016{}, &articleTag0016{}, &category0016{}); err != nil {
+			return err
+		}
+		if err := m.AddColumn(&article0016{}, "CategoryID"); err != nil {
+			return err
+		}
+		return m.CreateIndex(&article0016{}, "CategoryID")
+	},
+	Down: func(tx *gorm.DB) error {
+		m := tx.Migrator()
+		if err := m.DropIndex(&article0016{}, "CategoryID"); err != nil {
+			return err
+		}
+		if err := dropColumn(tx, &article0016{}, "CategoryID"); err != nil {
+			return err
+		}
+		return m.DropTable(&category0016{}, &articleTag0016{}, &tag0016{})
+	},
+}
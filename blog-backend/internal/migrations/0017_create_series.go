@@ -0,0 +1,38 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type series0017 struct {
+	ID          uint   `gorm:"primaryKey"`
+	Title       string `gorm:"size:200;not null"`
+	Description string `gorm:"size:500"`
+	AuthorID    uint   `gorm:"not null;index"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (series0017) TableName() string { return "series" }
+
+type seriesArticle0017 struct {
+	ArticleID uint `gorm:"primaryKey;autoIncrement:false"`
+	SeriesID  uint `gorm:"not null;index"`
+	Position  int  `gorm:"not null"`
+}
+
+func (seriesArticle0017) TableName() string { return "series_articles" }
+
+// 系列文章
+var createSeries = Migration{
+	Version: 17,
+	Name:    "create_series",
+	Up: func(tx *gorm.DB) error {
+		return tx.Migrator().CreateTable(&series0017{}, &seriesArticle0017{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&seriesArticle0017{}, &series0017{})
+	},
+}
@@ -0,0 +1,248 @@
+package migrations
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 数据库结构落后于代码时返回
+var ErrSchemaBehind = errors.New("数据库结构版本落后，请先执行迁移")
+
+// 一次数据库结构变更
+//
+// Up/Down 在同一个事务中执行并记录版本号。迁移内部应使用迁移文件中定义的结构体快照，
+// 不要直接引用 internal/models，否则模型后续的改动会改变历史迁移的行为。
+type Migration struct {
+	Version int64
+	Name    string
+	Up      func(tx *gorm.DB) error
+	Down    func(tx *gorm.DB) error
+}
+
+// 已执行的迁移记录
+type SchemaMigration struct {
+	Version   int64     `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"size:255;not null"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// 迁移状态
+type Status struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// 全部迁移，按版本号升序排列，新增迁移时在末尾追加
+func All() []Migration {
+	return []Migration{
+		createInitialTables,
+		createSessions,
+		addSessionDetails,
+		addUserRole,
+		createAuditLogs,
+		createUserTokens,
+		addUserVerifiedAt,
+		addTwoFactor,
+		createLoginThrottles,
+		createPersonalTokens,
+		createUserIdentities,
+		addUserDeletedAt,
+		addArticleStatus,
+		addArticlePublishAt,
+		addArticleSlugs,
+		createTaxonomy,
+		createSeries,
+	}
+}
+
+// 迁移执行器
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+}
+
+func New(db *gorm.DB) *Migrator {
+	return NewWithMigrations(db, All())
+}
+
+func NewWithMigrations(db *gorm.DB, migrations []Migration) *Migrator {
+	sorted := append([]Migration(nil), migrations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Version < sorted[j].Version })
+	return &Migrator{db: db, migrations: sorted}
+}
+
+// 执行全部未执行的迁移，返回本次执行的迁移
+func (m *Migrator) Up() ([]Migration, error) {
+	pending, err := m.Pending()
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for _, migration := range pending {
+		err := m.db.Transaction(func(tx *gorm.DB) error {
+			if err := migration.Up(tx); err != nil {
+				return err
+			}
+			return tx.Create(&SchemaMigration{Version: migration.Version, Name: migration.Name, AppliedAt: time.Now()}).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("迁移 %d_%s 执行失败: %w", migration.Version, migration.Name, err)
+		}
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+// 回滚最近执行的 steps 个迁移，返回本次回滚的迁移
+func (m *Migrator) Down(steps int) ([]Migration, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for i := len(m.migrations) - 1; i >= 0 && len(done) < steps; i-- {
+		migration := m.migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+		if migration.Down == nil {
+			return done, fmt.Errorf("迁移 %d_%s 不支持回滚", migration.Version, migration.Name)
+		}
+
+		err := m.db.Transaction(func(tx *gorm.DB) error {
+			if err := migration.Down(tx); err != nil {
+				return err
+			}
+			return tx.Delete(&SchemaMigration{}, migration.Version).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("迁移 %d_%s 回滚失败: %w", migration.Version, migration.Name, err)
+		}
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+// 全部迁移的执行状态
+func (m *Migrator) Status() ([]Status, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := Status{Version: migration.Version, Name: migration.Name}
+		if record, ok := applied[migration.Version]; ok {
+			status.Applied = true
+			status.AppliedAt = record.AppliedAt
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// 尚未执行的迁移
+func (m *Migrator) Pending() ([]Migration, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []Migration
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; !ok {
+			pending = append(pending, migration)
+		}
+	}
+	return pending, nil
+}
+
+// 检查数据库结构是否为最新版本
+func (m *Migrator) EnsureUpToDate() error {
+	pending, err := m.Pending()
+	if err != nil {
+		return err
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("%w: 有 %d 个迁移未执行 (最早为 %d_%s)", ErrSchemaBehind, len(pending), pending[0].Version, pending[0].Name)
+	}
+	return nil
+}
+
+// 已执行的迁移，按版本号索引
+func (m *Migrator) applied() (map[int64]SchemaMigration, error) {
+	if err := m.db.AutoMigrate(&SchemaMigration{}); err != nil {
+		return nil, fmt.Errorf("创建 schema_migrations 表失败: %w", err)
+	}
+
+	var records []SchemaMigration
+	if err := m.db.Find(&records).Error; err != nil {
+		return nil, err
+	}
+
+	applied := make(map[int64]SchemaMigration, len(records))
+	for _, record := range records {
+		applied[record.Version] = record
+	}
+	return applied, nil
+}
+
+// 删除表中的列
+//
+// SQLite 驱动删除列时会重建整张表，表上其他列的索引随之丢失。这里先记录表上的索引，
+// 删除列后重新创建其中不涉及被删除列且已不存在的索引。
+func dropColumn(tx *gorm.DB, model interface{}, field string) error {
+	m := tx.Migrator()
+	if tx.Dialector.Name() != "sqlite" {
+		return m.DropColumn(model, field)
+	}
+
+	stmt := &gorm.Statement{DB: tx}
+	if err := stmt.Parse(model); err != nil {
+		return err
+	}
+	column := field
+	if f := stmt.Schema.LookUpField(field); f != nil {
+		column = f.DBName
+	}
+
+	var indexes []struct {
+		Name string
+		SQL  string
+	}
+	err := tx.Raw("SELECT name, sql FROM sqlite_master WHERE type = 'index' AND tbl_name = ? AND sql IS NOT NULL", stmt.Schema.Table).
+		Scan(&indexes).Error
+	if err != nil {
+		return err
+	}
+
+	if err := m.DropColumn(model, field); err != nil {
+		return err
+	}
+	for _, index := range indexes {
+		if strings.Contains(index.SQL, "`"+column+"`") || strings.Contains(index.SQL, `"`+column+`"`) {
+			continue
+		}
+		if m.HasIndex(stmt.Schema.Table, index.Name) {
+			continue
+		}
+		if err := tx.Exec(index.SQL).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package migrations_test
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+
+	"blog-backend/config"
+	"blog-backend/internal/database"
+	"blog-backend/internal/migrations"
+)
+
+func openDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	cfg := config.Default().Database
+	cfg.Driver = config.DriverSQLite
+	cfg.Name = ":memory:"
+	db, err := database.Open(cfg)
+	if err != nil {
+		t.Fatalf("打开测试数据库失败: %v", err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return db
+}
+
+// 数据库结构：每张表的列和索引，不包括 schema_migrations
+func schema(t *testing.T, db *gorm.DB) []string {
+	t.Helper()
+	var objects []struct {
+		Type    string
+		Name    string
+		TblName string
+	}
+	err := db.Raw("SELECT type, name, tbl_name FROM sqlite_master WHERE type IN ('table', 'index') AND name NOT LIKE 'sqlite_%' AND tbl_name <> 'schema_migrations'").
+		Scan(&objects).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var result []string
+	for _, o := range objects {
+		if o.Type == "index" {
+			result = append(result, fmt.Sprintf("index %s.%s", o.TblName, o.Name))
+			continue
+		}
+		var columns []string
+		if err := db.Raw("SELECT name FROM pragma_table_info(?)", o.Name).Scan(&columns).Error; err != nil {
+			t.Fatal(err)
+		}
+		sort.Strings(columns)
+		result = append(result, fmt.Sprintf("table %s(%s)", o.Name, strings.Join(columns, ",")))
+	}
+	sort.Strings(result)
+	return result
+}
+
+func TestMigrationsRoundTrip(t *testing.T) {
+	db := openDB(t)
+	m := migrations.New(db)
+	all := migrations.All()
+
+	if _, err := m.Up(); err != nil {
+		t.Fatal(err)
+	}
+	want := schema(t, db)
+
+	// 逐个回滚到空数据库，每一步都必须成功
+	for i := len(all) - 1; i >= 0; i-- {
+		if _, err := m.Down(1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if left := schema(t, db); len(left) != 0 {
+		t.Fatalf("全部回滚后仍有残留: %v", left)
+	}
+	pending, err := m.Pending()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != len(all) {
+		t.Fatalf("全部回滚后应有 %d 个未执行的迁移，实际 %d", len(all), len(pending))
+	}
+
+	if _, err := m.Up(); err != nil {
+		t.Fatal(err)
+	}
+	if got := schema(t, db); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("重新迁移后结构不一致:\n%s\n期望:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+// 每个迁移回滚后再执行，结构与回滚前一致
+func TestMigrationsStepRoundTrip(t *testing.T) {
+	db := openDB(t)
+	m := migrations.New(db)
+	all := migrations.All()
+
+	if _, err := m.Up(); err != nil {
+		t.Fatal(err)
+	}
+	for i := len(all) - 1; i >= 0; i-- {
+		// 只包含到当前迁移为止，重新执行时不会带上已回滚的后续迁移
+		m := migrations.NewWithMigrations(db, all[:i+1])
+		before := schema(t, db)
+		if _, err := m.Down(1); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := m.Up(); err != nil {
+			t.Fatal(err)
+		}
+		if got := schema(t, db); strings.Join(got, "\n") != strings.Join(before, "\n") {
+			t.Fatalf("迁移 %d_%s 回滚后重新执行，结构不一致:\n%s\n期望:\n%s", all[i].Version, all[i].Name, strings.Join(got, "\n"), strings.Join(before, "\n"))
+		}
+		if _, err := m.Down(1); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// 已有文章使用不依赖 slug 生成规则的临时 slug
+func TestArticleSlugsBackfill(t *testing.T) {
+	db := openDB(t)
+	all := migrations.All()
+	i := sort.Search(len(all), func(i int) bool { return all[i].Version >= 15 })
+	if _, err := migrations.NewWithMigrations(db, all[:i]).Up(); err != nil {
+		t.Fatal(err)
+	}
+	for _, title := range []string{"Go 语言入门", "Go 语言入门"} {
+		err := db.Exec("INSERT INTO articles (title, content, author_id, created_at, updated_at) VALUES (?, '', 1, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)", title).Error
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := migrations.New(db).Up(); err != nil {
+		t.Fatal(err)
+	}
+	var slugs []string
+	if err := db.Raw("SELECT slug FROM articles ORDER BY id").Scan(&slugs).Error; err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(slugs, ",") != "article-1,article-2" {
+		t.Fatalf("期望临时 slug article-1,article-2，实际 %v", slugs)
+	}
+}
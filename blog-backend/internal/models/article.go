@@ -4,6 +4,16 @@ import (
 	"time"
 )
 
+// 文章状态
+const (
+	// 草稿，只有作者和编辑可见
+	ArticleDraft = "draft"
+	// 已发布，所有人可见
+	ArticlePublished = "published"
+	// 已归档，不出现在文章列表中，仍可通过链接访问
+	ArticleArchived = "archived"
+)
+
 type Article struct {
 	ID        uint      `gorm:"primaryKey" json:"id"`
 	Title     string    `gorm:"size:200;not null" json:"title"`
@@ -14,4 +24,44 @@ type Article struct {
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 	Comments  []Comment `gorm:"foreignKey:ArticleID" json:"comments"`
-}
\ No newline at end of file
+
+	Status string `gorm:"size:20;not null;default:published;index" json:"status"`
+	// 首次发布时间，从未发布过时为空
+	PublishedAt *time.Time `json:"published_at"`
+	// 定时发布时间，只对草稿有效，到期后由后台任务发布
+	PublishAt *time.Time `gorm:"index" json:"publish_at"`
+	// 由标题生成的 URL 标识，标题修改后随之更新
+	Slug string `gorm:"size:100;not null;uniqueIndex" json:"slug"`
+
+	Tags []Tag `gorm:"many2many:article_tags" json:"tags"`
+	// 所属分类，未分类时为空
+	CategoryID *uint     `gorm:"index" json:"category_id"`
+	Category   *Category `gorm:"foreignKey:CategoryID" json:"category"`
+}
+
+// 文章以前使用过的 slug，访问时重定向到当前 slug
+type ArticleSlug struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	ArticleID uint      `gorm:"not null;index" json:"article_id"`
+	Slug      string    `gorm:"size:100;not null;uniqueIndex" json:"slug"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// 是否为合法的文章状态
+func ValidArticleStatus(status string) bool {
+	switch status {
+	case ArticleDraft, ArticlePublished, ArticleArchived:
+		return true
+	}
+	return false
+}
+
+// 是否为草稿
+func (a *Article) Draft() bool {
+	return a.Status == ArticleDraft
+}
+
+// 是否为等待定时发布的草稿
+func (a *Article) Scheduled() bool {
+	return a.Draft() && a.PublishAt != nil
+}
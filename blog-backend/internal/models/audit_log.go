@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+)
+
+// 审计日志的操作类型
+const (
+	AuditArticleUpdate = "article.update"
+	AuditArticleDelete = "article.delete"
+	AuditArticleStatus = "article.status"
+	AuditCommentDelete = "comment.delete"
+	AuditSeriesUpdate  = "series.update"
+	AuditSeriesDelete  = "series.delete"
+	AuditUserRole      = "user.role"
+	AuditUserUnlock    = "user.unlock"
+)
+
+// 审计日志，记录管理员和编辑越过所有权检查的操作
+type AuditLog struct {
+	ID uint `gorm:"primaryKey" json:"id"`
+	// 操作人，0 表示命令行工具
+	ActorID    uint   `gorm:"not null;index" json:"actor_id"`
+	ActorRole  string `gorm:"size:20;not null" json:"actor_role"`
+	Action     string `gorm:"size:50;not null;index" json:"action"`
+	TargetType string `gorm:"size:50;not null" json:"target_type"`
+	TargetID   uint   `gorm:"not null" json:"target_id"`
+	// 被操作资源的所有者
+	OwnerID   uint      `gorm:"not null" json:"owner_id"`
+	Detail    string    `gorm:"size:500" json:"detail"`
+	CreatedAt time.Time `json:"created_at"`
+}
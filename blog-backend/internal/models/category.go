@@ -0,0 +1,17 @@
+package models
+
+import (
+	"time"
+)
+
+// 文章分类，通过 ParentID 组成树形结构
+type Category struct {
+	ID          uint   `gorm:"primaryKey" json:"id"`
+	Name        string `gorm:"size:50;not null" json:"name"`
+	Slug        string `gorm:"size:100;not null;uniqueIndex" json:"slug"`
+	Description string `gorm:"size:500" json:"description"`
+	// 上级分类，顶级分类为空
+	ParentID  *uint     `gorm:"index" json:"parent_id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
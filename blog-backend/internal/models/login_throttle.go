@@ -0,0 +1,36 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// 登录失败计数，按账号和客户端 IP 分别记录
+type LoginThrottle struct {
+	ID uint `gorm:"primaryKey" json:"id"`
+	// 限流对象，见 AccountThrottle 和 IPThrottle
+	Subject  string `gorm:"size:255;not null;uniqueIndex" json:"subject"`
+	Failures int    `gorm:"not null;default:0" json:"failures"`
+	// 锁定截止时间，为空表示未锁定
+	LockedUntil  *time.Time `json:"locked_until,omitempty"`
+	LastFailedAt time.Time  `gorm:"not null" json:"last_failed_at"`
+}
+
+// 账号维度的限流对象，邮箱不区分大小写，未注册的邮箱同样计数
+func AccountThrottle(email string) string {
+	subject := "account:" + strings.ToLower(strings.TrimSpace(email))
+	if len(subject) > 255 {
+		subject = subject[:255]
+	}
+	return subject
+}
+
+// 客户端 IP 维度的限流对象
+func IPThrottle(ip string) string {
+	return "ip:" + ip
+}
+
+// 当前是否处于锁定期
+func (t *LoginThrottle) Locked(now time.Time) bool {
+	return t.LockedUntil != nil && t.LockedUntil.After(now)
+}
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// 个人访问令牌，供脚本和 CI 使用，只保存摘要
+type PersonalToken struct {
+	ID        uint   `gorm:"primaryKey" json:"id"`
+	UserID    uint   `gorm:"not null;index" json:"user_id"`
+	Name      string `gorm:"size:100;not null" json:"name"`
+	TokenHash string `gorm:"size:64;not null;uniqueIndex" json:"-"`
+	// 空格分隔的权限范围
+	Scopes string `gorm:"size:255;not null" json:"-"`
+	// 为空表示永不过期
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+func (t *PersonalToken) ScopeList() []string {
+	return strings.Fields(t.Scopes)
+}
+
+// 令牌是否已过期
+func (t *PersonalToken) Expired(now time.Time) bool {
+	return t.ExpiresAt != nil && !now.Before(*t.ExpiresAt)
+}
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+)
+
+// 系列文章，将同一作者的多篇文章按顺序组织在一起
+type Series struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	Title       string    `gorm:"size:200;not null" json:"title"`
+	Description string    `gorm:"size:500" json:"description"`
+	AuthorID    uint      `gorm:"not null;index" json:"author_id"`
+	Author      User      `gorm:"foreignKey:AuthorID" json:"author"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// 系列中的文章，每篇文章最多属于一个系列
+type SeriesArticle struct {
+	ArticleID uint `gorm:"primaryKey;autoIncrement:false"`
+	SeriesID  uint `gorm:"not null;index"`
+	// 在系列中的顺序，从 1 开始
+	Position int `gorm:"not null"`
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+)
+
+// 登录会话，每次登录创建一个会话，会话内的刷新令牌轮换使用
+type Session struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	UserID     uint      `gorm:"not null;index" json:"user_id"`
+	UserAgent  string    `gorm:"size:255" json:"user_agent"`
+	IP         string    `gorm:"size:45" json:"ip"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	// 最新刷新令牌的过期时间，过期后会话不再有效
+	ExpiresAt time.Time  `json:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// 会话是否已被撤销
+func (s *Session) Revoked() bool {
+	return s.RevokedAt != nil
+}
+
+// 会话是否仍然有效
+func (s *Session) Active(now time.Time) bool {
+	return !s.Revoked() && now.Before(s.ExpiresAt)
+}
+
+// 刷新令牌，仅保存 SHA-256 摘要，每个令牌只能使用一次
+type RefreshToken struct {
+	ID        uint      `gorm:"primaryKey"`
+	SessionID uint      `gorm:"not null;index"`
+	TokenHash string    `gorm:"size:64;not null;uniqueIndex"`
+	ExpiresAt time.Time `gorm:"not null"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
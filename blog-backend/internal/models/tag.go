@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+)
+
+// 文章标签，名称不区分大小写
+type Tag struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Name      string    `gorm:"size:50;not null" json:"name"`
+	Slug      string    `gorm:"size:100;not null;uniqueIndex" json:"slug"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// 文章和标签的多对多关联
+type ArticleTag struct {
+	ArticleID uint `gorm:"primaryKey;autoIncrement:false"`
+	TagID     uint `gorm:"primaryKey;autoIncrement:false;index"`
+}
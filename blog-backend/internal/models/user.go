@@ -1,17 +1,85 @@
 package models
 
 import (
+	"fmt"
+	"strings"
 	"time"
 )
 
+// 用户角色
+const (
+	// 管理员，可管理任意文章、评论和用户角色
+	RoleAdmin = "admin"
+	// 编辑，可修改他人的文章
+	RoleEditor = "editor"
+	// 作者，可发布文章，默认角色
+	RoleAuthor = "author"
+	// 读者，只能发表评论
+	RoleReader = "reader"
+)
+
 type User struct {
 	ID        uint      `gorm:"primaryKey" json:"id"`
 	Username  string    `gorm:"size:50;not null;unique" json:"username"`
 	Email     string    `gorm:"size:100;not null;unique" json:"email"`
-	Password  string    `gorm:"size:255;not null" json:"password"`
+	Password  string    `gorm:"size:255;not null" json:"-"`
 	Avatar    string    `gorm:"size:255" json:"avatar"`
+	Role      string    `gorm:"size:20;not null;default:author" json:"role"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 	Articles  []Article `gorm:"foreignKey:AuthorID" json:"articles"`
 	Comments  []Comment `gorm:"foreignKey:AuthorID" json:"comments"`
-}
\ No newline at end of file
+
+	// 邮箱验证时间，未验证时为空
+	VerifiedAt *time.Time `json:"verified_at"`
+	// 两步验证开启时间，未开启时为空
+	TOTPEnabledAt *time.Time `gorm:"column:totp_enabled_at" json:"totp_enabled_at"`
+	// 两步验证密钥，开启前保存待确认的密钥
+	TOTPSecret string `gorm:"column:totp_secret;size:64" json:"-"`
+	// 最近一次使用的验证码时间步，防止同一验证码被重复使用
+	TOTPLastStep int64 `gorm:"column:totp_last_step;not null;default:0" json:"-"`
+	// 账号注销时间，匿名化注销后保留用户记录作为文章和评论的作者
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+// 是否为合法角色
+func ValidRole(role string) bool {
+	switch role {
+	case RoleAdmin, RoleEditor, RoleAuthor, RoleReader:
+		return true
+	}
+	return false
+}
+
+// 邮箱是否已验证
+func (u *User) Verified() bool {
+	return u.VerifiedAt != nil
+}
+
+// 账号是否已注销
+func (u *User) Deleted() bool {
+	return u.DeletedAt != nil
+}
+
+// 注销后匿名化的邮箱，使用保留域名，不会收到邮件也不会与真实邮箱冲突
+func DeletedUserEmail(id uint) string {
+	return fmt.Sprintf("deleted-%d@deleted.invalid", id)
+}
+
+// 注销后匿名化的用户名前缀，保留给系统使用
+const deletedUsernamePrefix = "deleted-"
+
+// 注销后匿名化的用户名
+func DeletedUsername(id uint) string {
+	return fmt.Sprintf("%s%d", deletedUsernamePrefix, id)
+}
+
+// 用户名是否使用了保留前缀，不区分大小写，注册和修改用户名时不允许使用
+func ReservedUsername(username string) bool {
+	return strings.HasPrefix(strings.ToLower(username), deletedUsernamePrefix)
+}
+
+// 是否开启了两步验证
+func (u *User) TwoFactorEnabled() bool {
+	return u.TOTPEnabledAt != nil
+}
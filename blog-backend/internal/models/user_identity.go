@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+)
+
+// 关联到用户的外部身份，同一身份提供方的同一 subject 只能关联一个用户
+type UserIdentity struct {
+	ID     uint `gorm:"primaryKey" json:"id"`
+	UserID uint `gorm:"not null;index" json:"user_id"`
+	// 身份提供方的 issuer
+	Issuer    string    `gorm:"size:255;not null;uniqueIndex:idx_identity_subject" json:"issuer"`
+	Subject   string    `gorm:"size:255;not null;uniqueIndex:idx_identity_subject" json:"subject"`
+	Email     string    `gorm:"size:100" json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// 进行中的 OIDC 登录请求，保存 PKCE code_verifier 和 nonce，使用一次后删除
+type PendingLogin struct {
+	ID           uint      `gorm:"primaryKey"`
+	StateHash    string    `gorm:"size:64;not null;uniqueIndex"`
+	CodeVerifier string    `gorm:"size:128;not null"`
+	Nonce        string    `gorm:"size:64;not null"`
+	ExpiresAt    time.Time `gorm:"not null;index"`
+	CreatedAt    time.Time
+}
@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+)
+
+// 一次性令牌的用途
+const (
+	TokenPasswordReset = "password_reset"
+)
+
+// 通过邮件发送的一次性令牌，只保存摘要
+type UserToken struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	UserID    uint       `gorm:"not null;index" json:"user_id"`
+	Purpose   string     `gorm:"size:30;not null" json:"purpose"`
+	TokenHash string     `gorm:"size:64;not null;uniqueIndex" json:"-"`
+	ExpiresAt time.Time  `gorm:"not null" json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
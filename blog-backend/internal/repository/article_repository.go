@@ -0,0 +1,226 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"blog-backend/internal/models"
+)
+
+// 文章列表的筛选条件，零值表示不限
+type ArticleFilter struct {
+	Status   string
+	AuthorID uint
+	TagID    uint
+	// 属于其中任一分类
+	CategoryIDs []uint
+}
+
+type ArticleRepository interface {
+	// 分页查询，预加载作者、标签和分类；已发布的文章按发布时间倒序，其他按更新时间倒序
+	List(ctx context.Context, filter ArticleFilter, offset, limit int) ([]models.Article, int64, error)
+	// 按ID升序查询作者的全部文章，预加载标签和分类
+	ListByAuthor(ctx context.Context, authorID uint) ([]models.Article, error)
+	// 按ID查询，预加载作者、标签和分类
+	FindByID(ctx context.Context, id uint) (*models.Article, error)
+	// 按当前 slug 查询，预加载作者、标签和分类
+	FindBySlug(ctx context.Context, slug string) (*models.Article, error)
+	// 查询文章以前使用过的 slug
+	FindOldSlug(ctx context.Context, slug string) (*models.ArticleSlug, error)
+	// slug 是否已被其他文章使用，包括其他文章以前使用过的 slug
+	SlugTaken(ctx context.Context, slug string, exceptArticleID uint) (bool, error)
+	// 创建文章及其标签关联，标签必须已存在
+	Create(ctx context.Context, article *models.Article) error
+	// 保存文章，标签关联替换为 article.Tags
+	Update(ctx context.Context, article *models.Article) error
+	// 保存修改了 slug 的文章，并记录旧 slug
+	Rename(ctx context.Context, article *models.Article, oldSlug string) error
+	// 删除文章及其历史 slug、标签关联，并从所属系列中移除
+	Delete(ctx context.Context, id uint) error
+	IncrementViews(ctx context.Context, id uint) error
+	// 各分类中已发布文章的数量，不包括子分类
+	CountByCategory(ctx context.Context) (map[uint]int64, error)
+	// 按定时发布时间升序查询已到期的草稿
+	ListDue(ctx context.Context, now time.Time, limit int) ([]models.Article, error)
+	// 发布到期的定时草稿，文章已被发布或取消定时时返回 false
+	//
+	// 通过带条件的 UPDATE 实现，多个实例同时执行时每篇文章只会被其中一个发布。
+	PublishScheduled(ctx context.Context, id uint, now time.Time) (bool, error)
+}
+
+type gormArticleRepository struct {
+	db *gorm.DB
+}
+
+func NewArticleRepository(db *gorm.DB) ArticleRepository {
+	return &gormArticleRepository{db: db}
+}
+
+func (r *gormArticleRepository) List(ctx context.Context, filter ArticleFilter, offset, limit int) ([]models.Article, int64, error) {
+	var articles []models.Article
+	var total int64
+
+	where := func(db *gorm.DB) *gorm.DB {
+		if filter.Status != "" {
+			db = db.Where("status = ?", filter.Status)
+		}
+		if filter.AuthorID != 0 {
+			db = db.Where("author_id = ?", filter.AuthorID)
+		}
+		if filter.TagID != 0 {
+			db = db.Where("id IN (?)", r.db.Model(&models.ArticleTag{}).Select("article_id").Where("tag_id = ?", filter.TagID))
+		}
+		if filter.CategoryIDs != nil {
+			db = db.Where("category_id IN ?", filter.CategoryIDs)
+		}
+		return db
+	}
+
+	db := r.db.WithContext(ctx)
+	if err := db.Model(&models.Article{}).Scopes(where).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	order := "updated_at DESC, id DESC"
+	if filter.Status == models.ArticlePublished {
+		order = "published_at DESC, id DESC"
+	}
+	if err := db.Scopes(where).Preload("Author").Preload("Tags").Preload("Category").Offset(offset).Limit(limit).Order(order).Find(&articles).Error; err != nil {
+		return nil, 0, err
+	}
+	return articles, total, nil
+}
+
+func (r *gormArticleRepository) ListByAuthor(ctx context.Context, authorID uint) ([]models.Article, error) {
+	var articles []models.Article
+	err := r.db.WithContext(ctx).Preload("Tags").Preload("Category").Where("author_id = ?", authorID).Order("id").Find(&articles).Error
+	return articles, err
+}
+
+func (r *gormArticleRepository) FindByID(ctx context.Context, id uint) (*models.Article, error) {
+	var article models.Article
+	if err := r.db.WithContext(ctx).Preload("Author").Preload("Tags").Preload("Category").First(&article, id).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &article, nil
+}
+
+func (r *gormArticleRepository) FindBySlug(ctx context.Context, slug string) (*models.Article, error) {
+	var article models.Article
+	if err := r.db.WithContext(ctx).Preload("Author").Preload("Tags").Preload("Category").Where("slug = ?", slug).First(&article).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &article, nil
+}
+
+func (r *gormArticleRepository) FindOldSlug(ctx context.Context, slug string) (*models.ArticleSlug, error) {
+	var old models.ArticleSlug
+	if err := r.db.WithContext(ctx).Where("slug = ?", slug).First(&old).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &old, nil
+}
+
+func (r *gormArticleRepository) SlugTaken(ctx context.Context, slug string, exceptArticleID uint) (bool, error) {
+	db := r.db.WithContext(ctx)
+	var count int64
+	if err := db.Model(&models.Article{}).Where("slug = ? AND id <> ?", slug, exceptArticleID).Count(&count).Error; err != nil {
+		return false, err
+	}
+	if count > 0 {
+		return true, nil
+	}
+	err := db.Model(&models.ArticleSlug{}).Where("slug = ? AND article_id <> ?", slug, exceptArticleID).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *gormArticleRepository) Create(ctx context.Context, article *models.Article) error {
+	return r.db.WithContext(ctx).Omit("Author", "Category", "Tags.*").Create(article).Error
+}
+
+func (r *gormArticleRepository) Update(ctx context.Context, article *models.Article) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return saveArticle(tx, article)
+	})
+}
+
+// 保存文章字段并替换标签关联，不修改作者、分类和标签本身
+func saveArticle(tx *gorm.DB, article *models.Article) error {
+	if err := tx.Omit("Author", "Category", "Tags").Save(article).Error; err != nil {
+		return err
+	}
+	return tx.Model(article).Omit("Tags.*").Association("Tags").Replace(article.Tags)
+}
+
+func (r *gormArticleRepository) Rename(ctx context.Context, article *models.Article, oldSlug string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 改回以前使用过的 slug 时，该 slug 不再是旧 slug
+		if err := tx.Where("article_id = ? AND slug = ?", article.ID, article.Slug).Delete(&models.ArticleSlug{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&models.ArticleSlug{ArticleID: article.ID, Slug: oldSlug}).Error; err != nil {
+			return err
+		}
+		return saveArticle(tx, article)
+	})
+}
+
+func (r *gormArticleRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("article_id = ?", id).Delete(&models.ArticleSlug{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("article_id = ?", id).Delete(&models.ArticleTag{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("article_id = ?", id).Delete(&models.SeriesArticle{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Article{}, id).Error
+	})
+}
+
+func (r *gormArticleRepository) IncrementViews(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Model(&models.Article{}).Where("id = ?", id).
+		UpdateColumn("views", gorm.Expr("views + ?", 1)).Error
+}
+
+func (r *gormArticleRepository) CountByCategory(ctx context.Context) (map[uint]int64, error) {
+	var rows []struct {
+		CategoryID uint
+		Count      int64
+	}
+	err := r.db.WithContext(ctx).Model(&models.Article{}).Select("category_id, COUNT(*) AS count").
+		Where("status = ? AND category_id IS NOT NULL", models.ArticlePublished).
+		Group("category_id").Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[uint]int64, len(rows))
+	for _, row := range rows {
+		counts[row.CategoryID] = row.Count
+	}
+	return counts, nil
+}
+
+func (r *gormArticleRepository) ListDue(ctx context.Context, now time.Time, limit int) ([]models.Article, error) {
+	var articles []models.Article
+	err := r.db.WithContext(ctx).Where("status = ? AND publish_at <= ?", models.ArticleDraft, now).
+		Order("publish_at, id").Limit(limit).Find(&articles).Error
+	return articles, err
+}
+
+func (r *gormArticleRepository) PublishScheduled(ctx context.Context, id uint, now time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&models.Article{}).
+		Where("id = ? AND status = ? AND publish_at <= ?", id, models.ArticleDraft, now).
+		Updates(map[string]interface{}{
+			"status": models.ArticlePublished,
+			// 首次发布时间记为计划的发布时间，而不是任务实际执行的时间
+			"published_at": gorm.Expr("COALESCE(published_at, publish_at)"),
+			"publish_at":   nil,
+			"updated_at":   now,
+		})
+	return result.RowsAffected == 1, result.Error
+}
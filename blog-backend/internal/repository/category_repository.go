@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"blog-backend/internal/models"
+)
+
+type CategoryRepository interface {
+	// 全部分类，按名称升序排列
+	List(ctx context.Context) ([]models.Category, error)
+	FindByID(ctx context.Context, id uint) (*models.Category, error)
+	FindBySlug(ctx context.Context, slug string) (*models.Category, error)
+	// slug 是否已被其他分类使用
+	SlugTaken(ctx context.Context, slug string, exceptID uint) (bool, error)
+	Create(ctx context.Context, category *models.Category) error
+	Update(ctx context.Context, category *models.Category) error
+	// 删除分类，其中的文章变为未分类
+	Delete(ctx context.Context, id uint) error
+}
+
+type gormCategoryRepository struct {
+	db *gorm.DB
+}
+
+func NewCategoryRepository(db *gorm.DB) CategoryRepository {
+	return &gormCategoryRepository{db: db}
+}
+
+func (r *gormCategoryRepository) List(ctx context.Context) ([]models.Category, error) {
+	var categories []models.Category
+	err := r.db.WithContext(ctx).Order("name, id").Find(&categories).Error
+	return categories, err
+}
+
+func (r *gormCategoryRepository) FindByID(ctx context.Context, id uint) (*models.Category, error) {
+	var category models.Category
+	if err := r.db.WithContext(ctx).First(&category, id).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &category, nil
+}
+
+func (r *gormCategoryRepository) FindBySlug(ctx context.Context, slug string) (*models.Category, error) {
+	var category models.Category
+	if err := r.db.WithContext(ctx).Where("slug = ?", slug).First(&category).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &category, nil
+}
+
+func (r *gormCategoryRepository) SlugTaken(ctx context.Context, slug string, exceptID uint) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.Category{}).Where("slug = ? AND id <> ?", slug, exceptID).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *gormCategoryRepository) Create(ctx context.Context, category *models.Category) error {
+	return r.db.WithContext(ctx).Create(category).Error
+}
+
+func (r *gormCategoryRepository) Update(ctx context.Context, category *models.Category) error {
+	return r.db.WithContext(ctx).Save(category).Error
+}
+
+func (r *gormCategoryRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Article{}).Where("category_id = ?", id).UpdateColumn("category_id", nil).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&models.Category{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrNotFound
+		}
+		return nil
+	})
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"blog-backend/internal/models"
+)
+
+type CommentRepository interface {
+	// 按创建时间倒序分页查询文章评论，预加载作者信息
+	ListByArticle(ctx context.Context, articleID uint, offset, limit int) ([]models.Comment, int64, error)
+	// 按ID升序查询用户发表的全部评论，预加载所属文章
+	ListByAuthor(ctx context.Context, authorID uint) ([]models.Comment, error)
+	FindByID(ctx context.Context, id uint) (*models.Comment, error)
+	Create(ctx context.Context, comment *models.Comment) error
+	Delete(ctx context.Context, id uint) error
+}
+
+type gormCommentRepository struct {
+	db *gorm.DB
+}
+
+func NewCommentRepository(db *gorm.DB) CommentRepository {
+	return &gormCommentRepository{db: db}
+}
+
+func (r *gormCommentRepository) ListByArticle(ctx context.Context, articleID uint, offset, limit int) ([]models.Comment, int64, error) {
+	var comments []models.Comment
+	var total int64
+
+	db := r.db.WithContext(ctx)
+	if err := db.Model(&models.Comment{}).Where("article_id = ?", articleID).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := db.Preload("Author").Where("article_id = ?", articleID).Offset(offset).Limit(limit).Order("created_at DESC").Find(&comments).Error; err != nil {
+		return nil, 0, err
+	}
+	return comments, total, nil
+}
+
+func (r *gormCommentRepository) ListByAuthor(ctx context.Context, authorID uint) ([]models.Comment, error) {
+	var comments []models.Comment
+	err := r.db.WithContext(ctx).Preload("Article").Where("author_id = ?", authorID).Order("id").Find(&comments).Error
+	return comments, err
+}
+
+func (r *gormCommentRepository) FindByID(ctx context.Context, id uint) (*models.Comment, error) {
+	var comment models.Comment
+	if err := r.db.WithContext(ctx).First(&comment, id).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &comment, nil
+}
+
+func (r *gormCommentRepository) Create(ctx context.Context, comment *models.Comment) error {
+	return r.db.WithContext(ctx).Omit("Article", "Author").Create(comment).Error
+}
+
+func (r *gormCommentRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&models.Comment{}, id).Error
+}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"blog-backend/internal/models"
+)
+
+type IdentityRepository interface {
+	Find(ctx context.Context, issuer, subject string) (*models.UserIdentity, error)
+	Create(ctx context.Context, identity *models.UserIdentity) error
+
+	CreateState(ctx context.Context, state *models.PendingLogin) error
+	// 取出并删除登录请求，并发使用同一个 state 时只有一个请求成功
+	ConsumeState(ctx context.Context, hash string) (*models.PendingLogin, error)
+	// 删除已过期的登录请求
+	DeleteExpiredStates(ctx context.Context, now time.Time) error
+}
+
+type gormIdentityRepository struct {
+	db *gorm.DB
+}
+
+func NewIdentityRepository(db *gorm.DB) IdentityRepository {
+	return &gormIdentityRepository{db: db}
+}
+
+func (r *gormIdentityRepository) Find(ctx context.Context, issuer, subject string) (*models.UserIdentity, error) {
+	var identity models.UserIdentity
+	if err := r.db.WithContext(ctx).Where("issuer = ? AND subject = ?", issuer, subject).First(&identity).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &identity, nil
+}
+
+func (r *gormIdentityRepository) Create(ctx context.Context, identity *models.UserIdentity) error {
+	return r.db.WithContext(ctx).Create(identity).Error
+}
+
+func (r *gormIdentityRepository) CreateState(ctx context.Context, state *models.PendingLogin) error {
+	return r.db.WithContext(ctx).Create(state).Error
+}
+
+func (r *gormIdentityRepository) ConsumeState(ctx context.Context, hash string) (*models.PendingLogin, error) {
+	db := r.db.WithContext(ctx)
+	var state models.PendingLogin
+	if err := db.Where("state_hash = ?", hash).First(&state).Error; err != nil {
+		return nil, translate(err)
+	}
+	result := db.Delete(&models.PendingLogin{}, state.ID)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrNotFound
+	}
+	return &state, nil
+}
+
+func (r *gormIdentityRepository) DeleteExpiredStates(ctx context.Context, now time.Time) error {
+	return r.db.WithContext(ctx).Where("expires_at < ?", now).Delete(&models.PendingLogin{}).Error
+}
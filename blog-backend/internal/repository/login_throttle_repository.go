@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"blog-backend/internal/models"
+)
+
+type LoginThrottleRepository interface {
+	Find(ctx context.Context, subject string) (*models.LoginThrottle, error)
+	// 在数据库中将失败次数加一并返回更新后的记录，上次失败早于 resetBefore 时先清零计数并解除锁定
+	Fail(ctx context.Context, subject string, now, resetBefore time.Time) (*models.LoginThrottle, error)
+	// 锁定到 until，已有更晚的锁定截止时间时保持不变
+	Lock(ctx context.Context, id uint, until time.Time) error
+	Delete(ctx context.Context, subject string) error
+}
+
+type gormLoginThrottleRepository struct {
+	db *gorm.DB
+}
+
+func NewLoginThrottleRepository(db *gorm.DB) LoginThrottleRepository {
+	return &gormLoginThrottleRepository{db: db}
+}
+
+func (r *gormLoginThrottleRepository) Find(ctx context.Context, subject string) (*models.LoginThrottle, error) {
+	var throttle models.LoginThrottle
+	if err := r.db.WithContext(ctx).Where("subject = ?", subject).First(&throttle).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &throttle, nil
+}
+
+func (r *gormLoginThrottleRepository) Fail(ctx context.Context, subject string, now, resetBefore time.Time) (*models.LoginThrottle, error) {
+	var throttle models.LoginThrottle
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 并发的首次失败只保留一条记录
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.LoginThrottle{Subject: subject, LastFailedAt: now}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.LoginThrottle{}).
+			Where("subject = ? AND last_failed_at < ?", subject, resetBefore).
+			Updates(map[string]interface{}{"failures": 0, "locked_until": nil}).Error; err != nil {
+			return err
+		}
+		// 在数据库中递增，并发的失败不会互相覆盖
+		if err := tx.Model(&models.LoginThrottle{}).
+			Where("subject = ?", subject).
+			Updates(map[string]interface{}{"failures": gorm.Expr("failures + 1"), "last_failed_at": now}).Error; err != nil {
+			return err
+		}
+		return tx.Where("subject = ?", subject).First(&throttle).Error
+	})
+	if err != nil {
+		return nil, translate(err)
+	}
+	return &throttle, nil
+}
+
+func (r *gormLoginThrottleRepository) Lock(ctx context.Context, id uint, until time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.LoginThrottle{}).
+		Where("id = ? AND (locked_until IS NULL OR locked_until < ?)", id, until).
+		Update("locked_until", until).Error
+}
+
+func (r *gormLoginThrottleRepository) Delete(ctx context.Context, subject string) error {
+	return r.db.WithContext(ctx).Where("subject = ?", subject).Delete(&models.LoginThrottle{}).Error
+}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"blog-backend/internal/models"
+)
+
+type PersonalTokenRepository interface {
+	Create(ctx context.Context, token *models.PersonalToken) error
+	FindByHash(ctx context.Context, hash string) (*models.PersonalToken, error)
+	// 用户的全部令牌，按创建时间倒序排列
+	ListByUser(ctx context.Context, userID uint) ([]models.PersonalToken, error)
+	// 更新最后使用时间
+	Touch(ctx context.Context, id uint, at time.Time) error
+	// 删除用户的令牌，令牌不存在或不属于该用户时返回 ErrNotFound
+	DeleteForUser(ctx context.Context, userID, id uint) error
+	// 删除用户的全部令牌
+	DeleteAllForUser(ctx context.Context, userID uint) error
+}
+
+type gormPersonalTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewPersonalTokenRepository(db *gorm.DB) PersonalTokenRepository {
+	return &gormPersonalTokenRepository{db: db}
+}
+
+func (r *gormPersonalTokenRepository) Create(ctx context.Context, token *models.PersonalToken) error {
+	return r.db.WithContext(ctx).Create(token).Error
+}
+
+func (r *gormPersonalTokenRepository) FindByHash(ctx context.Context, hash string) (*models.PersonalToken, error) {
+	var token models.PersonalToken
+	if err := r.db.WithContext(ctx).Where("token_hash = ?", hash).First(&token).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &token, nil
+}
+
+func (r *gormPersonalTokenRepository) ListByUser(ctx context.Context, userID uint) ([]models.PersonalToken, error) {
+	var tokens []models.PersonalToken
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("id DESC").Find(&tokens).Error
+	return tokens, err
+}
+
+func (r *gormPersonalTokenRepository) Touch(ctx context.Context, id uint, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.PersonalToken{}).Where("id = ?", id).Update("last_used_at", at).Error
+}
+
+func (r *gormPersonalTokenRepository) DeleteForUser(ctx context.Context, userID, id uint) error {
+	result := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).Delete(&models.PersonalToken{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *gormPersonalTokenRepository) DeleteAllForUser(ctx context.Context, userID uint) error {
+	return r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&models.PersonalToken{}).Error
+}
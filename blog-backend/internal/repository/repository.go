@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// 记录不存在
+var ErrNotFound = errors.New("记录不存在")
+
+// 将 GORM 的未找到错误转换为 ErrNotFound
+func translate(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrNotFound
+	}
+	return err
+}
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"blog-backend/internal/models"
+)
+
+type SeriesRepository interface {
+	// 分页查询，预加载作者，按创建时间倒序排列
+	List(ctx context.Context, offset, limit int) ([]models.Series, int64, error)
+	// 按ID升序查询作者的全部系列
+	ListByAuthor(ctx context.Context, authorID uint) ([]models.Series, error)
+	// 按ID查询，预加载作者
+	FindByID(ctx context.Context, id uint) (*models.Series, error)
+	// 查询文章所属系列的关联
+	FindByArticle(ctx context.Context, articleID uint) (*models.SeriesArticle, error)
+	// 按顺序查询系列中的文章，只加载ID、标题、slug、状态和作者，不加载正文
+	Articles(ctx context.Context, seriesID uint) ([]models.Article, error)
+	// 创建系列并按顺序加入 articleIDs 中的文章
+	Create(ctx context.Context, series *models.Series, articleIDs []uint) error
+	Update(ctx context.Context, series *models.Series) error
+	// 将系列中的文章替换为 articleIDs，按列表顺序排列
+	SetArticles(ctx context.Context, seriesID uint, articleIDs []uint) error
+	// 删除系列，其中的文章保留
+	Delete(ctx context.Context, id uint) error
+}
+
+type gormSeriesRepository struct {
+	db *gorm.DB
+}
+
+func NewSeriesRepository(db *gorm.DB) SeriesRepository {
+	return &gormSeriesRepository{db: db}
+}
+
+func (r *gormSeriesRepository) List(ctx context.Context, offset, limit int) ([]models.Series, int64, error) {
+	var series []models.Series
+	var total int64
+
+	db := r.db.WithContext(ctx)
+	if err := db.Model(&models.Series{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := db.Preload("Author").Offset(offset).Limit(limit).Order("created_at DESC, id DESC").Find(&series).Error; err != nil {
+		return nil, 0, err
+	}
+	return series, total, nil
+}
+
+func (r *gormSeriesRepository) ListByAuthor(ctx context.Context, authorID uint) ([]models.Series, error) {
+	var series []models.Series
+	err := r.db.WithContext(ctx).Where("author_id = ?", authorID).Order("id").Find(&series).Error
+	return series, err
+}
+
+func (r *gormSeriesRepository) FindByID(ctx context.Context, id uint) (*models.Series, error) {
+	var series models.Series
+	if err := r.db.WithContext(ctx).Preload("Author").First(&series, id).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &series, nil
+}
+
+func (r *gormSeriesRepository) FindByArticle(ctx context.Context, articleID uint) (*models.SeriesArticle, error) {
+	var link models.SeriesArticle
+	if err := r.db.WithContext(ctx).Where("article_id = ?", articleID).First(&link).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &link, nil
+}
+
+func (r *gormSeriesRepository) Articles(ctx context.Context, seriesID uint) ([]models.Article, error) {
+	var articles []models.Article
+	err := r.db.WithContext(ctx).
+		Select("articles.id, articles.title, articles.slug, articles.status, articles.author_id").
+		Joins("JOIN series_articles ON series_articles.article_id = articles.id").
+		Where("series_articles.series_id = ?", seriesID).
+		Order("series_articles.position").
+		Find(&articles).Error
+	return articles, err
+}
+
+func (r *gormSeriesRepository) Create(ctx context.Context, series *models.Series, articleIDs []uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Author").Create(series).Error; err != nil {
+			return err
+		}
+		return setSeriesArticles(tx, series.ID, articleIDs)
+	})
+}
+
+func (r *gormSeriesRepository) Update(ctx context.Context, series *models.Series) error {
+	return r.db.WithContext(ctx).Omit("Author").Save(series).Error
+}
+
+func (r *gormSeriesRepository) SetArticles(ctx context.Context, seriesID uint, articleIDs []uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return setSeriesArticles(tx, seriesID, articleIDs)
+	})
+}
+
+// 替换系列中的文章，顺序从 1 开始编号
+func setSeriesArticles(tx *gorm.DB, seriesID uint, articleIDs []uint) error {
+	if err := tx.Where("series_id = ?", seriesID).Delete(&models.SeriesArticle{}).Error; err != nil {
+		return err
+	}
+	if len(articleIDs) == 0 {
+		return nil
+	}
+	links := make([]models.SeriesArticle, len(articleIDs))
+	for i, id := range articleIDs {
+		links[i] = models.SeriesArticle{SeriesID: seriesID, ArticleID: id, Position: i + 1}
+	}
+	return tx.Create(&links).Error
+}
+
+func (r *gormSeriesRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("series_id = ?", id).Delete(&models.SeriesArticle{}).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&models.Series{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrNotFound
+		}
+		return nil
+	})
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"blog-backend/internal/models"
+)
+
+type SessionRepository interface {
+	Create(ctx context.Context, session *models.Session) error
+	// 更新会话的客户端信息、最后活跃时间和有效期，会话已撤销时返回 false
+	Extend(ctx context.Context, session *models.Session) (bool, error)
+	FindByID(ctx context.Context, id uint) (*models.Session, error)
+	// 用户未撤销且未过期的会话，按最后活跃时间倒序排列
+	ListActiveByUser(ctx context.Context, userID uint, now time.Time) ([]models.Session, error)
+	// 更新最后活跃时间
+	Touch(ctx context.Context, id uint, at time.Time) error
+	// 撤销会话，已撤销的会话保持不变
+	Revoke(ctx context.Context, id uint) error
+	// 撤销用户的全部会话，exceptID 不为 0 时保留该会话
+	RevokeAllForUser(ctx context.Context, userID, exceptID uint) error
+
+	CreateRefreshToken(ctx context.Context, token *models.RefreshToken) error
+	FindRefreshTokenByHash(ctx context.Context, hash string) (*models.RefreshToken, error)
+	// 将刷新令牌标记为已使用，令牌已被使用过时返回 false
+	MarkRefreshTokenUsed(ctx context.Context, id uint) (bool, error)
+}
+
+type gormSessionRepository struct {
+	db *gorm.DB
+}
+
+func NewSessionRepository(db *gorm.DB) SessionRepository {
+	return &gormSessionRepository{db: db}
+}
+
+func (r *gormSessionRepository) Create(ctx context.Context, session *models.Session) error {
+	return r.db.WithContext(ctx).Create(session).Error
+}
+
+func (r *gormSessionRepository) Extend(ctx context.Context, session *models.Session) (bool, error) {
+	// 只更新这几列，并发撤销写入的 revoked_at 不会被覆盖
+	result := r.db.WithContext(ctx).Model(&models.Session{}).
+		Where("id = ? AND revoked_at IS NULL", session.ID).
+		Updates(map[string]interface{}{
+			"user_agent":   session.UserAgent,
+			"ip":           session.IP,
+			"last_seen_at": session.LastSeenAt,
+			"expires_at":   session.ExpiresAt,
+		})
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *gormSessionRepository) FindByID(ctx context.Context, id uint) (*models.Session, error) {
+	var session models.Session
+	if err := r.db.WithContext(ctx).First(&session, id).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &session, nil
+}
+
+func (r *gormSessionRepository) ListActiveByUser(ctx context.Context, userID uint, now time.Time) ([]models.Session, error) {
+	var sessions []models.Session
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, now).
+		Order("last_seen_at DESC").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+func (r *gormSessionRepository) Touch(ctx context.Context, id uint, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.Session{}).Where("id = ?", id).UpdateColumn("last_seen_at", at).Error
+}
+
+func (r *gormSessionRepository) Revoke(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Model(&models.Session{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now()).Error
+}
+
+func (r *gormSessionRepository) RevokeAllForUser(ctx context.Context, userID, exceptID uint) error {
+	query := r.db.WithContext(ctx).Model(&models.Session{}).Where("user_id = ? AND revoked_at IS NULL", userID)
+	if exceptID != 0 {
+		query = query.Where("id <> ?", exceptID)
+	}
+	return query.Update("revoked_at", time.Now()).Error
+}
+
+func (r *gormSessionRepository) CreateRefreshToken(ctx context.Context, token *models.RefreshToken) error {
+	return r.db.WithContext(ctx).Create(token).Error
+}
+
+func (r *gormSessionRepository) FindRefreshTokenByHash(ctx context.Context, hash string) (*models.RefreshToken, error) {
+	var token models.RefreshToken
+	if err := r.db.WithContext(ctx).Where("token_hash = ?", hash).First(&token).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &token, nil
+}
+
+func (r *gormSessionRepository) MarkRefreshTokenUsed(ctx context.Context, id uint) (bool, error) {
+	// 条件更新保证并发请求中只有一个能使用同一个令牌
+	result := r.db.WithContext(ctx).Model(&models.RefreshToken{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", time.Now())
+	return result.RowsAffected == 1, result.Error
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"blog-backend/internal/models"
+)
+
+// 标签及使用该标签的已发布文章数量
+type TagCount struct {
+	models.Tag
+	ArticleCount int64 `json:"article_count"`
+}
+
+type TagRepository interface {
+	// 全部标签及其已发布文章数量，按文章数量倒序、名称升序排列
+	ListWithCounts(ctx context.Context) ([]TagCount, error)
+	FindByID(ctx context.Context, id uint) (*models.Tag, error)
+	FindBySlug(ctx context.Context, slug string) (*models.Tag, error)
+	// 按名称查询，不区分大小写
+	FindByName(ctx context.Context, name string) (*models.Tag, error)
+	// slug 是否已被其他标签使用
+	SlugTaken(ctx context.Context, slug string, exceptID uint) (bool, error)
+	Create(ctx context.Context, tag *models.Tag) error
+	Update(ctx context.Context, tag *models.Tag) error
+	// 删除标签及其与文章的关联
+	Delete(ctx context.Context, id uint) error
+}
+
+type gormTagRepository struct {
+	db *gorm.DB
+}
+
+func NewTagRepository(db *gorm.DB) TagRepository {
+	return &gormTagRepository{db: db}
+}
+
+func (r *gormTagRepository) ListWithCounts(ctx context.Context) ([]TagCount, error) {
+	var tags []TagCount
+	err := r.db.WithContext(ctx).Model(&models.Tag{}).
+		Select("tags.*, COUNT(articles.id) AS article_count").
+		Joins("LEFT JOIN article_tags ON article_tags.tag_id = tags.id").
+		Joins("LEFT JOIN articles ON articles.id = article_tags.article_id AND articles.status = ?", models.ArticlePublished).
+		Group("tags.id").
+		Order("article_count DESC, tags.name").
+		Scan(&tags).Error
+	return tags, err
+}
+
+func (r *gormTagRepository) FindByID(ctx context.Context, id uint) (*models.Tag, error) {
+	var tag models.Tag
+	if err := r.db.WithContext(ctx).First(&tag, id).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &tag, nil
+}
+
+func (r *gormTagRepository) FindBySlug(ctx context.Context, slug string) (*models.Tag, error) {
+	var tag models.Tag
+	if err := r.db.WithContext(ctx).Where("slug = ?", slug).First(&tag).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &tag, nil
+}
+
+func (r *gormTagRepository) FindByName(ctx context.Context, name string) (*models.Tag, error) {
+	var tag models.Tag
+	if err := r.db.WithContext(ctx).Where("LOWER(name) = ?", strings.ToLower(name)).First(&tag).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &tag, nil
+}
+
+func (r *gormTagRepository) SlugTaken(ctx context.Context, slug string, exceptID uint) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.Tag{}).Where("slug = ? AND id <> ?", slug, exceptID).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *gormTagRepository) Create(ctx context.Context, tag *models.Tag) error {
+	return r.db.WithContext(ctx).Create(tag).Error
+}
+
+func (r *gormTagRepository) Update(ctx context.Context, tag *models.Tag) error {
+	return r.db.WithContext(ctx).Save(tag).Error
+}
+
+func (r *gormTagRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("tag_id = ?", id).Delete(&models.ArticleTag{}).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&models.Tag{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrNotFound
+		}
+		return nil
+	})
+}
@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"blog-backend/internal/models"
+)
+
+type UserRepository interface {
+	// 按ID升序分页查询
+	List(ctx context.Context, offset, limit int) ([]models.User, int64, error)
+	FindByID(ctx context.Context, id uint) (*models.User, error)
+	FindByEmail(ctx context.Context, email string) (*models.User, error)
+	// 邮箱或用户名是否已被占用
+	ExistsByEmailOrUsername(ctx context.Context, email, username string) (bool, error)
+	ExistsByEmail(ctx context.Context, email string) (bool, error)
+	ExistsByUsername(ctx context.Context, username string) (bool, error)
+	Create(ctx context.Context, user *models.User) error
+	Update(ctx context.Context, user *models.User) error
+	// 记录已使用的 TOTP 时间步，step 不大于上次记录的时间步时返回 false
+	AdvanceTOTPStep(ctx context.Context, id uint, step int64) (bool, error)
+	// 保存已清除个人信息的用户并删除其登录凭证，文章和评论保留
+	Anonymize(ctx context.Context, user *models.User) error
+	// 删除用户、登录凭证、用户的文章和系列及文章下的评论和用户发表的评论
+	DeleteWithContent(ctx context.Context, id uint) error
+}
+
+type gormUserRepository struct {
+	db *gorm.DB
+}
+
+func NewUserRepository(db *gorm.DB) UserRepository {
+	return &gormUserRepository{db: db}
+}
+
+func (r *gormUserRepository) List(ctx context.Context, offset, limit int) ([]models.User, int64, error) {
+	var users []models.User
+	var total int64
+
+	db := r.db.WithContext(ctx)
+	if err := db.Model(&models.User{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := db.Offset(offset).Limit(limit).Order("id").Find(&users).Error; err != nil {
+		return nil, 0, err
+	}
+	return users, total, nil
+}
+
+func (r *gormUserRepository) FindByID(ctx context.Context, id uint) (*models.User, error) {
+	var user models.User
+	if err := r.db.WithContext(ctx).First(&user, id).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &user, nil
+}
+
+func (r *gormUserRepository) FindByEmail(ctx context.Context, email string) (*models.User, error) {
+	var user models.User
+	if err := r.db.WithContext(ctx).Where("email = ?", email).First(&user).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &user, nil
+}
+
+func (r *gormUserRepository) ExistsByEmailOrUsername(ctx context.Context, email, username string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.User{}).Where("email = ? OR username = ?", email, username).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *gormUserRepository) ExistsByEmail(ctx context.Context, email string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.User{}).Where("email = ?", email).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *gormUserRepository) ExistsByUsername(ctx context.Context, username string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.User{}).Where("username = ?", username).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *gormUserRepository) Create(ctx context.Context, user *models.User) error {
+	return r.db.WithContext(ctx).Create(user).Error
+}
+
+func (r *gormUserRepository) Update(ctx context.Context, user *models.User) error {
+	return r.db.WithContext(ctx).Save(user).Error
+}
+
+func (r *gormUserRepository) AdvanceTOTPStep(ctx context.Context, id uint, step int64) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&models.User{}).
+		Where("id = ? AND totp_last_step < ?", id, step).
+		UpdateColumn("totp_last_step", step)
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *gormUserRepository) Anonymize(ctx context.Context, user *models.User) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := deleteCredentials(tx, user.ID); err != nil {
+			return err
+		}
+		return tx.Save(user).Error
+	})
+}
+
+func (r *gormUserRepository) DeleteWithContent(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := deleteCredentials(tx, id); err != nil {
+			return err
+		}
+		articles := tx.Model(&models.Article{}).Select("id").Where("author_id = ?", id)
+		if err := tx.Where("author_id = ? OR article_id IN (?)", id, articles).Delete(&models.Comment{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("article_id IN (?)", articles).Delete(&models.ArticleSlug{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("article_id IN (?)", articles).Delete(&models.ArticleTag{}).Error; err != nil {
+			return err
+		}
+		series := tx.Model(&models.Series{}).Select("id").Where("author_id = ?", id)
+		if err := tx.Where("article_id IN (?) OR series_id IN (?)", articles, series).Delete(&models.SeriesArticle{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("author_id = ?", id).Delete(&models.Series{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("author_id = ?", id).Delete(&models.Article{}).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&models.User{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrNotFound
+		}
+		return nil
+	})
+}
+
+// 删除用户的会话、各类令牌、外部身份和按邮箱记录的登录限流
+func deleteCredentials(tx *gorm.DB, userID uint) error {
+	var user models.User
+	if err := tx.Select("id", "email").First(&user, userID).Error; err != nil {
+		return translate(err)
+	}
+	for _, model := range []interface{}{
+		&models.Session{},
+		&models.UserToken{},
+		&models.RecoveryCode{},
+		&models.PersonalToken{},
+		&models.UserIdentity{},
+	} {
+		if err := tx.Where("user_id = ?", userID).Delete(model).Error; err != nil {
+			return err
+		}
+	}
+	return tx.Where("subject = ?", models.AccountThrottle(user.Email)).Delete(&models.LoginThrottle{}).Error
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"blog-backend/internal/models"
+)
+
+type UserTokenRepository interface {
+	Create(ctx context.Context, token *models.UserToken) error
+	FindByHash(ctx context.Context, purpose, hash string) (*models.UserToken, error)
+	// 标记令牌已使用，令牌此前已被使用时返回 false
+	MarkUsed(ctx context.Context, id uint) (bool, error)
+	// 作废用户指定用途的全部未使用令牌
+	InvalidateForUser(ctx context.Context, userID uint, purpose string) error
+}
+
+type gormUserTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewUserTokenRepository(db *gorm.DB) UserTokenRepository {
+	return &gormUserTokenRepository{db: db}
+}
+
+func (r *gormUserTokenRepository) Create(ctx context.Context, token *models.UserToken) error {
+	return r.db.WithContext(ctx).Create(token).Error
+}
+
+func (r *gormUserTokenRepository) FindByHash(ctx context.Context, purpose, hash string) (*models.UserToken, error) {
+	var token models.UserToken
+	if err := r.db.WithContext(ctx).Where("purpose = ? AND token_hash = ?", purpose, hash).First(&token).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &token, nil
+}
+
+func (r *gormUserTokenRepository) MarkUsed(ctx context.Context, id uint) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&models.UserToken{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", time.Now())
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *gormUserTokenRepository) InvalidateForUser(ctx context.Context, userID uint, purpose string) error {
+	return r.db.WithContext(ctx).Model(&models.UserToken{}).
+		Where("user_id = ? AND purpose = ? AND used_at IS NULL", userID, purpose).
+		Update("used_at", time.Now()).Error
+}
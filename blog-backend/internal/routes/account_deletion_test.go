@@ -0,0 +1,204 @@
+package routes_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"blog-backend/config"
+	"blog-backend/internal/models"
+	"blog-backend/internal/testutil"
+)
+
+// 下载个人数据压缩包，返回文件名到内容的映射
+func exportData(t *testing.T, s *testutil.Server, token string) map[string]string {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users/me/export", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	s.Engine.ServeHTTP(w, req)
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") != "application/zip" {
+		t.Fatalf("导出失败，状态码 %d: %s", w.Code, w.Body)
+	}
+	if !strings.HasPrefix(w.Header().Get("Content-Disposition"), "attachment;") {
+		t.Fatalf("缺少下载文件名: %v", w.Header())
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(w.Body.Bytes()), int64(w.Body.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := make(map[string]string)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[f.Name] = string(data)
+	}
+	return files
+}
+
+func deleteAccount(t *testing.T, s *testutil.Server, token, password string) *testutil.Response {
+	t.Helper()
+	return s.Do(t, http.MethodDelete, "/api/v1/users/me", map[string]string{"password": password}, token)
+}
+
+func TestExportPersonalData(t *testing.T) {
+	s := testutil.NewServer(t)
+	token, aliceID := s.Register(t, "alice", "alice@example.com", "secret123")
+	bobToken, _ := s.Register(t, "bob", "bob@example.com", "secret123")
+	own := createArticle(t, s, token, "我的文章")
+	other := createArticle(t, s, bobToken, "别人的文章")
+	createComment(t, s, token, other.ID, "写得好")
+	createComment(t, s, bobToken, own.ID, "不应导出")
+
+	files := exportData(t, s, token)
+	var profile struct {
+		ID    uint   `json:"id"`
+		Email string `json:"email"`
+	}
+	if err := json.Unmarshal([]byte(files["profile.json"]), &profile); err != nil || profile.ID != aliceID || profile.Email != "alice@example.com" {
+		t.Fatalf("个人资料不符: %v %s", err, files["profile.json"])
+	}
+	var articles []article
+	if err := json.Unmarshal([]byte(files["articles.json"]), &articles); err != nil || len(articles) != 1 || articles[0].ID != own.ID {
+		t.Fatalf("文章不符: %v %s", err, files["articles.json"])
+	}
+	var comments []struct {
+		Content      string `json:"content"`
+		ArticleTitle string `json:"article_title"`
+	}
+	if err := json.Unmarshal([]byte(files["comments.json"]), &comments); err != nil || len(comments) != 1 || comments[0].ArticleTitle != "别人的文章" {
+		t.Fatalf("评论不符: %v %s", err, files["comments.json"])
+	}
+	if md := files[fmt.Sprintf("articles/%d.md", own.ID)]; !strings.HasPrefix(md, "# 我的文章") {
+		t.Fatalf("Markdown 文章不符: %q", md)
+	}
+	if md := files["comments.md"]; !strings.Contains(md, "写得好") || strings.Contains(md, "不应导出") {
+		t.Fatalf("Markdown 评论不符: %q", md)
+	}
+	if strings.Contains(files["profile.json"], "password") {
+		t.Fatal("导出内容不应包含密码哈希")
+	}
+
+	// 个人访问令牌不能导出数据
+	pat := createPersonalToken(t, s, token, map[string]interface{}{"name": "ci", "scopes": []string{"profile:read"}})
+	s.Do(t, http.MethodGet, "/api/v1/users/me/export", nil, pat.Token).AssertError(t, http.StatusForbidden, "FORBIDDEN")
+}
+
+func TestDeleteAccountAnonymizes(t *testing.T) {
+	s := testutil.NewServer(t)
+	token, aliceID := s.Register(t, "alice", "alice@example.com", "secret123")
+	bobToken, _ := s.Register(t, "bob", "bob@example.com", "secret123")
+	own := createArticle(t, s, token, "我的文章")
+	other := createArticle(t, s, bobToken, "别人的文章")
+	createComment(t, s, token, other.ID, "写得好")
+	createComment(t, s, bobToken, own.ID, "谢谢分享")
+	pat := createPersonalToken(t, s, token, map[string]interface{}{"name": "ci", "scopes": []string{"profile:read"}})
+
+	deleteAccount(t, s, token, "wrong").AssertError(t, http.StatusBadRequest, "INVALID_INPUT")
+	deleteAccount(t, s, token, "secret123").AssertOK(t)
+
+	// 登录会话和个人访问令牌全部失效
+	s.Do(t, http.MethodGet, "/api/v1/users/me", nil, token).AssertError(t, http.StatusUnauthorized, "UNAUTHORIZED")
+	s.Do(t, http.MethodGet, "/api/v1/users/me", nil, pat.Token).AssertError(t, http.StatusUnauthorized, "UNAUTHORIZED")
+	s.Do(t, http.MethodPost, "/api/v1/auth/login", map[string]string{"email": "alice@example.com", "password": "secret123"}, "").
+		AssertError(t, http.StatusUnauthorized, "UNAUTHORIZED")
+
+	// 文章和评论保留，作者变为匿名用户
+	resp := s.Do(t, http.MethodGet, fmt.Sprintf("/api/v1/articles/%d", own.ID), nil, "")
+	resp.AssertOK(t)
+	var a article
+	resp.DecodeData(t, &a)
+	if a.AuthorID != aliceID || a.Author.Username != fmt.Sprintf("deleted-%d", aliceID) {
+		t.Fatalf("文章作者未匿名化: %s", resp.Body)
+	}
+	var user models.User
+	if err := s.DB.First(&user, aliceID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if !user.Deleted() || user.Email == "alice@example.com" || user.Verified() {
+		t.Fatalf("个人信息未清除: %+v", user)
+	}
+	var count int64
+	s.DB.Model(&models.Comment{}).Count(&count)
+	if count != 2 {
+		t.Fatalf("期望保留 2 条评论，实际 %d", count)
+	}
+
+	// 用户名和邮箱可以重新注册
+	s.Register(t, "alice", "alice@example.com", "secret123")
+}
+
+// 匿名化使用的用户名前缀保留给系统，注销时不会与现有用户名冲突
+func TestDeletedUsernameReserved(t *testing.T) {
+	s := testutil.NewServer(t)
+	token, aliceID := s.Register(t, "alice", "alice@example.com", "secret123")
+	bobToken, _ := s.Register(t, "bob", "bob@example.com", "secret123")
+	placeholder := fmt.Sprintf("deleted-%d", aliceID)
+
+	register(t, s, placeholder, "mallory@example.com", "secret123").AssertError(t, http.StatusBadRequest, "INVALID_INPUT")
+	register(t, s, "Deleted-99", "mallory@example.com", "secret123").AssertError(t, http.StatusBadRequest, "INVALID_INPUT")
+	s.Do(t, http.MethodPut, "/api/v1/users/me", map[string]string{"username": placeholder}, bobToken).
+		AssertError(t, http.StatusBadRequest, "INVALID_INPUT")
+
+	// 修改为已被占用的用户名返回冲突
+	s.Do(t, http.MethodPut, "/api/v1/users/me", map[string]string{"username": "alice"}, bobToken).
+		AssertError(t, http.StatusConflict, "INVALID_INPUT")
+
+	deleteAccount(t, s, token, "secret123").AssertOK(t)
+	var user models.User
+	if err := s.DB.First(&user, aliceID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if user.Username != placeholder {
+		t.Fatalf("期望匿名用户名 %s，实际 %s", placeholder, user.Username)
+	}
+}
+
+func TestDeleteAccountCascades(t *testing.T) {
+	cfg := testutil.Config()
+	cfg.Account.DeletionPolicy = config.DeletionCascade
+	s := testutil.NewServerWithConfig(t, cfg)
+	token, aliceID := s.Register(t, "alice", "alice@example.com", "secret123")
+	bobToken, _ := s.Register(t, "bob", "bob@example.com", "secret123")
+	own := createArticle(t, s, token, "我的文章")
+	other := createArticle(t, s, bobToken, "别人的文章")
+	createComment(t, s, token, other.ID, "写得好")
+	createComment(t, s, bobToken, own.ID, "谢谢分享")
+	kept := createComment(t, s, bobToken, other.ID, "自己的评论")
+
+	deleteAccount(t, s, token, "secret123").AssertOK(t)
+
+	s.Do(t, http.MethodGet, fmt.Sprintf("/api/v1/articles/%d", own.ID), nil, "").AssertError(t, http.StatusNotFound, "NOT_FOUND")
+	s.Do(t, http.MethodGet, fmt.Sprintf("/api/v1/articles/%d", other.ID), nil, "").AssertOK(t)
+
+	// 只保留与该用户无关的评论
+	var comments []models.Comment
+	if err := s.DB.Find(&comments).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(comments) != 1 || comments[0].ID != kept.ID {
+		t.Fatalf("评论未按预期删除: %+v", comments)
+	}
+	if err := s.DB.First(&models.User{}, aliceID).Error; err == nil {
+		t.Fatal("用户记录应被删除")
+	}
+	var sessions int64
+	s.DB.Model(&models.Session{}).Where("user_id = ?", aliceID).Count(&sessions)
+	if sessions != 0 {
+		t.Fatalf("会话应被删除，剩余 %d", sessions)
+	}
+}
@@ -0,0 +1,110 @@
+package routes_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"blog-backend/internal/testutil"
+)
+
+func TestChangePassword(t *testing.T) {
+	s := testutil.NewServer(t)
+	s.Register(t, "alice", "alice@example.com", "secret123")
+	laptop := login(t, s, "alice@example.com", "secret123")
+	phone := login(t, s, "alice@example.com", "secret123")
+	pat := createPersonalToken(t, s, laptop.Token, map[string]interface{}{"name": "cli", "scopes": []string{"profile:read"}})
+
+	change := func(current, next string) *testutil.Response {
+		return s.Do(t, http.MethodPut, "/api/v1/users/me/password", map[string]string{
+			"current_password": current,
+			"new_password":     next,
+		}, laptop.Token)
+	}
+	change("wrong", "newsecret456").AssertError(t, http.StatusBadRequest, "INVALID_INPUT")
+	change("secret123", "123").AssertError(t, http.StatusBadRequest, "INVALID_INPUT")
+	change("secret123", "newsecret456").AssertOK(t)
+
+	// 当前会话保留，其他会话失效
+	s.Do(t, http.MethodGet, "/api/v1/users/me", nil, laptop.Token).AssertOK(t)
+	s.Do(t, http.MethodGet, "/api/v1/users/me", nil, phone.Token).AssertError(t, http.StatusUnauthorized, "UNAUTHORIZED")
+	refresh(t, s, phone.RefreshToken).AssertError(t, http.StatusUnauthorized, "UNAUTHORIZED")
+	// 个人访问令牌不属于任何会话，全部撤销
+	s.Do(t, http.MethodGet, "/api/v1/users/me", nil, pat.Token).AssertError(t, http.StatusUnauthorized, "UNAUTHORIZED")
+
+	s.Do(t, http.MethodPost, "/api/v1/auth/login", map[string]string{"email": "alice@example.com", "password": "secret123"}, "").
+		AssertError(t, http.StatusUnauthorized, "UNAUTHORIZED")
+	login(t, s, "alice@example.com", "newsecret456")
+
+	mails := s.Mails(t, "alice@example.com")
+	if !strings.Contains(mails[len(mails)-1], "密码刚刚被修改") {
+		t.Fatalf("期望收到密码修改通知，实际:\n%s", mails[len(mails)-1])
+	}
+}
+
+func TestChangeEmail(t *testing.T) {
+	s := testutil.NewServer(t)
+	s.Register(t, "alice", "alice@example.com", "secret123")
+	s.Register(t, "bob", "bob@example.com", "secret123")
+	laptop := login(t, s, "alice@example.com", "secret123")
+	phone := login(t, s, "alice@example.com", "secret123")
+
+	request := func(password, email string) *testutil.Response {
+		return s.Do(t, http.MethodPost, "/api/v1/users/me/email", map[string]string{
+			"current_password": password,
+			"new_email":        email,
+		}, laptop.Token)
+	}
+	request("wrong", "alice@new.example.com").AssertError(t, http.StatusBadRequest, "INVALID_INPUT")
+	request("secret123", "alice@example.com").AssertError(t, http.StatusBadRequest, "INVALID_INPUT")
+	request("secret123", "bob@example.com").AssertError(t, http.StatusConflict, "INVALID_INPUT")
+	request("secret123", "alice@new.example.com").AssertOK(t)
+
+	// 确认前邮箱保持不变
+	login(t, s, "alice@example.com", "secret123")
+	token := s.MailToken(t, "alice@new.example.com")
+
+	confirm := func(token string) *testutil.Response {
+		return s.Do(t, http.MethodPost, "/api/v1/auth/confirm-email", map[string]string{"token": token}, "")
+	}
+	resp := confirm(token)
+	resp.AssertOK(t)
+	var user struct {
+		Email string `json:"email"`
+	}
+	resp.DecodeData(t, &user)
+	if user.Email != "alice@new.example.com" {
+		t.Fatalf("期望邮箱已修改，实际 %s", user.Email)
+	}
+
+	// 发起变更的会话保留，其他会话失效
+	s.Do(t, http.MethodGet, "/api/v1/users/me", nil, laptop.Token).AssertOK(t)
+	s.Do(t, http.MethodGet, "/api/v1/users/me", nil, phone.Token).AssertError(t, http.StatusUnauthorized, "UNAUTHORIZED")
+
+	login(t, s, "alice@new.example.com", "secret123")
+	s.Do(t, http.MethodPost, "/api/v1/auth/login", map[string]string{"email": "alice@example.com", "password": "secret123"}, "").
+		AssertError(t, http.StatusUnauthorized, "UNAUTHORIZED")
+
+	mails := s.Mails(t, "alice@example.com")
+	if !strings.Contains(mails[len(mails)-1], "账号邮箱已修改为 alice@new.example.com") {
+		t.Fatalf("期望原邮箱收到通知，实际:\n%s", mails[len(mails)-1])
+	}
+
+	confirm(token).AssertError(t, http.StatusBadRequest, "INVALID_INPUT")
+	confirm("garbage").AssertError(t, http.StatusBadRequest, "INVALID_INPUT")
+}
+
+func TestChangeEmailTakenBeforeConfirm(t *testing.T) {
+	s := testutil.NewServer(t)
+	token, _ := s.Register(t, "alice", "alice@example.com", "secret123")
+
+	s.Do(t, http.MethodPost, "/api/v1/users/me/email", map[string]string{
+		"current_password": "secret123",
+		"new_email":        "shared@example.com",
+	}, token).AssertOK(t)
+	link := s.MailToken(t, "shared@example.com")
+
+	s.Register(t, "carol", "shared@example.com", "secret123")
+	s.Do(t, http.MethodPost, "/api/v1/auth/confirm-email", map[string]string{"token": link}, "").
+		AssertError(t, http.StatusConflict, "INVALID_INPUT")
+}
@@ -0,0 +1,174 @@
+package routes_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"blog-backend/internal/models"
+	"blog-backend/internal/testutil"
+)
+
+func createDraft(t *testing.T, s *testutil.Server, token, title string) article {
+	t.Helper()
+	resp := s.Do(t, http.MethodPost, "/api/v1/articles", map[string]string{"title": title, "content": "正文", "status": "draft"}, token)
+	resp.AssertOK(t)
+
+	var a article
+	resp.DecodeData(t, &a)
+	return a
+}
+
+func changeStatus(t *testing.T, s *testutil.Server, token string, id uint, action string) *testutil.Response {
+	t.Helper()
+	return s.Do(t, http.MethodPost, fmt.Sprintf("/api/v1/articles/%d/%s", id, action), nil, token)
+}
+
+// 文章列表中的文章ID
+func listArticles(t *testing.T, s *testutil.Server, path, token string) []uint {
+	t.Helper()
+	resp := s.Do(t, http.MethodGet, path, nil, token)
+	resp.AssertOK(t)
+
+	var data struct {
+		Articles []article `json:"articles"`
+	}
+	resp.DecodeData(t, &data)
+	ids := make([]uint, 0, len(data.Articles))
+	for _, a := range data.Articles {
+		ids = append(ids, a.ID)
+	}
+	return ids
+}
+
+func TestDraftVisibility(t *testing.T) {
+	s := testutil.NewServer(t)
+	token, _ := s.Register(t, "alice", "alice@example.com", "secret123")
+	bobToken, _ := s.Register(t, "bob", "bob@example.com", "secret123")
+	editorToken, _ := s.RegisterWithRole(t, "eve", "eve@example.com", "secret123", models.RoleEditor)
+
+	published := createArticle(t, s, token, "已发布")
+	if published.Status != models.ArticlePublished || published.PublishedAt == nil {
+		t.Fatalf("默认应直接发布: %+v", published)
+	}
+	draft := createDraft(t, s, token, "草稿")
+	if draft.Status != models.ArticleDraft || draft.PublishedAt != nil {
+		t.Fatalf("草稿状态不符: %+v", draft)
+	}
+	s.Do(t, http.MethodPost, "/api/v1/articles", map[string]string{"title": "x", "content": "x", "status": "archived"}, token).
+		AssertError(t, http.StatusBadRequest, "INVALID_INPUT")
+
+	// 公开列表只有已发布的文章
+	for _, viewer := range []string{"", token, editorToken} {
+		if ids := listArticles(t, s, "/api/v1/articles", viewer); len(ids) != 1 || ids[0] != published.ID {
+			t.Fatalf("公开列表不应包含草稿: %v", ids)
+		}
+	}
+
+	// 草稿只有作者和编辑可以查看
+	path := fmt.Sprintf("/api/v1/articles/%d", draft.ID)
+	s.Do(t, http.MethodGet, path, nil, "").AssertError(t, http.StatusNotFound, "NOT_FOUND")
+	s.Do(t, http.MethodGet, path, nil, bobToken).AssertError(t, http.StatusNotFound, "NOT_FOUND")
+	s.Do(t, http.MethodGet, path, nil, token).AssertOK(t)
+	s.Do(t, http.MethodGet, path, nil, editorToken).AssertOK(t)
+	s.Do(t, http.MethodGet, path+"/comments", nil, "").AssertError(t, http.StatusNotFound, "NOT_FOUND")
+	s.Do(t, http.MethodGet, path, nil, "invalid").AssertError(t, http.StatusUnauthorized, "UNAUTHORIZED")
+
+	// 其他用户不能得知草稿是否存在
+	s.Do(t, http.MethodPut, path, map[string]string{"title": "改"}, bobToken).AssertError(t, http.StatusNotFound, "NOT_FOUND")
+	changeStatus(t, s, bobToken, draft.ID, "publish").AssertError(t, http.StatusNotFound, "NOT_FOUND")
+	s.Do(t, http.MethodPost, path+"/comments", map[string]string{"content": "抢沙发"}, bobToken).AssertError(t, http.StatusNotFound, "NOT_FOUND")
+
+	// 预览草稿不计入浏览量
+	var a article
+	resp := s.Do(t, http.MethodGet, path, nil, token)
+	resp.DecodeData(t, &a)
+	if a.Views != 0 {
+		t.Fatalf("草稿浏览量应为 0，实际 %d", a.Views)
+	}
+
+	// 按状态查询草稿需要登录，普通用户只能看到自己的草稿
+	bobDraft := createDraft(t, s, bobToken, "bob 的草稿")
+	s.Do(t, http.MethodGet, "/api/v1/articles?status=draft", nil, "").AssertError(t, http.StatusUnauthorized, "UNAUTHORIZED")
+	s.Do(t, http.MethodGet, "/api/v1/articles?status=unknown", nil, "").AssertError(t, http.StatusBadRequest, "INVALID_INPUT")
+	if ids := listArticles(t, s, "/api/v1/articles?status=draft", token); len(ids) != 1 || ids[0] != draft.ID {
+		t.Fatalf("作者只能看到自己的草稿: %v", ids)
+	}
+	if ids := listArticles(t, s, "/api/v1/articles?status=draft", editorToken); len(ids) != 2 {
+		t.Fatalf("编辑可以看到全部草稿: %v", ids)
+	}
+
+	// 我的草稿
+	if ids := listArticles(t, s, "/api/v1/users/me/drafts", bobToken); len(ids) != 1 || ids[0] != bobDraft.ID {
+		t.Fatalf("我的草稿不符: %v", ids)
+	}
+	if ids := listArticles(t, s, "/api/v1/users/me/drafts", editorToken); len(ids) != 0 {
+		t.Fatalf("我的草稿只包含本人的文章: %v", ids)
+	}
+	s.Do(t, http.MethodGet, "/api/v1/users/me/drafts", nil, "").AssertError(t, http.StatusUnauthorized, "UNAUTHORIZED")
+}
+
+func TestPublishUnpublishArchive(t *testing.T) {
+	s := testutil.NewServer(t)
+	token, _ := s.Register(t, "alice", "alice@example.com", "secret123")
+	bobToken, _ := s.Register(t, "bob", "bob@example.com", "secret123")
+	editorToken, editorID := s.RegisterWithRole(t, "eve", "eve@example.com", "secret123", models.RoleEditor)
+
+	draft := createDraft(t, s, token, "草稿")
+
+	var a article
+	resp := changeStatus(t, s, token, draft.ID, "publish")
+	resp.AssertOK(t)
+	resp.DecodeData(t, &a)
+	if a.Status != models.ArticlePublished || a.PublishedAt == nil {
+		t.Fatalf("发布后状态不符: %+v", a)
+	}
+	firstPublished := *a.PublishedAt
+	if ids := listArticles(t, s, "/api/v1/articles", ""); len(ids) != 1 || ids[0] != draft.ID {
+		t.Fatalf("发布后应出现在列表中: %v", ids)
+	}
+	createComment(t, s, bobToken, draft.ID, "好文")
+
+	// 重复发布不改变状态
+	changeStatus(t, s, token, draft.ID, "publish").AssertOK(t)
+
+	// 只有作者本人或编辑可以修改状态
+	changeStatus(t, s, bobToken, draft.ID, "unpublish").AssertError(t, http.StatusForbidden, "FORBIDDEN")
+	changeStatus(t, s, editorToken, draft.ID, "unpublish").AssertOK(t)
+	if ids := listArticles(t, s, "/api/v1/articles", ""); len(ids) != 0 {
+		t.Fatalf("撤回后不应出现在列表中: %v", ids)
+	}
+	if ids := listArticles(t, s, "/api/v1/users/me/drafts", token); len(ids) != 1 {
+		t.Fatalf("撤回后应出现在我的草稿中: %v", ids)
+	}
+
+	// 再次发布保留首次发布时间
+	resp = changeStatus(t, s, token, draft.ID, "publish")
+	resp.AssertOK(t)
+	resp.DecodeData(t, &a)
+	if a.PublishedAt == nil || !a.PublishedAt.Equal(firstPublished) {
+		t.Fatalf("首次发布时间不应改变: %v -> %v", firstPublished, a.PublishedAt)
+	}
+
+	// 归档后可以通过链接访问，但不在列表中，也不能评论
+	changeStatus(t, s, token, draft.ID, "archive").AssertOK(t)
+	s.Do(t, http.MethodGet, fmt.Sprintf("/api/v1/articles/%d", draft.ID), nil, "").AssertOK(t)
+	if ids := listArticles(t, s, "/api/v1/articles", ""); len(ids) != 0 {
+		t.Fatalf("归档后不应出现在列表中: %v", ids)
+	}
+	if ids := listArticles(t, s, "/api/v1/articles?status=archived", ""); len(ids) != 1 {
+		t.Fatalf("按归档状态查询不符: %v", ids)
+	}
+	s.Do(t, http.MethodGet, fmt.Sprintf("/api/v1/articles/%d/comments", draft.ID), nil, "").AssertOK(t)
+	s.Do(t, http.MethodPost, fmt.Sprintf("/api/v1/articles/%d/comments", draft.ID), map[string]string{"content": "x"}, bobToken).
+		AssertError(t, http.StatusForbidden, "FORBIDDEN")
+
+	// 编辑修改他人文章状态记录审计日志
+	var logs []models.AuditLog
+	if err := s.DB.Where("action = ?", models.AuditArticleStatus).Find(&logs).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(logs) != 1 || logs[0].ActorID != editorID || logs[0].TargetID != draft.ID {
+		t.Fatalf("审计日志不符: %+v", logs)
+	}
+}
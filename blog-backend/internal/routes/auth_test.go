@@ -0,0 +1,72 @@
+package routes_test
+
+import (
+	"net/http"
+	"testing"
+
+	"blog-backend/internal/testutil"
+)
+
+type tokenPair struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"`
+}
+
+func login(t *testing.T, s *testutil.Server, email, password string) tokenPair {
+	t.Helper()
+	resp := s.Do(t, http.MethodPost, "/api/v1/auth/login", map[string]string{"email": email, "password": password}, "")
+	resp.AssertOK(t)
+
+	var pair tokenPair
+	resp.DecodeData(t, &pair)
+	if pair.Token == "" || pair.RefreshToken == "" || pair.ExpiresIn <= 0 {
+		t.Fatalf("unexpected token pair: %s", resp.Body)
+	}
+	return pair
+}
+
+func refresh(t *testing.T, s *testutil.Server, refreshToken string) *testutil.Response {
+	t.Helper()
+	return s.Do(t, http.MethodPost, "/api/v1/auth/refresh", map[string]string{"refresh_token": refreshToken}, "")
+}
+
+func TestRefreshTokenRotation(t *testing.T) {
+	s := testutil.NewServer(t)
+	s.Register(t, "alice", "alice@example.com", "secret123")
+	first := login(t, s, "alice@example.com", "secret123")
+
+	resp := refresh(t, s, first.RefreshToken)
+	resp.AssertOK(t)
+	var second tokenPair
+	resp.DecodeData(t, &second)
+	if second.RefreshToken == first.RefreshToken {
+		t.Fatal("刷新令牌应当轮换")
+	}
+	s.Do(t, http.MethodGet, "/api/v1/users/me", nil, second.Token).AssertOK(t)
+
+	// 旧的刷新令牌被再次使用，视为泄露并撤销整个会话
+	refresh(t, s, first.RefreshToken).AssertError(t, http.StatusUnauthorized, "UNAUTHORIZED")
+	refresh(t, s, second.RefreshToken).AssertError(t, http.StatusUnauthorized, "UNAUTHORIZED")
+	s.Do(t, http.MethodGet, "/api/v1/users/me", nil, second.Token).AssertError(t, http.StatusUnauthorized, "UNAUTHORIZED")
+
+	refresh(t, s, "unknown").AssertError(t, http.StatusUnauthorized, "UNAUTHORIZED")
+	s.Do(t, http.MethodPost, "/api/v1/auth/refresh", map[string]string{}, "").AssertError(t, http.StatusBadRequest, "INVALID_INPUT")
+}
+
+func TestLogoutRevokesOnlyCurrentSession(t *testing.T) {
+	s := testutil.NewServer(t)
+	s.Register(t, "alice", "alice@example.com", "secret123")
+	laptop := login(t, s, "alice@example.com", "secret123")
+	phone := login(t, s, "alice@example.com", "secret123")
+
+	s.Do(t, http.MethodPost, "/api/v1/auth/logout", nil, laptop.Token).AssertOK(t)
+
+	s.Do(t, http.MethodGet, "/api/v1/users/me", nil, laptop.Token).AssertError(t, http.StatusUnauthorized, "UNAUTHORIZED")
+	refresh(t, s, laptop.RefreshToken).AssertError(t, http.StatusUnauthorized, "UNAUTHORIZED")
+
+	s.Do(t, http.MethodGet, "/api/v1/users/me", nil, phone.Token).AssertOK(t)
+	refresh(t, s, phone.RefreshToken).AssertOK(t)
+
+	s.Do(t, http.MethodPost, "/api/v1/auth/logout", nil, "").AssertError(t, http.StatusUnauthorized, "UNAUTHORIZED")
+}
@@ -0,0 +1,239 @@
+package routes_test
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+
+	"blog-backend/internal/auth"
+	"blog-backend/internal/routes"
+	"blog-backend/internal/testutil"
+)
+
+// 将私钥和公钥写入 PEM 文件，返回两个文件路径
+func writeKeyPair(t *testing.T, key crypto.Signer) (string, string) {
+	t.Helper()
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubDER, err := x509.MarshalPKIXPublicKey(key.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	private := filepath.Join(dir, "private.pem")
+	public := filepath.Join(dir, "public.pem")
+	if err := os.WriteFile(private, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(public, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER}), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return private, public
+}
+
+func newRSAKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+// 与 s 共用数据库、使用不同密钥配置的服务，模拟轮换期间的另一个实例
+func withKeys(t *testing.T, s *testutil.Server, private string, public ...string) *testutil.Server {
+	t.Helper()
+	cfg := *s.Config
+	cfg.JWT.PrivateKeyFile = private
+	cfg.JWT.PublicKeyFiles = public
+	r := gin.New()
+	if err := routes.SetupRoutes(r, s.DB, &cfg); err != nil {
+		t.Fatal(err)
+	}
+	return &testutil.Server{Engine: r, DB: s.DB, Config: &cfg}
+}
+
+// JWKS 不使用统一响应格式，直接读取
+func fetchJWKS(t *testing.T, s *testutil.Server) []auth.JWK {
+	t.Helper()
+	w := httptest.NewRecorder()
+	s.Engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("获取 JWKS 失败，状态码 %d: %s", w.Code, w.Body)
+	}
+	var set struct {
+		Keys []auth.JWK `json:"keys"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &set); err != nil || set.Keys == nil {
+		t.Fatalf("JWKS 格式错误: %v %s", err, w.Body)
+	}
+	return set.Keys
+}
+
+func tokenHeader(t *testing.T, token string) map[string]interface{} {
+	t.Helper()
+	parsed, _, err := jwt.NewParser().ParseUnverified(token, jwt.MapClaims{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return parsed.Header
+}
+
+func tokenClaims(t *testing.T, token string) jwt.MapClaims {
+	t.Helper()
+	claims := jwt.MapClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(token, claims); err != nil {
+		t.Fatal(err)
+	}
+	return claims
+}
+
+func TestJWTRejectsForgedTokens(t *testing.T) {
+	s := testutil.NewServer(t)
+	token, _ := s.Register(t, "alice", "alice@example.com", "secret123")
+	s.Do(t, http.MethodGet, "/api/v1/users/me", nil, token).AssertOK(t)
+	if keys := fetchJWKS(t, s); len(keys) != 0 {
+		t.Fatalf("HS256 不应公开任何密钥: %+v", keys)
+	}
+
+	claims := tokenClaims(t, token)
+	if claims["iss"] != "blog-backend" || claims["aud"] != "blog-api" {
+		t.Fatalf("签发方或受众不符: %v", claims)
+	}
+
+	forge := func(method jwt.SigningMethod, key interface{}, change func(jwt.MapClaims)) string {
+		forged := jwt.MapClaims{}
+		for k, v := range claims {
+			forged[k] = v
+		}
+		change(forged)
+		signed, err := jwt.NewWithClaims(method, forged).SignedString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return signed
+	}
+	secret := []byte(s.Config.JWT.Secret)
+	for name, forged := range map[string]string{
+		"iss":   forge(jwt.SigningMethodHS256, secret, func(c jwt.MapClaims) { c["iss"] = "other" }),
+		"aud":   forge(jwt.SigningMethodHS256, secret, func(c jwt.MapClaims) { c["aud"] = "other-service" }),
+		"exp":   forge(jwt.SigningMethodHS256, secret, func(c jwt.MapClaims) { delete(c, "exp") }),
+		"alg":   forge(jwt.SigningMethodHS512, secret, func(jwt.MapClaims) {}),
+		"none":  forge(jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, func(jwt.MapClaims) {}),
+		"other": forge(jwt.SigningMethodHS256, []byte("other"), func(jwt.MapClaims) {}),
+	} {
+		t.Run(name, func(t *testing.T) {
+			s.Do(t, http.MethodGet, "/api/v1/users/me", nil, forged).AssertError(t, http.StatusUnauthorized, "UNAUTHORIZED")
+		})
+	}
+}
+
+func TestJWTAsymmetricSigning(t *testing.T) {
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, key := range map[string]crypto.Signer{"RS256": newRSAKey(t), "EdDSA": edKey} {
+		t.Run(name, func(t *testing.T) {
+			private, public := writeKeyPair(t, key)
+			cfg := testutil.Config()
+			cfg.JWT.PrivateKeyFile = private
+			s := testutil.NewServerWithConfig(t, cfg)
+
+			token, _ := s.Register(t, "alice", "alice@example.com", "secret123")
+			s.Do(t, http.MethodGet, "/api/v1/users/me", nil, token).AssertOK(t)
+
+			keys := fetchJWKS(t, s)
+			header := tokenHeader(t, token)
+			if len(keys) != 1 || keys[0].Alg != name || keys[0].Use != "sig" || header["alg"] != name || header["kid"] != keys[0].Kid {
+				t.Fatalf("JWKS 与令牌头部不符: %+v %v", keys, header)
+			}
+
+			// 其他服务可以使用 JWKS 中的公钥校验令牌
+			pub, err := auth.LoadPublicKey(public)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := jwt.Parse(token, func(*jwt.Token) (interface{}, error) { return pub, nil }); err != nil {
+				t.Fatalf("公钥校验失败: %v", err)
+			}
+
+			// 使用共享密钥或以公钥作为 HMAC 密钥签名的令牌均被拒绝
+			claims := tokenClaims(t, token)
+			pemBytes, err := os.ReadFile(public)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, secret := range [][]byte{[]byte(cfg.JWT.Secret), pemBytes} {
+				forged := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+				forged.Header["kid"] = keys[0].Kid
+				signed, err := forged.SignedString(secret)
+				if err != nil {
+					t.Fatal(err)
+				}
+				s.Do(t, http.MethodGet, "/api/v1/users/me", nil, signed).AssertError(t, http.StatusUnauthorized, "UNAUTHORIZED")
+			}
+		})
+	}
+}
+
+func TestJWTKeyRotation(t *testing.T) {
+	oldPrivate, oldPublic := writeKeyPair(t, newRSAKey(t))
+	newPrivate, newPublic := writeKeyPair(t, newRSAKey(t))
+
+	cfg := testutil.Config()
+	cfg.JWT.PrivateKeyFile = oldPrivate
+	s := testutil.NewServerWithConfig(t, cfg)
+	oldToken, _ := s.Register(t, "alice", "alice@example.com", "secret123")
+
+	// 第一步：先发布新公钥，仍使用旧私钥签名
+	published := withKeys(t, s, oldPrivate, newPublic)
+	if keys := fetchJWKS(t, published); len(keys) != 2 || keys[0].Kid != tokenHeader(t, oldToken)["kid"] {
+		t.Fatalf("JWKS 应包含当前密钥和待启用的密钥: %+v", keys)
+	}
+
+	// 第二步：切换到新私钥，旧令牌在过期前仍然有效
+	rotated := withKeys(t, s, newPrivate, oldPublic)
+	rotated.Do(t, http.MethodGet, "/api/v1/users/me", nil, oldToken).AssertOK(t)
+	newToken, _ := rotated.Login(t, "alice@example.com", "secret123")
+	if tokenHeader(t, newToken)["kid"] == tokenHeader(t, oldToken)["kid"] {
+		t.Fatal("轮换后应使用新密钥签名")
+	}
+	published.Do(t, http.MethodGet, "/api/v1/users/me", nil, newToken).AssertOK(t)
+
+	// 第三步：移除旧公钥后旧令牌失效
+	retired := withKeys(t, s, newPrivate)
+	retired.Do(t, http.MethodGet, "/api/v1/users/me", nil, newToken).AssertOK(t)
+	retired.Do(t, http.MethodGet, "/api/v1/users/me", nil, oldToken).AssertError(t, http.StatusUnauthorized, "UNAUTHORIZED")
+	if keys := fetchJWKS(t, retired); len(keys) != 1 {
+		t.Fatalf("JWKS 不应再包含旧密钥: %+v", keys)
+	}
+}
+
+func TestJWTRejectsWeakKeys(t *testing.T) {
+	weak, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	private, _ := writeKeyPair(t, weak)
+	cfg := testutil.Config()
+	cfg.JWT.PrivateKeyFile = private
+	if err := routes.SetupRoutes(gin.New(), nil, cfg); err == nil {
+		t.Fatal("期望拒绝长度不足的 RSA 密钥")
+	}
+}
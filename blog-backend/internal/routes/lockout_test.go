@@ -0,0 +1,260 @@
+package routes_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"blog-backend/internal/models"
+	"blog-backend/internal/repository"
+	"blog-backend/internal/services"
+	"blog-backend/internal/testutil"
+)
+
+func attemptLogin(t *testing.T, s *testutil.Server, email, password string) *testutil.Response {
+	t.Helper()
+	return s.Do(t, http.MethodPost, "/api/v1/auth/login", map[string]string{"email": email, "password": password}, "")
+}
+
+// 错误密码登录，forwardedFor 不为空时添加 X-Forwarded-For 请求头，返回状态码
+func attemptLoginVia(t *testing.T, s *testutil.Server, email, forwardedFor string) int {
+	t.Helper()
+	body, err := json.Marshal(map[string]string{"email": email, "password": "wrong"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if forwardedFor != "" {
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+	}
+	rec := httptest.NewRecorder()
+	s.Engine.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func assertLocked(t *testing.T, resp *testutil.Response, retryAfter string) {
+	t.Helper()
+	resp.AssertError(t, http.StatusTooManyRequests, "TOO_MANY_ATTEMPTS")
+	if got := resp.Header.Get("Retry-After"); got != retryAfter {
+		t.Fatalf("期望 Retry-After 为 %s，实际 %q", retryAfter, got)
+	}
+}
+
+// 模拟锁定到期
+func expireLockout(t *testing.T, s *testutil.Server, email string) {
+	t.Helper()
+	err := s.DB.Model(&models.LoginThrottle{}).
+		Where("subject = ?", models.AccountThrottle(email)).
+		Update("locked_until", time.Now().Add(-time.Second)).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoginLockoutBackoff(t *testing.T) {
+	s := testutil.NewServer(t)
+	s.Register(t, "alice", "alice@example.com", "secret123")
+
+	for i := 0; i < 5; i++ {
+		attemptLogin(t, s, "alice@example.com", "wrong").AssertError(t, http.StatusUnauthorized, "UNAUTHORIZED")
+	}
+	// 锁定期内正确的密码同样被拒绝，邮箱大小写不影响计数
+	assertLocked(t, attemptLogin(t, s, "alice@example.com", "secret123"), "60")
+	assertLocked(t, attemptLogin(t, s, "Alice@Example.com", "secret123"), "60")
+
+	// 锁定到期后再次失败，锁定时间翻倍
+	expireLockout(t, s, "alice@example.com")
+	attemptLogin(t, s, "alice@example.com", "wrong").AssertError(t, http.StatusUnauthorized, "UNAUTHORIZED")
+	assertLocked(t, attemptLogin(t, s, "alice@example.com", "secret123"), "120")
+
+	// 登录成功后重新计数
+	expireLockout(t, s, "alice@example.com")
+	login(t, s, "alice@example.com", "secret123")
+	for i := 0; i < 4; i++ {
+		attemptLogin(t, s, "alice@example.com", "wrong").AssertError(t, http.StatusUnauthorized, "UNAUTHORIZED")
+	}
+	login(t, s, "alice@example.com", "secret123")
+}
+
+// 需要验证当前密码的接口与登录共用失败计数
+func TestReauthenticateLockout(t *testing.T) {
+	s := testutil.NewServer(t)
+	token, _ := s.Register(t, "alice", "alice@example.com", "secret123")
+
+	type request struct {
+		method, path string
+		body         func(password string) map[string]string
+	}
+	password := func(password string) map[string]string { return map[string]string{"password": password} }
+	requests := []request{
+		{http.MethodPut, "/api/v1/users/me/password", func(password string) map[string]string {
+			return map[string]string{"current_password": password, "new_password": "newsecret456"}
+		}},
+		{http.MethodPost, "/api/v1/users/me/email", func(password string) map[string]string {
+			return map[string]string{"current_password": password, "new_email": "alice2@example.com"}
+		}},
+		{http.MethodDelete, "/api/v1/users/me", password},
+		{http.MethodPost, "/api/v1/users/me/2fa/enroll", password},
+		{http.MethodPost, "/api/v1/users/me/2fa/disable", password},
+	}
+	do := func(r request, password string) *testutil.Response {
+		return s.Do(t, r.method, r.path, r.body(password), token)
+	}
+
+	for _, r := range requests {
+		do(r, "wrong").AssertError(t, http.StatusBadRequest, "INVALID_INPUT")
+	}
+	// 锁定期内正确的密码同样被拒绝
+	for _, r := range append(requests, request{http.MethodPost, "/api/v1/users/me/2fa/recovery-codes", password}) {
+		assertLocked(t, do(r, "secret123"), "60")
+	}
+	assertLocked(t, attemptLogin(t, s, "alice@example.com", "secret123"), "60")
+
+	// 验证成功后重新计数
+	expireLockout(t, s, "alice@example.com")
+	do(requests[3], "secret123").AssertOK(t)
+	for i := 0; i < 4; i++ {
+		do(requests[0], "wrong").AssertError(t, http.StatusBadRequest, "INVALID_INPUT")
+	}
+	do(requests[0], "secret123").AssertOK(t)
+}
+
+func TestLoginLockoutUnknownEmail(t *testing.T) {
+	s := testutil.NewServer(t)
+
+	// 未注册的邮箱与已注册的邮箱表现一致
+	for i := 0; i < 5; i++ {
+		attemptLogin(t, s, "nobody@example.com", "wrong").AssertError(t, http.StatusUnauthorized, "UNAUTHORIZED")
+	}
+	assertLocked(t, attemptLogin(t, s, "nobody@example.com", "wrong"), "60")
+}
+
+func TestLoginLockoutPerIP(t *testing.T) {
+	cfg := testutil.Config()
+	cfg.Account.LoginIPMaxFailures = 3
+	s := testutil.NewServerWithConfig(t, cfg)
+	s.Register(t, "alice", "alice@example.com", "secret123")
+
+	for i := 0; i < 3; i++ {
+		email := fmt.Sprintf("user%d@example.com", i)
+		attemptLogin(t, s, email, "wrong").AssertError(t, http.StatusUnauthorized, "UNAUTHORIZED")
+	}
+	// 同一 IP 的其他账号也被锁定
+	assertLocked(t, attemptLogin(t, s, "alice@example.com", "secret123"), "60")
+}
+
+func TestAdminUnlockUser(t *testing.T) {
+	s := testutil.NewServer(t)
+	_, aliceID := s.Register(t, "alice", "alice@example.com", "secret123")
+	adminToken, adminID := s.RegisterWithRole(t, "root", "root@example.com", "secret123", models.RoleAdmin)
+	aliceToken := login(t, s, "alice@example.com", "secret123").Token
+
+	for i := 0; i < 5; i++ {
+		attemptLogin(t, s, "alice@example.com", "wrong").AssertError(t, http.StatusUnauthorized, "UNAUTHORIZED")
+	}
+	assertLocked(t, attemptLogin(t, s, "alice@example.com", "secret123"), "60")
+
+	path := fmt.Sprintf("/api/v1/admin/users/%d/unlock", aliceID)
+	s.Do(t, http.MethodPost, path, nil, aliceToken).AssertError(t, http.StatusForbidden, "FORBIDDEN")
+	s.Do(t, http.MethodPost, "/api/v1/admin/users/999/unlock", nil, adminToken).AssertError(t, http.StatusNotFound, "NOT_FOUND")
+	s.Do(t, http.MethodPost, path, nil, adminToken).AssertOK(t)
+	login(t, s, "alice@example.com", "secret123")
+
+	logs := listAuditLogs(t, s, adminToken)
+	want := auditLog{ActorID: adminID, ActorRole: models.RoleAdmin, Action: models.AuditUserUnlock, TargetType: "user", TargetID: aliceID, OwnerID: aliceID}
+	if len(logs) == 0 || logs[0] != want {
+		t.Fatalf("审计日志不符: %+v", logs)
+	}
+}
+
+func TestTwoFactorCodeFailuresLockAccount(t *testing.T) {
+	s := testutil.NewServer(t)
+	token, _ := s.Register(t, "alice", "alice@example.com", "secret123")
+	enableTwoFactor(t, s, token, "secret123")
+
+	// 重新登录不会重置验证码的失败次数
+	for i := 0; i < 5; i++ {
+		challenge := loginChallenge(t, s, "alice@example.com", "secret123")
+		loginTwoFactor(t, s, challenge, "000000").AssertError(t, http.StatusBadRequest, "INVALID_INPUT")
+	}
+	assertLocked(t, attemptLogin(t, s, "alice@example.com", "secret123"), "60")
+}
+
+// 未配置可信代理时，伪造 X-Forwarded-For 不能绕过按 IP 的锁定
+func TestLoginLockoutIgnoresForwardedFor(t *testing.T) {
+	cfg := testutil.Config()
+	cfg.Account.LoginIPMaxFailures = 3
+	s := testutil.NewServerWithConfig(t, cfg)
+
+	for i := 0; i < 3; i++ {
+		if code := attemptLoginVia(t, s, fmt.Sprintf("user%d@example.com", i), fmt.Sprintf("10.0.0.%d", i+1)); code != http.StatusUnauthorized {
+			t.Fatalf("第 %d 次登录应返回 401，实际 %d", i+1, code)
+		}
+	}
+	if code := attemptLoginVia(t, s, "other@example.com", "10.0.0.99"); code != http.StatusTooManyRequests {
+		t.Fatalf("更换 X-Forwarded-For 后仍应被锁定，实际 %d", code)
+	}
+}
+
+// 配置可信代理后按 X-Forwarded-For 区分客户端
+func TestLoginLockoutTrustedProxy(t *testing.T) {
+	cfg := testutil.Config()
+	cfg.Account.LoginIPMaxFailures = 3
+	// httptest 请求的来源地址为 192.0.2.1
+	cfg.Server.TrustedProxies = []string{"192.0.2.0/24"}
+	s := testutil.NewServerWithConfig(t, cfg)
+
+	for i := 0; i < 3; i++ {
+		attemptLoginVia(t, s, fmt.Sprintf("user%d@example.com", i), "10.0.0.1")
+	}
+	if code := attemptLoginVia(t, s, "other@example.com", "10.0.0.1"); code != http.StatusTooManyRequests {
+		t.Fatalf("同一客户端应被锁定，实际 %d", code)
+	}
+	if code := attemptLoginVia(t, s, "other@example.com", "10.0.0.2"); code != http.StatusUnauthorized {
+		t.Fatalf("其他客户端不应被锁定，实际 %d", code)
+	}
+}
+
+// 并发的登录失败全部计入失败次数
+func TestLoginLockoutConcurrentFailures(t *testing.T) {
+	s := testutil.NewServer(t)
+	cfg := s.Config.Account
+	cfg.LoginMaxFailures = 1000
+	cfg.LoginIPMaxFailures = 1000
+	throttle := services.NewLoginThrottleService(repository.NewLoginThrottleRepository(s.DB), cfg)
+
+	const attempts = 50
+	errs := make(chan error, attempts)
+	var wg sync.WaitGroup
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- throttle.Fail(context.Background(), "alice@example.com", "192.0.2.1")
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, subject := range []string{models.AccountThrottle("alice@example.com"), models.IPThrottle("192.0.2.1")} {
+		var record models.LoginThrottle
+		if err := s.DB.Where("subject = ?", subject).First(&record).Error; err != nil {
+			t.Fatal(err)
+		}
+		if record.Failures != attempts {
+			t.Fatalf("%s 应记录 %d 次失败，实际 %d", subject, attempts, record.Failures)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package routes_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v4"
+
+	"blog-backend/internal/models"
+	"blog-backend/internal/testutil"
+)
+
+func newOIDCServer(t *testing.T) (*testutil.Server, *testutil.MockIdP) {
+	t.Helper()
+	idp := testutil.NewMockIdP(t)
+	cfg := testutil.Config()
+	idp.Configure(cfg)
+	return testutil.NewServerWithConfig(t, cfg), idp
+}
+
+// 获取授权地址并在身份提供方完成授权，返回回调参数
+func oidcAuthorize(t *testing.T, s *testutil.Server, idp *testutil.MockIdP, user testutil.IdPUser) map[string]string {
+	t.Helper()
+	resp := s.Do(t, http.MethodGet, "/api/v1/auth/oidc/authorize", nil, "")
+	resp.AssertOK(t)
+	var data struct {
+		URL string `json:"authorization_url"`
+	}
+	resp.DecodeData(t, &data)
+
+	code, state := idp.Authorize(t, data.URL, user)
+	return map[string]string{"code": code, "state": state}
+}
+
+func oidcCallback(t *testing.T, s *testutil.Server, callback map[string]string) *testutil.Response {
+	t.Helper()
+	return s.Do(t, http.MethodPost, "/api/v1/auth/oidc/callback", callback, "")
+}
+
+// OIDC 登录成功，返回当前用户 ID
+func oidcLogin(t *testing.T, s *testutil.Server, idp *testutil.MockIdP, user testutil.IdPUser) uint {
+	t.Helper()
+	resp := oidcCallback(t, s, oidcAuthorize(t, s, idp, user))
+	resp.AssertOK(t)
+	var pair tokenPair
+	resp.DecodeData(t, &pair)
+
+	resp = s.Do(t, http.MethodGet, "/api/v1/users/me", nil, pair.Token)
+	resp.AssertOK(t)
+	var me models.User
+	resp.DecodeData(t, &me)
+	return me.ID
+}
+
+func TestOIDCLoginCreatesUser(t *testing.T) {
+	s, idp := newOIDCServer(t)
+	user := testutil.IdPUser{Subject: "sub-1", Email: "alice@example.com", EmailVerified: true, Username: "alice"}
+
+	id := oidcLogin(t, s, idp, user)
+	var created models.User
+	if err := s.DB.First(&created, id).Error; err != nil {
+		t.Fatal(err)
+	}
+	if created.Username != "alice" || created.Email != "alice@example.com" || !created.Verified() {
+		t.Fatalf("自动创建的用户不符: %+v", created)
+	}
+
+	// 再次登录使用已关联的账号，即使邮箱已变化
+	user.Email = "alice@new.example.com"
+	if again := oidcLogin(t, s, idp, user); again != id {
+		t.Fatalf("期望用户 %d，实际 %d", id, again)
+	}
+
+	// 用户名被占用时自动添加后缀
+	other := oidcLogin(t, s, idp, testutil.IdPUser{Subject: "sub-2", Email: "other@example.com", EmailVerified: true, Username: "alice"})
+	if other == id {
+		t.Fatal("不同的外部身份不应关联到同一用户")
+	}
+}
+
+func TestOIDCLoginLinksVerifiedEmail(t *testing.T) {
+	s, idp := newOIDCServer(t)
+	_, aliceID := s.Register(t, "alice", "alice@example.com", "secret123")
+
+	// 未验证的邮箱不能关联已有账号
+	unverified := testutil.IdPUser{Subject: "sub-1", Email: "alice@example.com", Username: "mallory"}
+	oidcCallback(t, s, oidcAuthorize(t, s, idp, unverified)).AssertError(t, http.StatusForbidden, "FORBIDDEN")
+
+	verified := testutil.IdPUser{Subject: "sub-1", Email: "alice@example.com", EmailVerified: true}
+	if id := oidcLogin(t, s, idp, verified); id != aliceID {
+		t.Fatalf("期望关联到用户 %d，实际 %d", aliceID, id)
+	}
+	// 原有密码仍可登录
+	s.Login(t, "alice@example.com", "secret123")
+}
+
+// 抢注他人邮箱的未验证账号不能被关联，否则抢注者的密码在真正的所有者登录后仍然有效
+func TestOIDCLoginRejectsUnverifiedAccount(t *testing.T) {
+	s, idp := newOIDCServer(t)
+	register(t, s, "mallory", "alice@example.com", "secret123").AssertOK(t)
+
+	verified := testutil.IdPUser{Subject: "sub-1", Email: "alice@example.com", EmailVerified: true}
+	oidcCallback(t, s, oidcAuthorize(t, s, idp, verified)).AssertError(t, http.StatusConflict, "INVALID_INPUT")
+
+	var user models.User
+	if err := s.DB.Where("email = ?", "alice@example.com").First(&user).Error; err != nil {
+		t.Fatal(err)
+	}
+	if user.Verified() {
+		t.Fatal("未验证的账号不应被标记为已验证")
+	}
+	var links int64
+	s.DB.Model(&models.UserIdentity{}).Count(&links)
+	if links != 0 {
+		t.Fatalf("不应关联外部身份，实际 %d 条", links)
+	}
+}
+
+func TestOIDCCallbackRejectsInvalidRequests(t *testing.T) {
+	s, idp := newOIDCServer(t)
+	user := testutil.IdPUser{Subject: "sub-1", Email: "alice@example.com", EmailVerified: true}
+
+	// state 只能使用一次
+	callback := oidcAuthorize(t, s, idp, user)
+	oidcCallback(t, s, callback).AssertOK(t)
+	oidcCallback(t, s, callback).AssertError(t, http.StatusUnauthorized, "UNAUTHORIZED")
+	oidcCallback(t, s, map[string]string{"code": callback["code"], "state": "invalid"}).
+		AssertError(t, http.StatusUnauthorized, "UNAUTHORIZED")
+
+	// 授权码无效
+	callback = oidcAuthorize(t, s, idp, user)
+	callback["code"] = "invalid"
+	oidcCallback(t, s, callback).AssertError(t, http.StatusUnauthorized, "UNAUTHORIZED")
+
+	// ID Token 的受众、nonce 不匹配或已过期
+	for _, tamper := range []func(jwt.MapClaims){
+		func(c jwt.MapClaims) { c["aud"] = "other-client" },
+		func(c jwt.MapClaims) { c["nonce"] = "replayed" },
+		func(c jwt.MapClaims) { c["exp"] = int64(1) },
+	} {
+		idp.Tamper = tamper
+		oidcCallback(t, s, oidcAuthorize(t, s, idp, user)).AssertError(t, http.StatusUnauthorized, "UNAUTHORIZED")
+	}
+}
+
+func TestOIDCLoginRequiresTwoFactor(t *testing.T) {
+	s, idp := newOIDCServer(t)
+	token, _ := s.Register(t, "alice", "alice@example.com", "secret123")
+	enableTwoFactor(t, s, token, "secret123")
+
+	user := testutil.IdPUser{Subject: "sub-1", Email: "alice@example.com", EmailVerified: true}
+	resp := oidcCallback(t, s, oidcAuthorize(t, s, idp, user))
+	resp.AssertOK(t)
+	var challenge struct {
+		Required bool   `json:"two_factor_required"`
+		Token    string `json:"challenge_token"`
+		Access   string `json:"token"`
+	}
+	resp.DecodeData(t, &challenge)
+	if !challenge.Required || challenge.Token == "" || challenge.Access != "" {
+		t.Fatalf("期望返回两步验证挑战，实际: %s", resp.Body)
+	}
+}
+
+// 自动创建的账号没有可用的本地密码，通过忘记密码设置后才能完成需要验证密码的操作
+func TestOIDCAccountSetsPasswordViaReset(t *testing.T) {
+	s, idp := newOIDCServer(t)
+	user := testutil.IdPUser{Subject: "sub-1", Email: "alice@example.com", EmailVerified: true, Username: "alice"}
+	resp := oidcCallback(t, s, oidcAuthorize(t, s, idp, user))
+	resp.AssertOK(t)
+	var pair tokenPair
+	resp.DecodeData(t, &pair)
+	deleteAccount(t, s, pair.Token, "secret123").AssertError(t, http.StatusBadRequest, "INVALID_INPUT")
+
+	forgotPassword(t, s, "alice@example.com")
+	resetPassword(t, s, s.MailToken(t, "alice@example.com"), "newsecret456").AssertOK(t)
+
+	// 设置密码后可以用密码登录，并完成两步验证、注销等操作
+	token, _ := s.Login(t, "alice@example.com", "newsecret456")
+	enableTwoFactor(t, s, token, "newsecret456")
+	s.Do(t, http.MethodPost, "/api/v1/users/me/2fa/disable", map[string]string{"password": "newsecret456"}, token).AssertOK(t)
+	deleteAccount(t, s, token, "newsecret456").AssertOK(t)
+}
@@ -0,0 +1,72 @@
+package routes_test
+
+import (
+	"net/http"
+	"testing"
+
+	"blog-backend/internal/testutil"
+)
+
+func forgotPassword(t *testing.T, s *testutil.Server, email string) {
+	t.Helper()
+	s.Do(t, http.MethodPost, "/api/v1/auth/forgot-password", map[string]string{"email": email}, "").AssertOK(t)
+}
+
+func resetPassword(t *testing.T, s *testutil.Server, token, password string) *testutil.Response {
+	t.Helper()
+	return s.Do(t, http.MethodPost, "/api/v1/auth/reset-password", map[string]string{"token": token, "password": password}, "")
+}
+
+func TestPasswordReset(t *testing.T) {
+	s := testutil.NewServer(t)
+	s.Register(t, "alice", "alice@example.com", "secret123")
+	session := login(t, s, "alice@example.com", "secret123")
+	pat := createPersonalToken(t, s, session.Token, map[string]interface{}{"name": "cli", "scopes": []string{"profile:read"}})
+	bobToken, _ := s.Register(t, "bob", "bob@example.com", "secret123")
+	bobPAT := createPersonalToken(t, s, bobToken, map[string]interface{}{"name": "cli", "scopes": []string{"profile:read"}})
+
+	forgotPassword(t, s, "alice@example.com")
+	token := s.MailToken(t, "alice@example.com")
+
+	resetPassword(t, s, token, "123").AssertError(t, http.StatusBadRequest, "INVALID_INPUT")
+	resetPassword(t, s, token, "newsecret456").AssertOK(t)
+
+	// 重置后全部会话和个人访问令牌失效，旧密码不能再登录
+	s.Do(t, http.MethodGet, "/api/v1/users/me", nil, session.Token).AssertError(t, http.StatusUnauthorized, "UNAUTHORIZED")
+	refresh(t, s, session.RefreshToken).AssertError(t, http.StatusUnauthorized, "UNAUTHORIZED")
+	s.Do(t, http.MethodGet, "/api/v1/users/me", nil, pat.Token).AssertError(t, http.StatusUnauthorized, "UNAUTHORIZED")
+	s.Do(t, http.MethodGet, "/api/v1/users/me", nil, bobPAT.Token).AssertOK(t)
+	s.Do(t, http.MethodPost, "/api/v1/auth/login", map[string]string{"email": "alice@example.com", "password": "secret123"}, "").
+		AssertError(t, http.StatusUnauthorized, "UNAUTHORIZED")
+	login(t, s, "alice@example.com", "newsecret456")
+
+	// 令牌只能使用一次
+	resetPassword(t, s, token, "another789").AssertError(t, http.StatusBadRequest, "INVALID_INPUT")
+	resetPassword(t, s, "unknown", "another789").AssertError(t, http.StatusBadRequest, "INVALID_INPUT")
+}
+
+func TestForgotPasswordInvalidatesEarlierLinks(t *testing.T) {
+	s := testutil.NewServer(t)
+	s.Register(t, "alice", "alice@example.com", "secret123")
+
+	forgotPassword(t, s, "alice@example.com")
+	first := s.MailToken(t, "alice@example.com")
+	forgotPassword(t, s, "alice@example.com")
+	second := s.MailToken(t, "alice@example.com")
+	if first == second {
+		t.Fatal("每次请求应当生成新的重置令牌")
+	}
+
+	resetPassword(t, s, first, "newsecret456").AssertError(t, http.StatusBadRequest, "INVALID_INPUT")
+	resetPassword(t, s, second, "newsecret456").AssertOK(t)
+}
+
+func TestForgotPasswordUnknownEmail(t *testing.T) {
+	s := testutil.NewServer(t)
+
+	forgotPassword(t, s, "nobody@example.com")
+	if mails := s.Mails(t, "nobody@example.com"); len(mails) != 0 {
+		t.Fatalf("未注册的邮箱不应收到邮件，实际 %d 封", len(mails))
+	}
+	s.Do(t, http.MethodPost, "/api/v1/auth/forgot-password", map[string]string{"email": "invalid"}, "").AssertError(t, http.StatusBadRequest, "INVALID_INPUT")
+}
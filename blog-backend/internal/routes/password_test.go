@@ -0,0 +1,142 @@
+package routes_test
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"blog-backend/internal/auth"
+	"blog-backend/internal/models"
+	"blog-backend/internal/routes"
+	"blog-backend/internal/services"
+	"blog-backend/internal/testutil"
+)
+
+func storedPassword(t *testing.T, s *testutil.Server, userID uint) string {
+	t.Helper()
+	var user models.User
+	if err := s.DB.First(&user, userID).Error; err != nil {
+		t.Fatal(err)
+	}
+	return user.Password
+}
+
+func register(t *testing.T, s *testutil.Server, username, email, password string) *testutil.Response {
+	t.Helper()
+	return s.Do(t, http.MethodPost, "/api/v1/auth/register", map[string]string{"username": username, "email": email, "password": password}, "")
+}
+
+func TestPasswordHashedWithArgon2id(t *testing.T) {
+	s := testutil.NewServer(t)
+	_, userID := s.Register(t, "alice", "alice@example.com", "secret123")
+
+	if hashed := storedPassword(t, s, userID); !strings.HasPrefix(hashed, "$argon2id$v=19$") {
+		t.Fatalf("期望 argon2id 哈希，实际 %q", hashed)
+	}
+
+	// 与 bcrypt 不同，超过 72 字节的部分同样参与校验
+	long := strings.Repeat("a", 72)
+	s.Register(t, "bob", "bob@example.com", long+"first")
+	s.Do(t, http.MethodPost, "/api/v1/auth/login", map[string]string{"email": "bob@example.com", "password": long + "second"}, "").
+		AssertError(t, http.StatusUnauthorized, "UNAUTHORIZED")
+}
+
+func TestLegacyPasswordRehashedOnLogin(t *testing.T) {
+	s := testutil.NewServer(t)
+	_, userID := s.Register(t, "alice", "alice@example.com", "secret123")
+
+	// 旧版本保存的 bcrypt 哈希和参数较弱的 argon2id 哈希登录后都会按当前参数重新加密
+	weak, err := auth.Argon2idHasher{Memory: 8 * 1024, Iterations: 1, Parallelism: 1}.Hash("secret123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	legacy, err := auth.BcryptHasher{Cost: 4}.Hash("secret123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, hashed := range []string{legacy, weak} {
+		if err := s.DB.Model(&models.User{}).Where("id = ?", userID).Update("password", hashed).Error; err != nil {
+			t.Fatal(err)
+		}
+
+		s.Do(t, http.MethodPost, "/api/v1/auth/login", map[string]string{"email": "alice@example.com", "password": "wrong"}, "").
+			AssertError(t, http.StatusUnauthorized, "UNAUTHORIZED")
+		if storedPassword(t, s, userID) != hashed {
+			t.Fatal("密码错误时不应重新加密")
+		}
+
+		s.Login(t, "alice@example.com", "secret123")
+		rehashed := storedPassword(t, s, userID)
+		if !strings.HasPrefix(rehashed, "$argon2id$v=19$m=19456,t=2,p=1$") {
+			t.Fatalf("期望重新加密为当前参数，实际 %q", rehashed)
+		}
+		s.Login(t, "alice@example.com", "secret123")
+		if storedPassword(t, s, userID) != rehashed {
+			t.Fatal("使用当前参数的哈希不应再次重新加密")
+		}
+	}
+}
+
+func TestPasswordPolicy(t *testing.T) {
+	sum := sha1.Sum([]byte("correcthorse"))
+	breached := filepath.Join(t.TempDir(), "breached.txt")
+	list := "password123\r\n\n" + strings.ToUpper(hex.EncodeToString(sum[:])) + ":42\n"
+	if err := os.WriteFile(breached, []byte(list), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg := testutil.Config()
+	cfg.Account.PasswordBreachedFile = breached
+	s := testutil.NewServerWithConfig(t, cfg)
+
+	for _, password := range []string{
+		"short",
+		strings.Repeat("长", 129),
+		"Alice_1990",
+		"alice@example.com",
+		"password123",
+		"correcthorse",
+	} {
+		register(t, s, "alice_1990", "alice@example.com", password).AssertError(t, http.StatusBadRequest, "INVALID_INPUT")
+	}
+	// 长度按字符计算
+	register(t, s, "alice_1990", "alice@example.com", "密码足够长了吗").AssertError(t, http.StatusBadRequest, "INVALID_INPUT")
+	register(t, s, "alice_1990", "alice@example.com", "密码足够长了吧吧").AssertOK(t)
+
+	token, _ := s.Register(t, "bob", "bob@example.com", "secret123")
+	change := func(next string) *testutil.Response {
+		return s.Do(t, http.MethodPut, "/api/v1/users/me/password", map[string]string{"current_password": "secret123", "new_password": next}, token)
+	}
+	change("correcthorse").AssertError(t, http.StatusBadRequest, "INVALID_INPUT")
+	change("bob@example.com").AssertError(t, http.StatusBadRequest, "INVALID_INPUT")
+
+	// 重置密码时新密码不符合规则，重置链接仍然有效
+	forgotPassword(t, s, "bob@example.com")
+	reset := s.MailToken(t, "bob@example.com")
+	resetPassword(t, s, reset, "password123").AssertError(t, http.StatusBadRequest, "INVALID_INPUT")
+	resetPassword(t, s, reset, "newsecret456").AssertOK(t)
+	s.Login(t, "bob@example.com", "newsecret456")
+}
+
+// 泄露密码列表整个读入内存，条数超过上限时拒绝启动
+func TestPasswordBreachedFileTooLarge(t *testing.T) {
+	var b strings.Builder
+	for i := 0; i <= services.MaxBreachedPasswords; i++ {
+		fmt.Fprintf(&b, "p%d\n", i)
+	}
+	breached := filepath.Join(t.TempDir(), "breached.txt")
+	if err := os.WriteFile(breached, []byte(b.String()), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg := testutil.Config()
+	cfg.Account.PasswordBreachedFile = breached
+	if err := routes.SetupRoutes(gin.New(), nil, cfg); err == nil {
+		t.Fatal("期望拒绝超过上限的泄露密码列表")
+	}
+}
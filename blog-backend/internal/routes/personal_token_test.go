@@ -0,0 +1,151 @@
+package routes_test
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"blog-backend/internal/models"
+	"blog-backend/internal/testutil"
+)
+
+type personalToken struct {
+	ID     uint     `json:"id"`
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes"`
+	Token  string   `json:"token"`
+}
+
+func createPersonalToken(t *testing.T, s *testutil.Server, token string, body map[string]interface{}) personalToken {
+	t.Helper()
+	resp := s.Do(t, http.MethodPost, "/api/v1/users/me/tokens", body, token)
+	resp.AssertOK(t)
+
+	var pat personalToken
+	resp.DecodeData(t, &pat)
+	if !strings.HasPrefix(pat.Token, "blogpat_") {
+		t.Fatalf("unexpected personal token: %s", resp.Body)
+	}
+	return pat
+}
+
+func TestPersonalTokenScopes(t *testing.T) {
+	s := testutil.NewServer(t)
+	token, _ := s.Register(t, "alice", "alice@example.com", "secret123")
+	pat := createPersonalToken(t, s, token, map[string]interface{}{
+		"name":   "ci",
+		"scopes": []string{"articles:write", "articles:write"},
+	})
+	if len(pat.Scopes) != 1 || pat.Scopes[0] != "articles:write" {
+		t.Fatalf("权限范围不符: %v", pat.Scopes)
+	}
+
+	// 拥有 articles:write 的令牌可以发布和修改文章
+	a := createArticle(t, s, pat.Token, "发布说明")
+	s.Do(t, http.MethodPut, fmt.Sprintf("/api/v1/articles/%d", a.ID), map[string]string{"title": "v1.0 发布说明"}, pat.Token).AssertOK(t)
+
+	// 其他权限范围和仅限登录会话的接口均被拒绝
+	s.Do(t, http.MethodPost, fmt.Sprintf("/api/v1/articles/%d/comments", a.ID), map[string]string{"content": "评论"}, pat.Token).
+		AssertError(t, http.StatusForbidden, "FORBIDDEN")
+	s.Do(t, http.MethodGet, "/api/v1/users/me", nil, pat.Token).AssertError(t, http.StatusForbidden, "FORBIDDEN")
+	s.Do(t, http.MethodGet, "/api/v1/users/me/tokens", nil, pat.Token).AssertError(t, http.StatusForbidden, "FORBIDDEN")
+	s.Do(t, http.MethodPut, "/api/v1/users/me/password", map[string]string{"current_password": "secret123", "new_password": "newsecret456"}, pat.Token).
+		AssertError(t, http.StatusForbidden, "FORBIDDEN")
+
+	profile := createPersonalToken(t, s, token, map[string]interface{}{
+		"name":   "profile",
+		"scopes": []string{"profile:read", "comments:write"},
+	})
+	s.Do(t, http.MethodGet, "/api/v1/users/me", nil, profile.Token).AssertOK(t)
+	createComment(t, s, profile.Token, a.ID, "评论")
+	s.Do(t, http.MethodPost, "/api/v1/articles", map[string]string{"title": "标题", "content": "正文"}, profile.Token).
+		AssertError(t, http.StatusForbidden, "FORBIDDEN")
+}
+
+// 缺少 articles:write 的令牌访问公开的只读接口时按匿名访问处理
+func TestPersonalTokenPublicReads(t *testing.T) {
+	s := testutil.NewServer(t)
+	token, _ := s.Register(t, "alice", "alice@example.com", "secret123")
+	a := createArticle(t, s, token, "公开文章")
+	draft := createDraft(t, s, token, "草稿")
+	series := createSeries(t, s, token, "系列", a.ID)
+	createComment(t, s, token, a.ID, "评论")
+	pat := createPersonalToken(t, s, token, map[string]interface{}{"name": "reader", "scopes": []string{"profile:read"}})
+
+	for _, path := range []string{
+		"/api/v1/articles",
+		fmt.Sprintf("/api/v1/articles/%d", a.ID),
+		"/api/v1/articles/slug/" + a.Slug,
+		fmt.Sprintf("/api/v1/series/%d", series.ID),
+		fmt.Sprintf("/api/v1/articles/%d/comments", a.ID),
+	} {
+		s.Do(t, http.MethodGet, path, nil, pat.Token).AssertOK(t)
+	}
+
+	// 匿名访问看不到草稿
+	s.Do(t, http.MethodGet, fmt.Sprintf("/api/v1/articles/%d", draft.ID), nil, pat.Token).AssertError(t, http.StatusNotFound, "NOT_FOUND")
+	if ids := listArticles(t, s, "/api/v1/articles", pat.Token); len(ids) != 1 || ids[0] != a.ID {
+		t.Fatalf("期望只列出已发布文章，实际 %v", ids)
+	}
+	s.Do(t, http.MethodGet, "/api/v1/articles", nil, "invalid").AssertError(t, http.StatusUnauthorized, "UNAUTHORIZED")
+}
+
+func TestPersonalTokenLifecycle(t *testing.T) {
+	s := testutil.NewServer(t)
+	token, _ := s.Register(t, "alice", "alice@example.com", "secret123")
+	bobToken, _ := s.Register(t, "bob", "bob@example.com", "secret123")
+
+	create := func(body map[string]interface{}) *testutil.Response {
+		return s.Do(t, http.MethodPost, "/api/v1/users/me/tokens", body, token)
+	}
+	create(map[string]interface{}{"name": "ci", "scopes": []string{"admin"}}).AssertError(t, http.StatusBadRequest, "INVALID_INPUT")
+	create(map[string]interface{}{"name": "ci", "scopes": []string{}}).AssertError(t, http.StatusBadRequest, "INVALID_INPUT")
+	create(map[string]interface{}{"name": "ci", "scopes": []string{"profile:read"}, "expires_at": time.Now().Add(-time.Hour)}).
+		AssertError(t, http.StatusBadRequest, "INVALID_INPUT")
+
+	pat := createPersonalToken(t, s, token, map[string]interface{}{
+		"name":       "ci",
+		"scopes":     []string{"profile:read"},
+		"expires_at": time.Now().Add(24 * time.Hour),
+	})
+	s.Do(t, http.MethodGet, "/api/v1/users/me", nil, pat.Token).AssertOK(t)
+
+	// 列表中不包含令牌明文
+	resp := s.Do(t, http.MethodGet, "/api/v1/users/me/tokens", nil, token)
+	resp.AssertOK(t)
+	var list []personalToken
+	resp.DecodeData(t, &list)
+	if len(list) != 1 || list[0].ID != pat.ID || list[0].Token != "" {
+		t.Fatalf("令牌列表不符: %s", resp.Body)
+	}
+
+	// 过期后失效
+	if err := s.DB.Model(&models.PersonalToken{}).Where("id = ?", pat.ID).Update("expires_at", time.Now().Add(-time.Second)).Error; err != nil {
+		t.Fatal(err)
+	}
+	s.Do(t, http.MethodGet, "/api/v1/users/me", nil, pat.Token).AssertError(t, http.StatusUnauthorized, "UNAUTHORIZED")
+
+	// 撤销后失效，不能撤销其他用户的令牌
+	other := createPersonalToken(t, s, token, map[string]interface{}{"name": "other", "scopes": []string{"profile:read"}})
+	path := fmt.Sprintf("/api/v1/users/me/tokens/%d", other.ID)
+	s.Do(t, http.MethodDelete, path, nil, bobToken).AssertError(t, http.StatusNotFound, "NOT_FOUND")
+	s.Do(t, http.MethodGet, "/api/v1/users/me", nil, other.Token).AssertOK(t)
+	s.Do(t, http.MethodDelete, path, nil, token).AssertOK(t)
+	s.Do(t, http.MethodGet, "/api/v1/users/me", nil, other.Token).AssertError(t, http.StatusUnauthorized, "UNAUTHORIZED")
+	s.Do(t, http.MethodGet, "/api/v1/users/me", nil, "blogpat_invalid").AssertError(t, http.StatusUnauthorized, "UNAUTHORIZED")
+}
+
+func TestPersonalTokenFollowsRole(t *testing.T) {
+	s := testutil.NewServer(t)
+	token, aliceID := s.Register(t, "alice", "alice@example.com", "secret123")
+	pat := createPersonalToken(t, s, token, map[string]interface{}{"name": "ci", "scopes": []string{"articles:write"}})
+
+	// 角色按请求时读取，降级后令牌随之失去发布权限
+	if err := s.DB.Model(&models.User{}).Where("id = ?", aliceID).Update("role", models.RoleReader).Error; err != nil {
+		t.Fatal(err)
+	}
+	s.Do(t, http.MethodPost, "/api/v1/articles", map[string]string{"title": "标题", "content": "正文"}, pat.Token).
+		AssertError(t, http.StatusForbidden, "FORBIDDEN")
+}
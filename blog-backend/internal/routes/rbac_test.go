@@ -0,0 +1,129 @@
+package routes_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"blog-backend/internal/models"
+	"blog-backend/internal/testutil"
+)
+
+type auditLog struct {
+	ActorID    uint   `json:"actor_id"`
+	ActorRole  string `json:"actor_role"`
+	Action     string `json:"action"`
+	TargetType string `json:"target_type"`
+	TargetID   uint   `json:"target_id"`
+	OwnerID    uint   `json:"owner_id"`
+}
+
+func listAuditLogs(t *testing.T, s *testutil.Server, token string) []auditLog {
+	t.Helper()
+	resp := s.Do(t, http.MethodGet, "/api/v1/admin/audit-logs", nil, token)
+	resp.AssertOK(t)
+
+	var data struct {
+		Logs []auditLog `json:"logs"`
+	}
+	resp.DecodeData(t, &data)
+	return data.Logs
+}
+
+func TestEditorCanEditOthersArticles(t *testing.T) {
+	s := testutil.NewServer(t)
+	aliceToken, aliceID := s.Register(t, "alice", "alice@example.com", "secret123")
+	editorToken, editorID := s.RegisterWithRole(t, "ed", "ed@example.com", "secret123", models.RoleEditor)
+	adminToken, _ := s.RegisterWithRole(t, "root", "root@example.com", "secret123", models.RoleAdmin)
+
+	a := createArticle(t, s, aliceToken, "原标题")
+	path := fmt.Sprintf("/api/v1/articles/%d", a.ID)
+
+	s.Do(t, http.MethodPut, path, map[string]string{"title": "编辑修改"}, editorToken).AssertOK(t)
+	s.Do(t, http.MethodDelete, path, nil, editorToken).AssertError(t, http.StatusForbidden, "FORBIDDEN")
+
+	// 作者本人的操作不记录审计日志
+	s.Do(t, http.MethodPut, path, map[string]string{"content": "作者修改"}, aliceToken).AssertOK(t)
+
+	logs := listAuditLogs(t, s, adminToken)
+	if len(logs) != 1 {
+		t.Fatalf("期望 1 条审计日志，实际 %d", len(logs))
+	}
+	want := auditLog{ActorID: editorID, ActorRole: models.RoleEditor, Action: models.AuditArticleUpdate, TargetType: "article", TargetID: a.ID, OwnerID: aliceID}
+	if logs[0] != want {
+		t.Fatalf("审计日志不符: %+v", logs[0])
+	}
+}
+
+func TestAdminModeration(t *testing.T) {
+	s := testutil.NewServer(t)
+	aliceToken, _ := s.Register(t, "alice", "alice@example.com", "secret123")
+	bobToken, bobID := s.Register(t, "bob", "bob@example.com", "secret123")
+	adminToken, adminID := s.RegisterWithRole(t, "root", "root@example.com", "secret123", models.RoleAdmin)
+
+	a := createArticle(t, s, aliceToken, "文章")
+	c := createComment(t, s, bobToken, a.ID, "垃圾评论")
+
+	s.Do(t, http.MethodDelete, fmt.Sprintf("/api/v1/comments/%d", c.ID), nil, adminToken).AssertOK(t)
+	s.Do(t, http.MethodDelete, fmt.Sprintf("/api/v1/articles/%d", a.ID), nil, adminToken).AssertOK(t)
+	s.Do(t, http.MethodGet, fmt.Sprintf("/api/v1/articles/%d", a.ID), nil, "").AssertError(t, http.StatusNotFound, "NOT_FOUND")
+
+	logs := listAuditLogs(t, s, adminToken)
+	if len(logs) != 2 {
+		t.Fatalf("期望 2 条审计日志，实际 %d", len(logs))
+	}
+	if logs[0].Action != models.AuditArticleDelete || logs[1].Action != models.AuditCommentDelete {
+		t.Fatalf("审计日志顺序不符: %+v", logs)
+	}
+	if logs[1].ActorID != adminID || logs[1].OwnerID != bobID {
+		t.Fatalf("评论审计日志不符: %+v", logs[1])
+	}
+
+	for _, token := range []string{aliceToken, bobToken} {
+		s.Do(t, http.MethodGet, "/api/v1/admin/audit-logs", nil, token).AssertError(t, http.StatusForbidden, "FORBIDDEN")
+		s.Do(t, http.MethodGet, "/api/v1/admin/users", nil, token).AssertError(t, http.StatusForbidden, "FORBIDDEN")
+	}
+	s.Do(t, http.MethodGet, "/api/v1/admin/users", nil, "").AssertError(t, http.StatusUnauthorized, "UNAUTHORIZED")
+}
+
+func TestReaderCannotPublish(t *testing.T) {
+	s := testutil.NewServer(t)
+	aliceToken, _ := s.Register(t, "alice", "alice@example.com", "secret123")
+	readerToken, _ := s.RegisterWithRole(t, "reader", "reader@example.com", "secret123", models.RoleReader)
+
+	a := createArticle(t, s, aliceToken, "文章")
+	s.Do(t, http.MethodPost, "/api/v1/articles", map[string]string{"title": "t", "content": "c"}, readerToken).AssertError(t, http.StatusForbidden, "FORBIDDEN")
+	createComment(t, s, readerToken, a.ID, "读者评论")
+}
+
+func TestAdminChangeRole(t *testing.T) {
+	s := testutil.NewServer(t)
+	aliceToken, aliceID := s.Register(t, "alice", "alice@example.com", "secret123")
+	adminToken, adminID := s.RegisterWithRole(t, "root", "root@example.com", "secret123", models.RoleAdmin)
+
+	path := fmt.Sprintf("/api/v1/admin/users/%d/role", aliceID)
+	s.Do(t, http.MethodPut, path, map[string]string{"role": "owner"}, adminToken).AssertError(t, http.StatusBadRequest, "INVALID_INPUT")
+	s.Do(t, http.MethodPut, path, map[string]string{"role": models.RoleEditor}, aliceToken).AssertError(t, http.StatusForbidden, "FORBIDDEN")
+	s.Do(t, http.MethodPut, fmt.Sprintf("/api/v1/admin/users/%d/role", adminID), map[string]string{"role": models.RoleReader}, adminToken).AssertError(t, http.StatusBadRequest, "INVALID_INPUT")
+	s.Do(t, http.MethodPut, "/api/v1/admin/users/999/role", map[string]string{"role": models.RoleEditor}, adminToken).AssertError(t, http.StatusNotFound, "NOT_FOUND")
+
+	resp := s.Do(t, http.MethodPut, path, map[string]string{"role": models.RoleReader}, adminToken)
+	resp.AssertOK(t)
+	var user struct {
+		Role string `json:"role"`
+	}
+	resp.DecodeData(t, &user)
+	if user.Role != models.RoleReader {
+		t.Fatalf("期望角色 reader，实际 %s", user.Role)
+	}
+
+	// 角色变更后旧令牌失效，重新登录后按新角色授权
+	s.Do(t, http.MethodGet, "/api/v1/users/me", nil, aliceToken).AssertError(t, http.StatusUnauthorized, "UNAUTHORIZED")
+	aliceToken, _ = s.Login(t, "alice@example.com", "secret123")
+	s.Do(t, http.MethodPost, "/api/v1/articles", map[string]string{"title": "t", "content": "c"}, aliceToken).AssertError(t, http.StatusForbidden, "FORBIDDEN")
+
+	logs := listAuditLogs(t, s, adminToken)
+	if len(logs) != 1 || logs[0].Action != models.AuditUserRole || logs[0].TargetID != aliceID {
+		t.Fatalf("审计日志不符: %+v", logs)
+	}
+}
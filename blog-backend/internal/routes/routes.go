@@ -2,53 +2,206 @@ package routes
 
 import (
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 
+	"blog-backend/config"
+	"blog-backend/internal/auth"
 	"blog-backend/internal/controllers"
+	"blog-backend/internal/mail"
 	"blog-backend/internal/middleware"
+	"blog-backend/internal/models"
+	"blog-backend/internal/oidc"
+	"blog-backend/internal/repository"
+	"blog-backend/internal/services"
 )
 
-func SetupRoutes(r *gin.Engine) {
+func SetupRoutes(r *gin.Engine, db *gorm.DB, cfg *config.Config) error {
+	tokenManager, err := auth.NewTokenManager(cfg.JWT)
+	if err != nil {
+		return err
+	}
+
+	userRepo := repository.NewUserRepository(db)
+	articleRepo := repository.NewArticleRepository(db)
+	commentRepo := repository.NewCommentRepository(db)
+	sessionRepo := repository.NewSessionRepository(db)
+	auditRepo := repository.NewAuditLogRepository(db)
+	userTokenRepo := repository.NewUserTokenRepository(db)
+	recoveryCodeRepo := repository.NewRecoveryCodeRepository(db)
+	loginThrottleRepo := repository.NewLoginThrottleRepository(db)
+	personalTokenRepo := repository.NewPersonalTokenRepository(db)
+	identityRepo := repository.NewIdentityRepository(db)
+	tagRepo := repository.NewTagRepository(db)
+	categoryRepo := repository.NewCategoryRepository(db)
+	seriesRepo := repository.NewSeriesRepository(db)
+
+	sessionService := services.NewSessionService(sessionRepo, userRepo, tokenManager, cfg.JWT.RefreshExpire)
+	personalTokenService := services.NewPersonalTokenService(personalTokenRepo, userRepo)
+	authenticator := services.NewAuthenticator(sessionService, personalTokenService)
+	// 个人访问令牌只能访问带有权限范围的路由
+	authRequired := middleware.AuthMiddleware(authenticator)
+	profileAuth := middleware.AuthMiddleware(authenticator, auth.ScopeProfileRead)
+	articlesAuth := middleware.AuthMiddleware(authenticator, auth.ScopeArticlesWrite)
+	commentsAuth := middleware.AuthMiddleware(authenticator, auth.ScopeCommentsWrite)
+	// 公开的文章接口，登录后可以看到自己的草稿，缺少权限范围的个人访问令牌按匿名访问处理
+	articlesViewer := middleware.OptionalAuthMiddleware(authenticator, auth.ScopeArticlesWrite)
+	canWriteArticles := middleware.RequirePermission(auth.PermCreateArticle)
+	canComment := middleware.RequirePermission(auth.PermCreateComment)
+	canManageTaxonomy := middleware.RequirePermission(auth.PermManageTaxonomy)
+
+	passwordPolicy, err := services.NewPasswordPolicy(cfg.Account)
+	if err != nil {
+		return err
+	}
+
+	signer := auth.NewSigner(cfg.JWT.Secret)
+	loginThrottleService := services.NewLoginThrottleService(loginThrottleRepo, cfg.Account)
+	accountService := services.NewAccountService(userRepo, userTokenRepo, sessionService, personalTokenService, loginThrottleService, mail.New(cfg.Mail), signer, passwordPolicy, cfg.Account)
+	twoFactorService := services.NewTwoFactorService(userRepo, recoveryCodeRepo, sessionService, loginThrottleService, signer, cfg.Account.TOTPIssuer)
+
+	authController := controllers.NewAuthController(services.NewAuthService(userRepo, sessionService, accountService, twoFactorService, loginThrottleService, passwordPolicy), sessionService, accountService, twoFactorService)
+	userController := controllers.NewUserController(services.NewUserService(userRepo), accountService, services.NewExportService(userRepo, articleRepo, seriesRepo, commentRepo))
+	sessionController := controllers.NewSessionController(sessionService)
+	twoFactorController := controllers.NewTwoFactorController(twoFactorService)
+	personalTokenController := controllers.NewPersonalTokenController(personalTokenService)
+	oidcController := controllers.NewOIDCController(services.NewOIDCService(oidc.New(cfg.OIDC), userRepo, identityRepo, sessionService, twoFactorService))
+	tagService := services.NewTagService(tagRepo)
+	categoryService := services.NewCategoryService(categoryRepo, articleRepo)
+	seriesService := services.NewSeriesService(seriesRepo, articleRepo, userRepo, auditRepo)
+	articleController := controllers.NewArticleController(services.NewArticleService(articleRepo, tagService, categoryService, userRepo, auditRepo), seriesService)
+	seriesController := controllers.NewSeriesController(seriesService)
+	tagController := controllers.NewTagController(tagService)
+	categoryController := controllers.NewCategoryController(categoryService)
+	commentController := controllers.NewCommentController(services.NewCommentService(commentRepo, articleRepo, userRepo, auditRepo))
+	adminController := controllers.NewAdminController(services.NewAdminService(userRepo, auditRepo, sessionService, loginThrottleService))
+	keyController := controllers.NewKeyController(tokenManager)
+
+	// 访问令牌公钥 (JWKS)
+	r.GET("/.well-known/jwks.json", keyController.GetJWKS)
+
 	// API v1 路由组
 	v1 := r.Group("/api/v1")
 	{
 		// 认证相关接口
 		auth := v1.Group("/auth")
 		{
-			auth.POST("/register", controllers.Register)
-			auth.POST("/login", controllers.Login)
+			auth.POST("/register", authController.Register)
+			auth.POST("/login", authController.Login)
+			auth.POST("/login/2fa", authController.LoginTwoFactor)
+			auth.POST("/refresh", authController.Refresh)
+			auth.POST("/logout", authRequired, authController.Logout)
+			auth.POST("/forgot-password", authController.ForgotPassword)
+			auth.POST("/reset-password", authController.ResetPassword)
+			auth.POST("/verify-email", authController.VerifyEmail)
+			auth.POST("/resend-verification", authRequired, authController.ResendVerification)
+			auth.POST("/confirm-email", authController.ConfirmEmailChange)
+
+			// 配置了身份提供方时启用 OIDC 登录
+			if cfg.OIDC.Enabled() {
+				auth.GET("/oidc/authorize", oidcController.Authorize)
+				auth.POST("/oidc/callback", oidcController.Callback)
+			}
 		}
 
 		// 用户相关接口
 		users := v1.Group("/users")
-		users.Use(middleware.AuthMiddleware())
 		{
-			users.GET("/me", controllers.GetCurrentUser)
-			users.PUT("/me", controllers.UpdateCurrentUser)
+			users.GET("/me", profileAuth, userController.GetCurrentUser)
+			users.GET("/me/drafts", articlesAuth, articleController.GetDrafts)
+
+			// 以下接口只接受登录会话签发的令牌
+			users.Use(authRequired)
+			{
+				users.PUT("/me", userController.UpdateCurrentUser)
+				users.DELETE("/me", userController.DeleteCurrentUser)
+				users.GET("/me/export", userController.ExportData)
+				users.PUT("/me/password", userController.ChangePassword)
+				users.POST("/me/email", userController.ChangeEmail)
+				users.GET("/me/sessions", sessionController.GetSessions)
+				users.DELETE("/me/sessions", sessionController.DeleteAllSessions)
+				users.DELETE("/me/sessions/:id", sessionController.DeleteSession)
+				users.POST("/me/2fa/enroll", twoFactorController.Enroll)
+				users.POST("/me/2fa/confirm", twoFactorController.Confirm)
+				users.POST("/me/2fa/disable", twoFactorController.Disable)
+				users.POST("/me/2fa/recovery-codes", twoFactorController.RegenerateRecoveryCodes)
+				users.GET("/me/tokens", personalTokenController.GetTokens)
+				users.POST("/me/tokens", personalTokenController.CreateToken)
+				users.DELETE("/me/tokens/:id", personalTokenController.DeleteToken)
+			}
 		}
 
 		// 文章相关接口
 		articles := v1.Group("/articles")
 		{
-			articles.GET("", controllers.GetArticles)
-			articles.GET("/:id", controllers.GetArticle)
-			
+			articles.GET("", articlesViewer, articleController.GetArticles)
+			articles.GET("/:id", articlesViewer, articleController.GetArticle)
+			articles.GET("/slug/:slug", articlesViewer, articleController.GetArticleBySlug)
+
 			// 需要认证的接口
-			articles.Use(middleware.AuthMiddleware())
+			articles.Use(articlesAuth)
+			{
+				articles.POST("", canWriteArticles, articleController.CreateArticle)
+				articles.PUT("/:id", articleController.UpdateArticle)
+				articles.DELETE("/:id", articleController.DeleteArticle)
+				articles.POST("/:id/publish", articleController.PublishArticle)
+				articles.POST("/:id/unpublish", articleController.UnpublishArticle)
+				articles.POST("/:id/archive", articleController.ArchiveArticle)
+			}
+		}
+
+		// 系列文章接口，所有权规则与文章相同
+		series := v1.Group("/series")
+		{
+			series.GET("", seriesController.GetSeriesList)
+			series.GET("/:id", articlesViewer, seriesController.GetSeries)
+
+			series.Use(articlesAuth)
 			{
-				articles.POST("", controllers.CreateArticle)
-				articles.PUT("/:id", controllers.UpdateArticle)
-				articles.DELETE("/:id", controllers.DeleteArticle)
+				series.POST("", canWriteArticles, seriesController.CreateSeries)
+				series.PUT("/:id", seriesController.UpdateSeries)
+				series.PUT("/:id/articles", seriesController.SetSeriesArticles)
+				series.DELETE("/:id", seriesController.DeleteSeries)
 			}
 		}
 
 		// 评论相关接口
 		comments := v1.Group("/articles/:id/comments")
 		{
-			comments.GET("", controllers.GetComments)
-			comments.POST("", middleware.AuthMiddleware(), controllers.CreateComment)
+			comments.GET("", articlesViewer, commentController.GetComments)
+			comments.POST("", commentsAuth, canComment, commentController.CreateComment)
 		}
-		
+
 		// 删除评论接口
-		v1.DELETE("/comments/:id", middleware.AuthMiddleware(), controllers.DeleteComment)
+		v1.DELETE("/comments/:id", commentsAuth, commentController.DeleteComment)
+
+		// 标签接口，编辑和管理员可以管理标签
+		tags := v1.Group("/tags")
+		{
+			tags.GET("", tagController.GetTags)
+			tags.POST("", articlesAuth, canManageTaxonomy, tagController.CreateTag)
+			tags.PUT("/:id", articlesAuth, canManageTaxonomy, tagController.UpdateTag)
+			tags.DELETE("/:id", articlesAuth, canManageTaxonomy, tagController.DeleteTag)
+		}
+
+		// 分类接口，编辑和管理员可以管理分类
+		categories := v1.Group("/categories")
+		{
+			categories.GET("", categoryController.GetCategories)
+			categories.POST("", articlesAuth, canManageTaxonomy, categoryController.CreateCategory)
+			categories.PUT("/:id", articlesAuth, canManageTaxonomy, categoryController.UpdateCategory)
+			categories.DELETE("/:id", articlesAuth, canManageTaxonomy, categoryController.DeleteCategory)
+		}
+
+		// 管理接口
+		admin := v1.Group("/admin")
+		admin.Use(authRequired, middleware.RequireRole(models.RoleAdmin))
+		{
+			admin.GET("/users", adminController.GetUsers)
+			admin.PUT("/users/:id/role", adminController.UpdateUserRole)
+			admin.POST("/users/:id/unlock", adminController.UnlockUser)
+			admin.GET("/audit-logs", adminController.GetAuditLogs)
+		}
 	}
-}
\ No newline at end of file
+
+	return nil
+}
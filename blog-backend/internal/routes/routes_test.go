@@ -0,0 +1,222 @@
+package routes_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"blog-backend/internal/testutil"
+)
+
+type article struct {
+	ID       uint   `json:"id"`
+	Title    string `json:"title"`
+	Content  string `json:"content"`
+	AuthorID uint   `json:"author_id"`
+	Views    int    `json:"views"`
+	Author   struct {
+		ID       uint   `json:"id"`
+		Username string `json:"username"`
+	} `json:"author"`
+	Status      string     `json:"status"`
+	PublishedAt *time.Time `json:"published_at"`
+	PublishAt   *time.Time `json:"publish_at"`
+	Slug        string     `json:"slug"`
+	Tags        []struct {
+		Name string `json:"name"`
+		Slug string `json:"slug"`
+	} `json:"tags"`
+	CategoryID *uint `json:"category_id"`
+}
+
+type comment struct {
+	ID        uint   `json:"id"`
+	Content   string `json:"content"`
+	ArticleID uint   `json:"article_id"`
+	AuthorID  uint   `json:"author_id"`
+}
+
+type pagination struct {
+	Page       int   `json:"page"`
+	Limit      int   `json:"limit"`
+	Total      int64 `json:"total"`
+	TotalPages int   `json:"total_pages"`
+}
+
+func createArticle(t *testing.T, s *testutil.Server, token, title string) article {
+	t.Helper()
+	resp := s.Do(t, http.MethodPost, "/api/v1/articles", map[string]string{"title": title, "content": "正文"}, token)
+	resp.AssertOK(t)
+
+	var a article
+	resp.DecodeData(t, &a)
+	return a
+}
+
+func createComment(t *testing.T, s *testutil.Server, token string, articleID uint, content string) comment {
+	t.Helper()
+	resp := s.Do(t, http.MethodPost, fmt.Sprintf("/api/v1/articles/%d/comments", articleID), map[string]string{"content": content}, token)
+	resp.AssertOK(t)
+
+	var c comment
+	resp.DecodeData(t, &c)
+	return c
+}
+
+func TestArticleLifecycle(t *testing.T) {
+	s := testutil.NewServer(t)
+	token, userID := s.Register(t, "alice", "alice@example.com", "secret123")
+
+	created := createArticle(t, s, token, "第一篇文章")
+	if created.AuthorID != userID {
+		t.Fatalf("author_id = %d, want %d", created.AuthorID, userID)
+	}
+
+	// 详情接口返回作者信息并增加浏览量
+	resp := s.Do(t, http.MethodGet, fmt.Sprintf("/api/v1/articles/%d", created.ID), nil, "")
+	resp.AssertOK(t)
+	var got article
+	resp.DecodeData(t, &got)
+	if got.Views != 1 || got.Author.Username != "alice" {
+		t.Fatalf("unexpected article: %+v", got)
+	}
+
+	resp = s.Do(t, http.MethodPut, fmt.Sprintf("/api/v1/articles/%d", created.ID), map[string]string{"title": "新标题"}, token)
+	resp.AssertOK(t)
+	resp.DecodeData(t, &got)
+	if got.Title != "新标题" || got.Content != "正文" {
+		t.Fatalf("unexpected article after update: %+v", got)
+	}
+
+	c := createComment(t, s, token, created.ID, "沙发")
+	if c.ArticleID != created.ID || c.AuthorID != userID {
+		t.Fatalf("unexpected comment: %+v", c)
+	}
+
+	resp = s.Do(t, http.MethodGet, fmt.Sprintf("/api/v1/articles/%d/comments", created.ID), nil, "")
+	resp.AssertOK(t)
+	var comments struct {
+		Comments   []comment  `json:"comments"`
+		Pagination pagination `json:"pagination"`
+	}
+	resp.DecodeData(t, &comments)
+	if len(comments.Comments) != 1 || comments.Pagination.Total != 1 {
+		t.Fatalf("unexpected comments: %+v", comments)
+	}
+
+	s.Do(t, http.MethodDelete, fmt.Sprintf("/api/v1/comments/%d", c.ID), nil, token).AssertOK(t)
+	s.Do(t, http.MethodDelete, fmt.Sprintf("/api/v1/comments/%d", c.ID), nil, token).AssertError(t, http.StatusNotFound, "NOT_FOUND")
+
+	s.Do(t, http.MethodDelete, fmt.Sprintf("/api/v1/articles/%d", created.ID), nil, token).AssertOK(t)
+	s.Do(t, http.MethodGet, fmt.Sprintf("/api/v1/articles/%d", created.ID), nil, "").AssertError(t, http.StatusNotFound, "NOT_FOUND")
+}
+
+func TestRegisterAndLogin(t *testing.T) {
+	s := testutil.NewServer(t)
+	s.Register(t, "alice", "alice@example.com", "secret123")
+
+	// 重复注册
+	s.Do(t, http.MethodPost, "/api/v1/auth/register", map[string]string{
+		"username": "alice", "email": "other@example.com", "password": "secret123",
+	}, "").AssertError(t, http.StatusConflict, "INVALID_INPUT")
+
+	// 参数校验
+	s.Do(t, http.MethodPost, "/api/v1/auth/register", map[string]string{
+		"username": "bob", "email": "not-an-email", "password": "secret123",
+	}, "").AssertError(t, http.StatusBadRequest, "INVALID_INPUT")
+	s.Do(t, http.MethodPost, "/api/v1/auth/register", map[string]string{
+		"username": "bob", "email": "bob@example.com", "password": "123",
+	}, "").AssertError(t, http.StatusBadRequest, "INVALID_INPUT")
+
+	// 错误密码和不存在的用户返回相同的错误
+	s.Do(t, http.MethodPost, "/api/v1/auth/login", map[string]string{
+		"email": "alice@example.com", "password": "wrong",
+	}, "").AssertError(t, http.StatusUnauthorized, "UNAUTHORIZED")
+	s.Do(t, http.MethodPost, "/api/v1/auth/login", map[string]string{
+		"email": "nobody@example.com", "password": "secret123",
+	}, "").AssertError(t, http.StatusUnauthorized, "UNAUTHORIZED")
+}
+
+func TestAuthMiddleware(t *testing.T) {
+	s := testutil.NewServer(t)
+	token, _ := s.Register(t, "alice", "alice@example.com", "secret123")
+
+	s.Do(t, http.MethodGet, "/api/v1/users/me", nil, "").AssertError(t, http.StatusUnauthorized, "UNAUTHORIZED")
+	s.Do(t, http.MethodGet, "/api/v1/users/me", nil, "not-a-jwt").AssertError(t, http.StatusUnauthorized, "UNAUTHORIZED")
+	s.Do(t, http.MethodPost, "/api/v1/articles", map[string]string{"title": "t", "content": "c"}, "").AssertError(t, http.StatusUnauthorized, "UNAUTHORIZED")
+
+	// 其他密钥签发的 token 无效
+	other := testutil.Config()
+	other.JWT.Secret = "another_secret"
+	otherServer := testutil.NewServerWithConfig(t, other)
+	forged, _ := otherServer.Register(t, "alice", "alice@example.com", "secret123")
+	s.Do(t, http.MethodGet, "/api/v1/users/me", nil, forged).AssertError(t, http.StatusUnauthorized, "UNAUTHORIZED")
+
+	resp := s.Do(t, http.MethodGet, "/api/v1/users/me", nil, token)
+	resp.AssertOK(t)
+	var me map[string]interface{}
+	resp.DecodeData(t, &me)
+	if me["username"] != "alice" {
+		t.Fatalf("unexpected user: %v", me)
+	}
+	if _, ok := me["password"]; ok {
+		t.Fatal("响应中不应包含密码")
+	}
+
+	resp = s.Do(t, http.MethodPut, "/api/v1/users/me", map[string]string{"avatar": "https://example.com/a.png"}, token)
+	resp.AssertOK(t)
+	resp.DecodeData(t, &me)
+	if me["avatar"] != "https://example.com/a.png" || me["username"] != "alice" {
+		t.Fatalf("unexpected user after update: %v", me)
+	}
+}
+
+func TestOwnershipChecks(t *testing.T) {
+	s := testutil.NewServer(t)
+	aliceToken, _ := s.Register(t, "alice", "alice@example.com", "secret123")
+	bobToken, _ := s.Register(t, "bob", "bob@example.com", "secret123")
+	carolToken, _ := s.Register(t, "carol", "carol@example.com", "secret123")
+
+	a := createArticle(t, s, aliceToken, "alice 的文章")
+	path := fmt.Sprintf("/api/v1/articles/%d", a.ID)
+
+	// 非作者不能修改或删除文章
+	s.Do(t, http.MethodPut, path, map[string]string{"title": "篡改"}, bobToken).AssertError(t, http.StatusForbidden, "FORBIDDEN")
+	s.Do(t, http.MethodDelete, path, nil, bobToken).AssertError(t, http.StatusForbidden, "FORBIDDEN")
+
+	// 评论可以由评论作者或文章作者删除，其他人不能删除
+	bobComment := createComment(t, s, bobToken, a.ID, "bob 的评论")
+	s.Do(t, http.MethodDelete, fmt.Sprintf("/api/v1/comments/%d", bobComment.ID), nil, carolToken).AssertError(t, http.StatusForbidden, "FORBIDDEN")
+	s.Do(t, http.MethodDelete, fmt.Sprintf("/api/v1/comments/%d", bobComment.ID), nil, aliceToken).AssertOK(t)
+
+	carolComment := createComment(t, s, carolToken, a.ID, "carol 的评论")
+	s.Do(t, http.MethodDelete, fmt.Sprintf("/api/v1/comments/%d", carolComment.ID), nil, carolToken).AssertOK(t)
+
+	// 不存在的文章
+	s.Do(t, http.MethodPost, "/api/v1/articles/999/comments", map[string]string{"content": "x"}, bobToken).AssertError(t, http.StatusNotFound, "NOT_FOUND")
+	s.Do(t, http.MethodPut, "/api/v1/articles/999", map[string]string{"title": "x"}, aliceToken).AssertError(t, http.StatusNotFound, "NOT_FOUND")
+	s.Do(t, http.MethodGet, "/api/v1/articles/abc", nil, "").AssertError(t, http.StatusBadRequest, "INVALID_INPUT")
+
+	s.Do(t, http.MethodPut, path, map[string]string{"title": "修改"}, aliceToken).AssertOK(t)
+	s.Do(t, http.MethodDelete, path, nil, aliceToken).AssertOK(t)
+}
+
+func TestArticlePagination(t *testing.T) {
+	s := testutil.NewServer(t)
+	token, _ := s.Register(t, "alice", "alice@example.com", "secret123")
+	for i := 0; i < 3; i++ {
+		createArticle(t, s, token, fmt.Sprintf("文章 %d", i))
+	}
+
+	resp := s.Do(t, http.MethodGet, "/api/v1/articles?page=2&limit=2", nil, "")
+	resp.AssertOK(t)
+	var data struct {
+		Articles   []article  `json:"articles"`
+		Pagination pagination `json:"pagination"`
+	}
+	resp.DecodeData(t, &data)
+	if len(data.Articles) != 1 || data.Pagination != (pagination{Page: 2, Limit: 2, Total: 3, TotalPages: 2}) {
+		t.Fatalf("unexpected page: %+v", data)
+	}
+}
@@ -0,0 +1,152 @@
+package routes_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"blog-backend/internal/models"
+	"blog-backend/internal/repository"
+	"blog-backend/internal/services"
+	"blog-backend/internal/testutil"
+)
+
+func scheduleArticle(t *testing.T, s *testutil.Server, token, title string, publishAt time.Time) article {
+	t.Helper()
+	resp := s.Do(t, http.MethodPost, "/api/v1/articles", map[string]interface{}{"title": title, "content": "正文", "publish_at": publishAt}, token)
+	resp.AssertOK(t)
+
+	var a article
+	resp.DecodeData(t, &a)
+	return a
+}
+
+func publishDue(t *testing.T, s *testutil.Server, now time.Time) int {
+	t.Helper()
+	n, err := services.NewScheduledPublisher(repository.NewArticleRepository(s.DB)).PublishDue(context.Background(), now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestScheduledPublishing(t *testing.T) {
+	s := testutil.NewServer(t)
+	token, _ := s.Register(t, "alice", "alice@example.com", "secret123")
+
+	publishAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	s.Do(t, http.MethodPost, "/api/v1/articles", map[string]interface{}{"title": "x", "content": "x", "publish_at": time.Now().Add(-time.Minute)}, token).
+		AssertError(t, http.StatusBadRequest, "INVALID_INPUT")
+	s.Do(t, http.MethodPost, "/api/v1/articles", map[string]interface{}{"title": "x", "content": "x", "status": "published", "publish_at": publishAt}, token).
+		AssertError(t, http.StatusBadRequest, "INVALID_INPUT")
+
+	scheduled := scheduleArticle(t, s, token, "发布公告", publishAt)
+	if scheduled.Status != models.ArticleDraft || scheduled.PublishAt == nil || !scheduled.PublishAt.Equal(publishAt) {
+		t.Fatalf("定时发布的文章应保存为草稿: %+v", scheduled)
+	}
+	s.Do(t, http.MethodGet, fmt.Sprintf("/api/v1/articles/%d", scheduled.ID), nil, "").AssertError(t, http.StatusNotFound, "NOT_FOUND")
+
+	// 已发布的文章不能定时发布
+	published := createArticle(t, s, token, "已发布")
+	s.Do(t, http.MethodPut, fmt.Sprintf("/api/v1/articles/%d", published.ID), map[string]interface{}{"publish_at": publishAt}, token).
+		AssertError(t, http.StatusBadRequest, "INVALID_INPUT")
+
+	// 未到期时不发布
+	if n := publishDue(t, s, time.Now()); n != 0 {
+		t.Fatalf("未到期的文章不应发布，实际发布 %d 篇", n)
+	}
+
+	// 到期后发布，发布时间记为计划时间，重复执行不再发布
+	if n := publishDue(t, s, publishAt.Add(time.Minute)); n != 1 {
+		t.Fatalf("期望发布 1 篇，实际 %d", n)
+	}
+	if n := publishDue(t, s, publishAt.Add(time.Minute)); n != 0 {
+		t.Fatalf("重复执行不应再次发布，实际 %d", n)
+	}
+	resp := s.Do(t, http.MethodGet, fmt.Sprintf("/api/v1/articles/%d", scheduled.ID), nil, "")
+	resp.AssertOK(t)
+	var a article
+	resp.DecodeData(t, &a)
+	if a.Status != models.ArticlePublished || a.PublishAt != nil || a.PublishedAt == nil || !a.PublishedAt.Equal(publishAt) {
+		t.Fatalf("定时发布后状态不符: %+v", a)
+	}
+	if ids := listArticles(t, s, "/api/v1/articles", ""); len(ids) != 2 {
+		t.Fatalf("发布后应出现在列表中: %v", ids)
+	}
+}
+
+func TestRescheduleAndCancel(t *testing.T) {
+	s := testutil.NewServer(t)
+	token, _ := s.Register(t, "alice", "alice@example.com", "secret123")
+
+	draft := createDraft(t, s, token, "草稿")
+	later := time.Now().Add(2 * time.Hour).UTC().Truncate(time.Second)
+	resp := s.Do(t, http.MethodPut, fmt.Sprintf("/api/v1/articles/%d", draft.ID), map[string]interface{}{"publish_at": later}, token)
+	resp.AssertOK(t)
+	var a article
+	resp.DecodeData(t, &a)
+	if a.PublishAt == nil || !a.PublishAt.Equal(later) {
+		t.Fatalf("定时发布时间未更新: %+v", a)
+	}
+
+	// 撤回为草稿即取消定时发布
+	resp = changeStatus(t, s, token, draft.ID, "unpublish")
+	resp.AssertOK(t)
+	resp.DecodeData(t, &a)
+	if a.PublishAt != nil {
+		t.Fatalf("撤回后应取消定时发布: %+v", a)
+	}
+	if n := publishDue(t, s, later.Add(time.Hour)); n != 0 {
+		t.Fatalf("取消定时后不应发布，实际 %d", n)
+	}
+
+	// 立即发布同样取消定时
+	s.Do(t, http.MethodPut, fmt.Sprintf("/api/v1/articles/%d", draft.ID), map[string]interface{}{"publish_at": later}, token).AssertOK(t)
+	resp = changeStatus(t, s, token, draft.ID, "publish")
+	resp.AssertOK(t)
+	resp.DecodeData(t, &a)
+	if a.Status != models.ArticlePublished || a.PublishAt != nil || a.PublishedAt == nil || !a.PublishedAt.Before(later) {
+		t.Fatalf("立即发布后状态不符: %+v", a)
+	}
+}
+
+func TestScheduledPublishingConcurrentReplicas(t *testing.T) {
+	s := testutil.NewServer(t)
+	token, _ := s.Register(t, "alice", "alice@example.com", "secret123")
+
+	publishAt := time.Now().Add(time.Hour)
+	for i := 0; i < 5; i++ {
+		scheduleArticle(t, s, token, fmt.Sprintf("文章 %d", i), publishAt)
+	}
+
+	// 模拟多个实例同时执行，每篇文章只发布一次
+	var wg sync.WaitGroup
+	results := make([]int, 4)
+	errs := make([]error, len(results))
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			publisher := services.NewScheduledPublisher(repository.NewArticleRepository(s.DB))
+			results[i], errs[i] = publisher.PublishDue(context.Background(), publishAt.Add(time.Minute))
+		}(i)
+	}
+	wg.Wait()
+
+	total := 0
+	for i, n := range results {
+		if errs[i] != nil {
+			t.Fatal(errs[i])
+		}
+		total += n
+	}
+	if total != 5 {
+		t.Fatalf("期望共发布 5 篇，实际 %d", total)
+	}
+	if ids := listArticles(t, s, "/api/v1/articles", ""); len(ids) != 5 {
+		t.Fatalf("全部文章应已发布: %v", ids)
+	}
+}
@@ -0,0 +1,247 @@
+package routes_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"blog-backend/config"
+	"blog-backend/internal/models"
+	"blog-backend/internal/testutil"
+)
+
+type seriesPart struct {
+	ID       uint   `json:"id"`
+	Title    string `json:"title"`
+	Position int    `json:"position"`
+}
+
+type seriesDetail struct {
+	ID       uint         `json:"id"`
+	Title    string       `json:"title"`
+	AuthorID uint         `json:"author_id"`
+	Articles []seriesPart `json:"articles"`
+}
+
+type seriesNav struct {
+	ID       uint        `json:"id"`
+	Position int         `json:"position"`
+	Total    int         `json:"total"`
+	Prev     *seriesPart `json:"prev"`
+	Next     *seriesPart `json:"next"`
+}
+
+func createSeries(t *testing.T, s *testutil.Server, token, title string, articleIDs ...uint) seriesDetail {
+	t.Helper()
+	resp := s.Do(t, http.MethodPost, "/api/v1/series", map[string]interface{}{"title": title, "article_ids": articleIDs}, token)
+	resp.AssertOK(t)
+
+	var series seriesDetail
+	resp.DecodeData(t, &series)
+	return series
+}
+
+func setSeriesArticles(t *testing.T, s *testutil.Server, token string, id uint, articleIDs ...uint) *testutil.Response {
+	t.Helper()
+	if articleIDs == nil {
+		articleIDs = []uint{}
+	}
+	return s.Do(t, http.MethodPut, fmt.Sprintf("/api/v1/series/%d/articles", id), map[string]interface{}{"article_ids": articleIDs}, token)
+}
+
+// 文章详情中的系列导航，文章不属于系列时为 nil
+func articleNav(t *testing.T, s *testutil.Server, path, token string) *seriesNav {
+	t.Helper()
+	resp := s.Do(t, http.MethodGet, path, nil, token)
+	resp.AssertOK(t)
+
+	var data struct {
+		ID     uint       `json:"id"`
+		Series *seriesNav `json:"series"`
+	}
+	resp.DecodeData(t, &data)
+	return data.Series
+}
+
+func partIDs(parts []seriesPart) []uint {
+	ids := []uint{}
+	for _, p := range parts {
+		ids = append(ids, p.ID)
+	}
+	return ids
+}
+
+func TestSeriesNavigation(t *testing.T) {
+	s := testutil.NewServer(t)
+	token, _ := s.Register(t, "alice", "alice@example.com", "secret123")
+
+	first := createArticle(t, s, token, "第一部分")
+	draft := createDraft(t, s, token, "第二部分")
+	third := createArticle(t, s, token, "第三部分")
+	alone := createArticle(t, s, token, "独立文章")
+
+	series := createSeries(t, s, token, "Go 教程", first.ID, draft.ID, third.ID)
+	if fmt.Sprint(partIDs(series.Articles)) != fmt.Sprint([]uint{first.ID, draft.ID, third.ID}) {
+		t.Fatalf("系列文章不符: %+v", series.Articles)
+	}
+
+	// 其他人看不到草稿，导航跳过草稿
+	nav := articleNav(t, s, fmt.Sprintf("/api/v1/articles/%d", first.ID), "")
+	if nav == nil || nav.ID != series.ID || nav.Position != 1 || nav.Total != 2 || nav.Prev != nil || nav.Next == nil || nav.Next.ID != third.ID {
+		t.Fatalf("导航不符: %+v", nav)
+	}
+	nav = articleNav(t, s, fmt.Sprintf("/api/v1/articles/%d", first.ID), token)
+	if nav.Total != 3 || nav.Next.ID != draft.ID {
+		t.Fatalf("作者的导航应包括草稿: %+v", nav)
+	}
+	nav = articleNav(t, s, "/api/v1/articles/slug/"+third.Slug, "")
+	if nav.Position != 2 || nav.Prev == nil || nav.Prev.ID != first.ID || nav.Next != nil {
+		t.Fatalf("按 slug 获取的导航不符: %+v", nav)
+	}
+	if nav := articleNav(t, s, fmt.Sprintf("/api/v1/articles/%d", alone.ID), ""); nav != nil {
+		t.Fatalf("不属于系列的文章不应有导航: %+v", nav)
+	}
+
+	resp := s.Do(t, http.MethodGet, fmt.Sprintf("/api/v1/series/%d", series.ID), nil, "")
+	resp.AssertOK(t)
+	var public seriesDetail
+	resp.DecodeData(t, &public)
+	if fmt.Sprint(partIDs(public.Articles)) != fmt.Sprint([]uint{first.ID, third.ID}) || public.Articles[1].Position != 2 {
+		t.Fatalf("公开的系列详情不应包括草稿: %+v", public.Articles)
+	}
+
+	// 调整顺序并移出草稿
+	setSeriesArticles(t, s, token, series.ID, third.ID, first.ID, alone.ID).AssertOK(t)
+	nav = articleNav(t, s, fmt.Sprintf("/api/v1/articles/%d", first.ID), "")
+	if nav.Position != 2 || nav.Prev.ID != third.ID || nav.Next.ID != alone.ID {
+		t.Fatalf("调整顺序后导航不符: %+v", nav)
+	}
+	if nav := articleNav(t, s, fmt.Sprintf("/api/v1/articles/%d", draft.ID), token); nav != nil {
+		t.Fatalf("移出系列的文章不应有导航: %+v", nav)
+	}
+
+	// 删除文章后从系列中移除
+	s.Do(t, http.MethodDelete, fmt.Sprintf("/api/v1/articles/%d", first.ID), nil, token).AssertOK(t)
+	nav = articleNav(t, s, fmt.Sprintf("/api/v1/articles/%d", third.ID), "")
+	if nav.Total != 2 || nav.Next.ID != alone.ID {
+		t.Fatalf("删除文章后导航不符: %+v", nav)
+	}
+
+	// 删除系列，文章保留
+	s.Do(t, http.MethodDelete, fmt.Sprintf("/api/v1/series/%d", series.ID), nil, token).AssertOK(t)
+	s.Do(t, http.MethodGet, fmt.Sprintf("/api/v1/series/%d", series.ID), nil, "").AssertError(t, http.StatusNotFound, "NOT_FOUND")
+	if nav := articleNav(t, s, fmt.Sprintf("/api/v1/articles/%d", third.ID), ""); nav != nil {
+		t.Fatalf("系列删除后不应有导航: %+v", nav)
+	}
+}
+
+func TestSeriesValidation(t *testing.T) {
+	s := testutil.NewServer(t)
+	aliceToken, _ := s.Register(t, "alice", "alice@example.com", "secret123")
+	bobToken, _ := s.Register(t, "bob", "bob@example.com", "secret123")
+
+	a := createArticle(t, s, aliceToken, "Alice 的文章")
+	b := createArticle(t, s, aliceToken, "Alice 的另一篇文章")
+	hidden := createDraft(t, s, aliceToken, "Alice 的草稿")
+	series := createSeries(t, s, aliceToken, "系列", a.ID)
+
+	for name, ids := range map[string][]uint{
+		"重复的文章":  {b.ID, b.ID},
+		"不存在的文章": {99999},
+	} {
+		resp := s.Do(t, http.MethodPost, "/api/v1/series", map[string]interface{}{"title": name, "article_ids": ids}, aliceToken)
+		resp.AssertError(t, http.StatusBadRequest, "INVALID_INPUT")
+	}
+	s.Do(t, http.MethodPost, "/api/v1/series", map[string]interface{}{"title": ""}, aliceToken).AssertError(t, http.StatusBadRequest, "INVALID_INPUT")
+
+	// 文章只能属于一个系列
+	resp := s.Do(t, http.MethodPost, "/api/v1/series", map[string]interface{}{"title": "另一个系列", "article_ids": []uint{a.ID}}, aliceToken)
+	resp.AssertError(t, http.StatusConflict, "INVALID_INPUT")
+
+	// 不能把别人的文章加入自己的系列，别人的草稿视为不存在
+	own := createSeries(t, s, bobToken, "Bob 的系列")
+	setSeriesArticles(t, s, bobToken, own.ID, b.ID).AssertError(t, http.StatusBadRequest, "INVALID_INPUT")
+	setSeriesArticles(t, s, bobToken, own.ID, hidden.ID).AssertError(t, http.StatusBadRequest, "INVALID_INPUT")
+
+	// 传空列表时清空系列
+	setSeriesArticles(t, s, aliceToken, series.ID).AssertOK(t)
+	createSeries(t, s, aliceToken, "新系列", a.ID)
+}
+
+func TestSeriesOwnership(t *testing.T) {
+	s := testutil.NewServer(t)
+	aliceToken, aliceID := s.Register(t, "alice", "alice@example.com", "secret123")
+	bobToken, _ := s.Register(t, "bob", "bob@example.com", "secret123")
+	editorToken, editorID := s.RegisterWithRole(t, "ed", "ed@example.com", "secret123", models.RoleEditor)
+	adminToken, adminID := s.RegisterWithRole(t, "root", "root@example.com", "secret123", models.RoleAdmin)
+	readerToken, _ := s.RegisterWithRole(t, "reader", "reader@example.com", "secret123", models.RoleReader)
+
+	first := createArticle(t, s, aliceToken, "第一部分")
+	second := createArticle(t, s, aliceToken, "第二部分")
+	series := createSeries(t, s, aliceToken, "Alice 的系列", first.ID, second.ID)
+	path := fmt.Sprintf("/api/v1/series/%d", series.ID)
+
+	s.Do(t, http.MethodPost, "/api/v1/series", map[string]string{"title": "读者的系列"}, readerToken).AssertError(t, http.StatusForbidden, "FORBIDDEN")
+	s.Do(t, http.MethodPut, path, map[string]string{"title": "Bob 修改"}, bobToken).AssertError(t, http.StatusForbidden, "FORBIDDEN")
+	setSeriesArticles(t, s, bobToken, series.ID, second.ID, first.ID).AssertError(t, http.StatusForbidden, "FORBIDDEN")
+	s.Do(t, http.MethodDelete, path, nil, bobToken).AssertError(t, http.StatusForbidden, "FORBIDDEN")
+
+	// 作者本人的操作不记录审计日志
+	s.Do(t, http.MethodPut, path, map[string]string{"description": "作者修改"}, aliceToken).AssertOK(t)
+
+	// 编辑可以修改和调整顺序，但不能删除
+	s.Do(t, http.MethodPut, path, map[string]string{"title": "编辑修改"}, editorToken).AssertOK(t)
+	setSeriesArticles(t, s, editorToken, series.ID, second.ID, first.ID).AssertOK(t)
+	s.Do(t, http.MethodDelete, path, nil, editorToken).AssertError(t, http.StatusForbidden, "FORBIDDEN")
+	s.Do(t, http.MethodDelete, path, nil, adminToken).AssertOK(t)
+
+	logs := listAuditLogs(t, s, adminToken)
+	want := []auditLog{
+		{ActorID: adminID, ActorRole: models.RoleAdmin, Action: models.AuditSeriesDelete, TargetType: "series", TargetID: series.ID, OwnerID: aliceID},
+		{ActorID: editorID, ActorRole: models.RoleEditor, Action: models.AuditSeriesUpdate, TargetType: "series", TargetID: series.ID, OwnerID: aliceID},
+		{ActorID: editorID, ActorRole: models.RoleEditor, Action: models.AuditSeriesUpdate, TargetType: "series", TargetID: series.ID, OwnerID: aliceID},
+	}
+	if fmt.Sprint(logs) != fmt.Sprint(want) {
+		t.Fatalf("审计日志不符: %+v", logs)
+	}
+
+	// 删除系列不影响其中的文章
+	s.Do(t, http.MethodGet, fmt.Sprintf("/api/v1/articles/%d", first.ID), nil, "").AssertOK(t)
+}
+
+func TestDeleteAccountRemovesSeries(t *testing.T) {
+	cfg := testutil.Config()
+	cfg.Account.DeletionPolicy = config.DeletionCascade
+	s := testutil.NewServerWithConfig(t, cfg)
+	token, aliceID := s.Register(t, "alice", "alice@example.com", "secret123")
+
+	a := createArticle(t, s, token, "第一部分")
+	createSeries(t, s, token, "系列", a.ID)
+	deleteAccount(t, s, token, "secret123").AssertOK(t)
+
+	var series, links int64
+	s.DB.Model(&models.Series{}).Where("author_id = ?", aliceID).Count(&series)
+	s.DB.Model(&models.SeriesArticle{}).Count(&links)
+	if series != 0 || links != 0 {
+		t.Fatalf("系列应随账号删除，剩余 %d 个系列、%d 条关联", series, links)
+	}
+}
+
+func TestExportSeries(t *testing.T) {
+	s := testutil.NewServer(t)
+	token, _ := s.Register(t, "alice", "alice@example.com", "secret123")
+	first := createArticle(t, s, token, "第一部分")
+	second := createDraft(t, s, token, "第二部分")
+	series := createSeries(t, s, token, "系列", second.ID, first.ID)
+
+	files := exportData(t, s, token)
+	var exported []struct {
+		ID         uint   `json:"id"`
+		ArticleIDs []uint `json:"article_ids"`
+	}
+	if err := json.Unmarshal([]byte(files["series.json"]), &exported); err != nil || len(exported) != 1 || exported[0].ID != series.ID ||
+		fmt.Sprint(exported[0].ArticleIDs) != fmt.Sprint([]uint{second.ID, first.ID}) {
+		t.Fatalf("系列不符: %v %s", err, files["series.json"])
+	}
+}
@@ -0,0 +1,137 @@
+package routes_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"blog-backend/internal/auth"
+	"blog-backend/internal/models"
+	"blog-backend/internal/repository"
+	"blog-backend/internal/services"
+	"blog-backend/internal/testutil"
+)
+
+type session struct {
+	ID         uint      `json:"id"`
+	UserAgent  string    `json:"user_agent"`
+	IP         string    `json:"ip"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	Current    bool      `json:"current"`
+}
+
+func listSessions(t *testing.T, s *testutil.Server, token string) []session {
+	t.Helper()
+	resp := s.Do(t, http.MethodGet, "/api/v1/users/me/sessions", nil, token)
+	resp.AssertOK(t)
+
+	var sessions []session
+	resp.DecodeData(t, &sessions)
+	return sessions
+}
+
+func TestListAndRevokeSessions(t *testing.T) {
+	s := testutil.NewServer(t)
+	registerToken, _ := s.Register(t, "alice", "alice@example.com", "secret123")
+	laptop := login(t, s, "alice@example.com", "secret123")
+	phone := login(t, s, "alice@example.com", "secret123")
+	bobToken, _ := s.Register(t, "bob", "bob@example.com", "secret123")
+
+	// 注册和 Register 辅助函数中的登录各产生一个会话
+	sessions := listSessions(t, s, laptop.Token)
+	if len(sessions) != 4 {
+		t.Fatalf("期望 4 个会话，实际 %d", len(sessions))
+	}
+	var phoneID uint
+	currents := 0
+	for _, sess := range sessions {
+		if sess.IP == "" || sess.ExpiresAt.Before(sess.CreatedAt) {
+			t.Fatalf("会话信息不完整: %+v", sess)
+		}
+		if sess.Current {
+			currents++
+		}
+	}
+	if currents != 1 {
+		t.Fatalf("期望恰好一个当前会话，实际 %d", currents)
+	}
+	for _, sess := range listSessions(t, s, phone.Token) {
+		if sess.Current {
+			phoneID = sess.ID
+		}
+	}
+
+	// 不能撤销他人的会话
+	path := fmt.Sprintf("/api/v1/users/me/sessions/%d", phoneID)
+	s.Do(t, http.MethodDelete, path, nil, bobToken).AssertError(t, http.StatusNotFound, "NOT_FOUND")
+	s.Do(t, http.MethodGet, "/api/v1/users/me", nil, phone.Token).AssertOK(t)
+
+	s.Do(t, http.MethodDelete, path, nil, laptop.Token).AssertOK(t)
+	s.Do(t, http.MethodGet, "/api/v1/users/me", nil, phone.Token).AssertError(t, http.StatusUnauthorized, "UNAUTHORIZED")
+	refresh(t, s, phone.RefreshToken).AssertError(t, http.StatusUnauthorized, "UNAUTHORIZED")
+	if got := len(listSessions(t, s, laptop.Token)); got != 3 {
+		t.Fatalf("撤销后期望 3 个会话，实际 %d", got)
+	}
+
+	s.Do(t, http.MethodDelete, "/api/v1/users/me/sessions/abc", nil, laptop.Token).AssertError(t, http.StatusBadRequest, "INVALID_INPUT")
+	s.Do(t, http.MethodGet, "/api/v1/users/me/sessions", nil, "").AssertError(t, http.StatusUnauthorized, "UNAUTHORIZED")
+	s.Do(t, http.MethodGet, "/api/v1/users/me", nil, registerToken).AssertOK(t)
+}
+
+func TestLogoutEverywhere(t *testing.T) {
+	s := testutil.NewServer(t)
+	s.Register(t, "alice", "alice@example.com", "secret123")
+	laptop := login(t, s, "alice@example.com", "secret123")
+	phone := login(t, s, "alice@example.com", "secret123")
+	bobToken, _ := s.Register(t, "bob", "bob@example.com", "secret123")
+
+	s.Do(t, http.MethodDelete, "/api/v1/users/me/sessions", nil, laptop.Token).AssertOK(t)
+
+	for _, pair := range []tokenPair{laptop, phone} {
+		s.Do(t, http.MethodGet, "/api/v1/users/me", nil, pair.Token).AssertError(t, http.StatusUnauthorized, "UNAUTHORIZED")
+		refresh(t, s, pair.RefreshToken).AssertError(t, http.StatusUnauthorized, "UNAUTHORIZED")
+	}
+	s.Do(t, http.MethodGet, "/api/v1/users/me", nil, bobToken).AssertOK(t)
+}
+
+// 读取会话后立即撤销，模拟刷新令牌期间会话被并发撤销
+type revokeAfterFind struct {
+	repository.SessionRepository
+}
+
+func (r revokeAfterFind) FindByID(ctx context.Context, id uint) (*models.Session, error) {
+	session, err := r.SessionRepository.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return session, r.SessionRepository.Revoke(ctx, id)
+}
+
+func TestRefreshDoesNotRestoreRevokedSession(t *testing.T) {
+	s := testutil.NewServer(t)
+	s.Register(t, "alice", "alice@example.com", "secret123")
+	pair := login(t, s, "alice@example.com", "secret123")
+
+	tokens, err := auth.NewTokenManager(s.Config.JWT)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sessions := revokeAfterFind{repository.NewSessionRepository(s.DB)}
+	service := services.NewSessionService(sessions, repository.NewUserRepository(s.DB), tokens, s.Config.JWT.RefreshExpire)
+
+	_, err = service.Refresh(context.Background(), pair.RefreshToken, services.ClientInfo{UserAgent: "test", IP: "192.0.2.1"})
+	if err != services.ErrSessionRevoked {
+		t.Fatalf("期望 ErrSessionRevoked，实际 %v", err)
+	}
+
+	var revoked int64
+	s.DB.Model(&models.Session{}).Where("revoked_at IS NOT NULL").Count(&revoked)
+	if revoked != 1 {
+		t.Fatalf("刷新不应恢复已撤销的会话，已撤销会话 %d 个", revoked)
+	}
+	s.Do(t, http.MethodGet, "/api/v1/users/me", nil, pair.Token).AssertError(t, http.StatusUnauthorized, "UNAUTHORIZED")
+}
@@ -0,0 +1,120 @@
+package routes_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"blog-backend/internal/models"
+	"blog-backend/internal/testutil"
+)
+
+// 旧 slug 的重定向不使用统一响应格式，直接读取，返回重定向地址
+func slugRedirect(t *testing.T, s *testutil.Server, articleSlug string) string {
+	t.Helper()
+	w := httptest.NewRecorder()
+	s.Engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/articles/slug/"+articleSlug, nil))
+	if w.Code != http.StatusMovedPermanently {
+		t.Fatalf("期望 301 重定向，实际状态码 %d: %s", w.Code, w.Body)
+	}
+	return w.Header().Get("Location")
+}
+
+func TestArticleSlugs(t *testing.T) {
+	s := testutil.NewServer(t)
+	token, _ := s.Register(t, "alice", "alice@example.com", "secret123")
+
+	for title, want := range map[string]string{
+		"中文标题：Go 语言入门":           "zhong-wen-biao-ti-go-yu-yan-ru-men",
+		"Café Ünïcödé — Straße!": "cafe-unicode-strasse",
+		"Don't Panic":            "dont-panic",
+		"！！！":                    "article",
+	} {
+		if a := createArticle(t, s, token, title); a.Slug != want {
+			t.Fatalf("%q 的 slug 期望 %q，实际 %q", title, want, a.Slug)
+		}
+	}
+
+	// 重复的标题追加序号
+	first := createArticle(t, s, token, "Hello World")
+	second := createArticle(t, s, token, "Hello, World")
+	if first.Slug != "hello-world" || second.Slug != "hello-world-2" {
+		t.Fatalf("slug 未去重: %q %q", first.Slug, second.Slug)
+	}
+
+	// 按 slug 访问增加浏览量
+	resp := s.Do(t, http.MethodGet, "/api/v1/articles/slug/hello-world-2", nil, "")
+	resp.AssertOK(t)
+	var a article
+	resp.DecodeData(t, &a)
+	if a.ID != second.ID || a.Views != 1 {
+		t.Fatalf("按 slug 获取的文章不符: %+v", a)
+	}
+	s.Do(t, http.MethodGet, "/api/v1/articles/slug/missing", nil, "").AssertError(t, http.StatusNotFound, "NOT_FOUND")
+
+	// 草稿的 slug 对其他人不可见
+	draft := createDraft(t, s, token, "草稿")
+	s.Do(t, http.MethodGet, "/api/v1/articles/slug/"+draft.Slug, nil, "").AssertError(t, http.StatusNotFound, "NOT_FOUND")
+	s.Do(t, http.MethodGet, "/api/v1/articles/slug/"+draft.Slug, nil, token).AssertOK(t)
+}
+
+func TestArticleSlugRedirect(t *testing.T) {
+	s := testutil.NewServer(t)
+	token, _ := s.Register(t, "alice", "alice@example.com", "secret123")
+
+	a := createArticle(t, s, token, "First Title")
+	path := fmt.Sprintf("/api/v1/articles/%d", a.ID)
+
+	// 修改标题生成新的 slug，旧 slug 重定向到新 slug
+	var updated article
+	resp := s.Do(t, http.MethodPut, path, map[string]string{"title": "Second Title"}, token)
+	resp.AssertOK(t)
+	resp.DecodeData(t, &updated)
+	if updated.Slug != "second-title" {
+		t.Fatalf("修改标题后 slug 期望 second-title，实际 %q", updated.Slug)
+	}
+	if loc := slugRedirect(t, s, "first-title"); loc != "/api/v1/articles/slug/second-title" {
+		t.Fatalf("重定向地址不符: %q", loc)
+	}
+
+	// 只修改内容不改变 slug
+	resp = s.Do(t, http.MethodPut, path, map[string]string{"content": "新内容"}, token)
+	resp.AssertOK(t)
+	resp.DecodeData(t, &updated)
+	if updated.Slug != "second-title" {
+		t.Fatalf("只修改内容不应改变 slug: %q", updated.Slug)
+	}
+
+	// 旧 slug 仍属于原文章，其他文章不能使用
+	other := createArticle(t, s, token, "First Title")
+	if other.Slug != "first-title-2" {
+		t.Fatalf("旧 slug 不应被其他文章占用: %q", other.Slug)
+	}
+
+	// 改回原标题时重新使用原 slug，之后的旧 slug 都重定向到它
+	resp = s.Do(t, http.MethodPut, path, map[string]string{"title": "First Title"}, token)
+	resp.AssertOK(t)
+	resp.DecodeData(t, &updated)
+	if updated.Slug != "first-title" {
+		t.Fatalf("改回原标题后 slug 期望 first-title，实际 %q", updated.Slug)
+	}
+	s.Do(t, http.MethodGet, "/api/v1/articles/slug/first-title", nil, "").AssertOK(t)
+	if loc := slugRedirect(t, s, "second-title"); loc != "/api/v1/articles/slug/first-title" {
+		t.Fatalf("重定向地址不符: %q", loc)
+	}
+
+	// 删除文章后旧 slug 可以被重新使用
+	s.Do(t, http.MethodDelete, path, nil, token).AssertOK(t)
+	s.Do(t, http.MethodGet, "/api/v1/articles/slug/second-title", nil, "").AssertError(t, http.StatusNotFound, "NOT_FOUND")
+	var count int64
+	if err := s.DB.Model(&models.ArticleSlug{}).Where("article_id = ?", a.ID).Count(&count).Error; err != nil {
+		t.Fatal(err)
+	}
+	if count != 0 {
+		t.Fatalf("删除文章后应删除历史 slug，剩余 %d 条", count)
+	}
+	if reused := createArticle(t, s, token, "Second Title"); reused.Slug != "second-title" {
+		t.Fatalf("删除后 slug 应可重新使用: %q", reused.Slug)
+	}
+}
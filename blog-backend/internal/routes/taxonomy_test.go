@@ -0,0 +1,297 @@
+package routes_test
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"blog-backend/internal/models"
+	"blog-backend/internal/testutil"
+)
+
+type tagCount struct {
+	ID           uint   `json:"id"`
+	Name         string `json:"name"`
+	Slug         string `json:"slug"`
+	ArticleCount int64  `json:"article_count"`
+}
+
+type categoryNode struct {
+	ID           uint           `json:"id"`
+	Name         string         `json:"name"`
+	Slug         string         `json:"slug"`
+	ParentID     *uint          `json:"parent_id"`
+	ArticleCount int64          `json:"article_count"`
+	Children     []categoryNode `json:"children"`
+}
+
+func createTaggedArticle(t *testing.T, s *testutil.Server, token, title string, body map[string]interface{}) article {
+	t.Helper()
+	body["title"], body["content"] = title, "正文"
+	resp := s.Do(t, http.MethodPost, "/api/v1/articles", body, token)
+	resp.AssertOK(t)
+
+	var a article
+	resp.DecodeData(t, &a)
+	return a
+}
+
+func tagNames(a article) []string {
+	names := make([]string, 0, len(a.Tags))
+	for _, tag := range a.Tags {
+		names = append(names, tag.Name)
+	}
+	return names
+}
+
+func listTags(t *testing.T, s *testutil.Server) []tagCount {
+	t.Helper()
+	resp := s.Do(t, http.MethodGet, "/api/v1/tags", nil, "")
+	resp.AssertOK(t)
+
+	var tags []tagCount
+	resp.DecodeData(t, &tags)
+	return tags
+}
+
+func createCategory(t *testing.T, s *testutil.Server, token string, body map[string]interface{}) categoryNode {
+	t.Helper()
+	resp := s.Do(t, http.MethodPost, "/api/v1/categories", body, token)
+	resp.AssertOK(t)
+
+	var c categoryNode
+	resp.DecodeData(t, &c)
+	return c
+}
+
+func TestArticleTags(t *testing.T) {
+	s := testutil.NewServer(t)
+	token, _ := s.Register(t, "alice", "alice@example.com", "secret123")
+
+	// 名称不区分大小写，重复的标签只保留一个
+	first := createTaggedArticle(t, s, token, "第一篇", map[string]interface{}{"tags": []string{"Go", "go", "  Web   开发 "}})
+	if names := tagNames(first); len(names) != 2 || names[0] != "Go" || names[1] != "Web 开发" {
+		t.Fatalf("标签不符: %v", names)
+	}
+	if first.Tags[1].Slug != "web-kai-fa" {
+		t.Fatalf("标签 slug 不符: %q", first.Tags[1].Slug)
+	}
+	second := createTaggedArticle(t, s, token, "第二篇", map[string]interface{}{"tags": []string{"GO"}})
+	if names := tagNames(second); len(names) != 1 || names[0] != "Go" {
+		t.Fatalf("应使用已有的标签: %v", names)
+	}
+	draft := createTaggedArticle(t, s, token, "草稿", map[string]interface{}{"tags": []string{"Go"}, "status": "draft"})
+	if plain := createArticle(t, s, token, "无标签"); plain.Tags == nil || len(plain.Tags) != 0 {
+		t.Fatalf("没有标签时应返回空列表: %+v", plain.Tags)
+	}
+
+	tooMany := make([]string, 11)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("tag%d", i)
+	}
+	for _, tags := range [][]string{tooMany, {" "}, {strings.Repeat("长", 31)}} {
+		s.Do(t, http.MethodPost, "/api/v1/articles", map[string]interface{}{"title": "x", "content": "x", "tags": tags}, token).
+			AssertError(t, http.StatusBadRequest, "INVALID_INPUT")
+	}
+
+	// 按标签筛选，只包括已发布的文章
+	if ids := listArticles(t, s, "/api/v1/articles?tag=go", ""); len(ids) != 2 {
+		t.Fatalf("按标签筛选不符: %v", ids)
+	}
+	if ids := listArticles(t, s, "/api/v1/articles?tag=web-kai-fa", ""); len(ids) != 1 || ids[0] != first.ID {
+		t.Fatalf("按标签筛选不符: %v", ids)
+	}
+	if ids := listArticles(t, s, "/api/v1/articles?tag=missing", ""); len(ids) != 0 {
+		t.Fatalf("不存在的标签应返回空列表: %v", ids)
+	}
+	if ids := listArticles(t, s, "/api/v1/articles?status=draft&tag=go", token); len(ids) != 1 || ids[0] != draft.ID {
+		t.Fatalf("按标签筛选草稿不符: %v", ids)
+	}
+
+	// 标签云只统计已发布的文章，按文章数量排列
+	tags := listTags(t, s)
+	if len(tags) != 2 || tags[0].Slug != "go" || tags[0].ArticleCount != 2 || tags[1].ArticleCount != 1 {
+		t.Fatalf("标签云不符: %+v", tags)
+	}
+
+	// 不传标签时保持不变，传空列表时清除，否则替换
+	path := fmt.Sprintf("/api/v1/articles/%d", first.ID)
+	var a article
+	resp := s.Do(t, http.MethodPut, path, map[string]interface{}{"content": "新内容"}, token)
+	resp.AssertOK(t)
+	resp.DecodeData(t, &a)
+	if len(a.Tags) != 2 {
+		t.Fatalf("未传标签时不应修改: %v", tagNames(a))
+	}
+	resp = s.Do(t, http.MethodPut, path, map[string]interface{}{"tags": []string{"Rust", "web 开发"}}, token)
+	resp.AssertOK(t)
+	resp.DecodeData(t, &a)
+	if names := tagNames(a); len(names) != 2 || names[0] != "Rust" || names[1] != "Web 开发" {
+		t.Fatalf("替换后的标签不符: %v", names)
+	}
+	s.Do(t, http.MethodPut, path, map[string]interface{}{"tags": []string{}}, token).AssertOK(t)
+	resp = s.Do(t, http.MethodGet, path, nil, "")
+	resp.DecodeData(t, &a)
+	if len(a.Tags) != 0 {
+		t.Fatalf("标签应已清除: %v", tagNames(a))
+	}
+	if ids := listArticles(t, s, "/api/v1/articles?tag=go", ""); len(ids) != 1 || ids[0] != second.ID {
+		t.Fatalf("清除标签后筛选不符: %v", ids)
+	}
+}
+
+func TestTagManagement(t *testing.T) {
+	s := testutil.NewServer(t)
+	token, _ := s.Register(t, "alice", "alice@example.com", "secret123")
+	editorToken, _ := s.RegisterWithRole(t, "eve", "eve@example.com", "secret123", models.RoleEditor)
+
+	a := createTaggedArticle(t, s, token, "文章", map[string]interface{}{"tags": []string{"Python"}})
+
+	// 只有编辑和管理员可以管理标签
+	s.Do(t, http.MethodPost, "/api/v1/tags", map[string]string{"name": "Rust"}, "").AssertError(t, http.StatusUnauthorized, "UNAUTHORIZED")
+	s.Do(t, http.MethodPost, "/api/v1/tags", map[string]string{"name": "Rust"}, token).AssertError(t, http.StatusForbidden, "FORBIDDEN")
+	s.Do(t, http.MethodPost, "/api/v1/tags", map[string]string{"name": "python"}, editorToken).AssertError(t, http.StatusConflict, "INVALID_INPUT")
+
+	var rust tagCount
+	resp := s.Do(t, http.MethodPost, "/api/v1/tags", map[string]string{"name": "Rust"}, editorToken)
+	resp.AssertOK(t)
+	resp.DecodeData(t, &rust)
+	if rust.Slug != "rust" {
+		t.Fatalf("标签 slug 不符: %+v", rust)
+	}
+
+	// 重命名时重新生成 slug，不能与其他标签重名
+	tags := listTags(t, s)
+	python := tags[0]
+	s.Do(t, http.MethodPut, fmt.Sprintf("/api/v1/tags/%d", python.ID), map[string]string{"name": "RUST"}, editorToken).
+		AssertError(t, http.StatusConflict, "INVALID_INPUT")
+	resp = s.Do(t, http.MethodPut, fmt.Sprintf("/api/v1/tags/%d", python.ID), map[string]string{"name": "Python 3"}, editorToken)
+	resp.AssertOK(t)
+	resp.DecodeData(t, &python)
+	if python.Slug != "python-3" {
+		t.Fatalf("重命名后 slug 不符: %+v", python)
+	}
+	if ids := listArticles(t, s, "/api/v1/articles?tag=python-3", ""); len(ids) != 1 || ids[0] != a.ID {
+		t.Fatalf("重命名后筛选不符: %v", ids)
+	}
+
+	// 删除标签同时从文章中移除
+	s.Do(t, http.MethodDelete, fmt.Sprintf("/api/v1/tags/%d", python.ID), nil, editorToken).AssertOK(t)
+	s.Do(t, http.MethodDelete, fmt.Sprintf("/api/v1/tags/%d", python.ID), nil, editorToken).AssertError(t, http.StatusNotFound, "NOT_FOUND")
+	var got article
+	resp = s.Do(t, http.MethodGet, fmt.Sprintf("/api/v1/articles/%d", a.ID), nil, "")
+	resp.DecodeData(t, &got)
+	if len(got.Tags) != 0 {
+		t.Fatalf("删除标签后文章不应再有该标签: %v", tagNames(got))
+	}
+	if tags := listTags(t, s); len(tags) != 1 || tags[0].Name != "Rust" {
+		t.Fatalf("删除后的标签不符: %+v", tags)
+	}
+}
+
+func TestCategories(t *testing.T) {
+	s := testutil.NewServer(t)
+	token, _ := s.Register(t, "alice", "alice@example.com", "secret123")
+	editorToken, _ := s.RegisterWithRole(t, "eve", "eve@example.com", "secret123", models.RoleEditor)
+
+	s.Do(t, http.MethodPost, "/api/v1/categories", map[string]string{"name": "后端"}, token).AssertError(t, http.StatusForbidden, "FORBIDDEN")
+
+	backend := createCategory(t, s, editorToken, map[string]interface{}{"name": "后端", "slug": "backend"})
+	golang := createCategory(t, s, editorToken, map[string]interface{}{"name": "Go 语言", "parent_id": backend.ID})
+	frontend := createCategory(t, s, editorToken, map[string]interface{}{"name": "前端"})
+	if golang.Slug != "go-yu-yan" || frontend.Slug != "qian-duan" || golang.ParentID == nil || *golang.ParentID != backend.ID {
+		t.Fatalf("分类不符: %+v %+v", golang, frontend)
+	}
+	for _, body := range []map[string]interface{}{
+		{"name": "x", "slug": "Back End"},
+		{"name": "x", "parent_id": 999},
+		{"name": ""},
+	} {
+		s.Do(t, http.MethodPost, "/api/v1/categories", body, editorToken).AssertError(t, http.StatusBadRequest, "INVALID_INPUT")
+	}
+	s.Do(t, http.MethodPost, "/api/v1/categories", map[string]interface{}{"name": "x", "slug": "backend"}, editorToken).
+		AssertError(t, http.StatusConflict, "INVALID_INPUT")
+
+	// 文章可以属于任一分类，分类不存在时返回 400
+	inBackend := createTaggedArticle(t, s, token, "后端文章", map[string]interface{}{"category_id": backend.ID})
+	inGo := createTaggedArticle(t, s, token, "Go 文章", map[string]interface{}{"category_id": golang.ID, "tags": []string{"并发"}})
+	inFrontend := createTaggedArticle(t, s, token, "前端文章", map[string]interface{}{"category_id": frontend.ID})
+	createTaggedArticle(t, s, token, "后端草稿", map[string]interface{}{"category_id": backend.ID, "status": "draft"})
+	if inGo.CategoryID == nil || *inGo.CategoryID != golang.ID {
+		t.Fatalf("文章分类不符: %+v", inGo)
+	}
+	s.Do(t, http.MethodPost, "/api/v1/articles", map[string]interface{}{"title": "x", "content": "x", "category_id": 999}, token).
+		AssertError(t, http.StatusBadRequest, "INVALID_INPUT")
+
+	// 按分类筛选包括子分类，可以和标签同时使用
+	if ids := listArticles(t, s, "/api/v1/articles?category=backend", ""); len(ids) != 2 {
+		t.Fatalf("按分类筛选应包括子分类: %v", ids)
+	}
+	if ids := listArticles(t, s, "/api/v1/articles?category=go-yu-yan", ""); len(ids) != 1 || ids[0] != inGo.ID {
+		t.Fatalf("按子分类筛选不符: %v", ids)
+	}
+	if ids := listArticles(t, s, "/api/v1/articles?category=backend&tag=bing-fa", ""); len(ids) != 1 || ids[0] != inGo.ID {
+		t.Fatalf("按分类和标签筛选不符: %v", ids)
+	}
+	if ids := listArticles(t, s, "/api/v1/articles?category=missing", ""); len(ids) != 0 {
+		t.Fatalf("不存在的分类应返回空列表: %v", ids)
+	}
+
+	// 分类树的文章数量包括子分类中已发布的文章
+	var tree []categoryNode
+	resp := s.Do(t, http.MethodGet, "/api/v1/categories", nil, "")
+	resp.AssertOK(t)
+	resp.DecodeData(t, &tree)
+	if len(tree) != 2 || tree[0].ID != frontend.ID || tree[1].ID != backend.ID {
+		t.Fatalf("分类树不符: %+v", tree)
+	}
+	if tree[1].ArticleCount != 2 || len(tree[1].Children) != 1 || tree[1].Children[0].ArticleCount != 1 || tree[0].ArticleCount != 1 {
+		t.Fatalf("分类文章数量不符: %+v", tree)
+	}
+
+	// 不能移动到自身或子分类下
+	backendPath := fmt.Sprintf("/api/v1/categories/%d", backend.ID)
+	for _, parent := range []uint{backend.ID, golang.ID} {
+		s.Do(t, http.MethodPut, backendPath, map[string]interface{}{"parent_id": parent}, editorToken).
+			AssertError(t, http.StatusBadRequest, "INVALID_INPUT")
+	}
+
+	// 有子分类时不能删除
+	s.Do(t, http.MethodDelete, backendPath, nil, editorToken).AssertError(t, http.StatusConflict, "INVALID_INPUT")
+	var moved categoryNode
+	resp = s.Do(t, http.MethodPut, fmt.Sprintf("/api/v1/categories/%d", golang.ID), map[string]interface{}{"parent_id": 0, "slug": "golang"}, editorToken)
+	resp.AssertOK(t)
+	resp.DecodeData(t, &moved)
+	if moved.ParentID != nil || moved.Slug != "golang" || moved.Name != "Go 语言" {
+		t.Fatalf("移动后的分类不符: %+v", moved)
+	}
+	s.Do(t, http.MethodDelete, backendPath, nil, editorToken).AssertOK(t)
+	s.Do(t, http.MethodDelete, backendPath, nil, editorToken).AssertError(t, http.StatusNotFound, "NOT_FOUND")
+
+	// 删除分类后其中的文章变为未分类
+	var a article
+	resp = s.Do(t, http.MethodGet, fmt.Sprintf("/api/v1/articles/%d", inBackend.ID), nil, "")
+	resp.DecodeData(t, &a)
+	if a.CategoryID != nil {
+		t.Fatalf("删除分类后文章应为未分类: %+v", a)
+	}
+
+	// 修改文章分类，0 表示取消分类
+	path := fmt.Sprintf("/api/v1/articles/%d", inFrontend.ID)
+	resp = s.Do(t, http.MethodPut, path, map[string]interface{}{"category_id": golang.ID}, token)
+	resp.AssertOK(t)
+	resp.DecodeData(t, &a)
+	if a.CategoryID == nil || *a.CategoryID != golang.ID {
+		t.Fatalf("修改分类后不符: %+v", a)
+	}
+	resp = s.Do(t, http.MethodPut, path, map[string]interface{}{"category_id": 0}, token)
+	resp.AssertOK(t)
+	resp.DecodeData(t, &a)
+	if a.CategoryID != nil {
+		t.Fatalf("取消分类后不符: %+v", a)
+	}
+	if ids := listArticles(t, s, "/api/v1/articles?category=golang", ""); len(ids) != 1 || ids[0] != inGo.ID {
+		t.Fatalf("按移动后的分类筛选不符: %v", ids)
+	}
+}
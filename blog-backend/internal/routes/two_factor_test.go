@@ -0,0 +1,141 @@
+package routes_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"blog-backend/internal/auth"
+	"blog-backend/internal/testutil"
+)
+
+type recoveryCodes struct {
+	Codes []string `json:"recovery_codes"`
+}
+
+func totpCode(t *testing.T, secret string, step int64) string {
+	t.Helper()
+	code, err := auth.TOTPCode(secret, step)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return code
+}
+
+// 开启两步验证，返回密钥、确认时使用的时间步和恢复码
+func enableTwoFactor(t *testing.T, s *testutil.Server, token, password string) (string, int64, []string) {
+	t.Helper()
+	resp := s.Do(t, http.MethodPost, "/api/v1/users/me/2fa/enroll", map[string]string{"password": password}, token)
+	resp.AssertOK(t)
+	var enrollment struct {
+		Secret string `json:"secret"`
+		URI    string `json:"otpauth_uri"`
+	}
+	resp.DecodeData(t, &enrollment)
+	if enrollment.Secret == "" || enrollment.URI == "" {
+		t.Fatalf("unexpected enrollment: %s", resp.Body)
+	}
+
+	step := auth.TOTPStep(time.Now())
+	resp = s.Do(t, http.MethodPost, "/api/v1/users/me/2fa/confirm", map[string]string{"code": totpCode(t, enrollment.Secret, step)}, token)
+	resp.AssertOK(t)
+	var codes recoveryCodes
+	resp.DecodeData(t, &codes)
+	if len(codes.Codes) != 10 {
+		t.Fatalf("期望 10 个恢复码，实际 %d", len(codes.Codes))
+	}
+	return enrollment.Secret, step, codes.Codes
+}
+
+// 密码校验通过后返回登录挑战令牌
+func loginChallenge(t *testing.T, s *testutil.Server, email, password string) string {
+	t.Helper()
+	resp := s.Do(t, http.MethodPost, "/api/v1/auth/login", map[string]string{"email": email, "password": password}, "")
+	resp.AssertOK(t)
+	var challenge struct {
+		Required bool   `json:"two_factor_required"`
+		Token    string `json:"challenge_token"`
+		Access   string `json:"token"`
+	}
+	resp.DecodeData(t, &challenge)
+	if !challenge.Required || challenge.Token == "" || challenge.Access != "" {
+		t.Fatalf("期望返回两步验证挑战，实际: %s", resp.Body)
+	}
+	return challenge.Token
+}
+
+func loginTwoFactor(t *testing.T, s *testutil.Server, challenge, code string) *testutil.Response {
+	t.Helper()
+	return s.Do(t, http.MethodPost, "/api/v1/auth/login/2fa", map[string]string{"challenge_token": challenge, "code": code}, "")
+}
+
+func TestTwoFactorLogin(t *testing.T) {
+	s := testutil.NewServer(t)
+	token, _ := s.Register(t, "alice", "alice@example.com", "secret123")
+	secret, step, codes := enableTwoFactor(t, s, token, "secret123")
+
+	// 已开启时不能重复开启
+	s.Do(t, http.MethodPost, "/api/v1/users/me/2fa/enroll", map[string]string{"password": "secret123"}, token).
+		AssertError(t, http.StatusBadRequest, "INVALID_INPUT")
+
+	challenge := loginChallenge(t, s, "alice@example.com", "secret123")
+	loginTwoFactor(t, s, challenge, "000000").AssertError(t, http.StatusBadRequest, "INVALID_INPUT")
+	// 确认时使用过的验证码不能再次使用
+	loginTwoFactor(t, s, challenge, totpCode(t, secret, step)).AssertError(t, http.StatusBadRequest, "INVALID_INPUT")
+
+	next := totpCode(t, secret, step+1)
+	resp := loginTwoFactor(t, s, challenge, next)
+	resp.AssertOK(t)
+	var pair tokenPair
+	resp.DecodeData(t, &pair)
+	s.Do(t, http.MethodGet, "/api/v1/users/me", nil, pair.Token).AssertOK(t)
+
+	loginTwoFactor(t, s, challenge, next).AssertError(t, http.StatusBadRequest, "INVALID_INPUT")
+	loginTwoFactor(t, s, "invalid", next).AssertError(t, http.StatusUnauthorized, "UNAUTHORIZED")
+
+	// 恢复码只能使用一次
+	loginTwoFactor(t, s, challenge, codes[0]).AssertOK(t)
+	loginTwoFactor(t, s, challenge, codes[0]).AssertError(t, http.StatusBadRequest, "INVALID_INPUT")
+}
+
+func TestTwoFactorRecoveryCodesAndDisable(t *testing.T) {
+	s := testutil.NewServer(t)
+	token, _ := s.Register(t, "alice", "alice@example.com", "secret123")
+	_, _, codes := enableTwoFactor(t, s, token, "secret123")
+
+	regenerate := func(password string) *testutil.Response {
+		return s.Do(t, http.MethodPost, "/api/v1/users/me/2fa/recovery-codes", map[string]string{"password": password}, token)
+	}
+	regenerate("wrong").AssertError(t, http.StatusBadRequest, "INVALID_INPUT")
+	resp := regenerate("secret123")
+	resp.AssertOK(t)
+	var fresh recoveryCodes
+	resp.DecodeData(t, &fresh)
+
+	// 旧恢复码作废
+	challenge := loginChallenge(t, s, "alice@example.com", "secret123")
+	loginTwoFactor(t, s, challenge, codes[0]).AssertError(t, http.StatusBadRequest, "INVALID_INPUT")
+	loginTwoFactor(t, s, challenge, fresh.Codes[0]).AssertOK(t)
+
+	disable := func(password string) *testutil.Response {
+		return s.Do(t, http.MethodPost, "/api/v1/users/me/2fa/disable", map[string]string{"password": password}, token)
+	}
+	disable("wrong").AssertError(t, http.StatusBadRequest, "INVALID_INPUT")
+	disable("secret123").AssertOK(t)
+	disable("secret123").AssertError(t, http.StatusBadRequest, "INVALID_INPUT")
+
+	login(t, s, "alice@example.com", "secret123")
+	loginTwoFactor(t, s, challenge, fresh.Codes[1]).AssertError(t, http.StatusUnauthorized, "UNAUTHORIZED")
+}
+
+func TestTwoFactorConfirmRequiresEnroll(t *testing.T) {
+	s := testutil.NewServer(t)
+	token, _ := s.Register(t, "alice", "alice@example.com", "secret123")
+
+	s.Do(t, http.MethodPost, "/api/v1/users/me/2fa/confirm", map[string]string{"code": "123456"}, token).
+		AssertError(t, http.StatusBadRequest, "INVALID_INPUT")
+	s.Do(t, http.MethodPost, "/api/v1/users/me/2fa/enroll", map[string]string{"password": "wrong"}, token).
+		AssertError(t, http.StatusBadRequest, "INVALID_INPUT")
+	s.Do(t, http.MethodPost, "/api/v1/users/me/2fa/disable", map[string]string{"password": "secret123"}, token).
+		AssertError(t, http.StatusBadRequest, "INVALID_INPUT")
+}
@@ -0,0 +1,72 @@
+package routes_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"blog-backend/internal/testutil"
+)
+
+// 注册但不验证邮箱，返回登录后的访问令牌
+func registerUnverified(t *testing.T, s *testutil.Server, username, email, password string) string {
+	t.Helper()
+	s.Do(t, http.MethodPost, "/api/v1/auth/register", map[string]string{
+		"username": username,
+		"email":    email,
+		"password": password,
+	}, "").AssertOK(t)
+	return login(t, s, email, password).Token
+}
+
+func verifyEmail(t *testing.T, s *testutil.Server, token string) *testutil.Response {
+	t.Helper()
+	return s.Do(t, http.MethodPost, "/api/v1/auth/verify-email", map[string]string{"token": token}, "")
+}
+
+func TestUnverifiedUserCannotPublish(t *testing.T) {
+	s := testutil.NewServer(t)
+	aliceToken, _ := s.Register(t, "alice", "alice@example.com", "secret123")
+	a := createArticle(t, s, aliceToken, "文章")
+
+	bobToken := registerUnverified(t, s, "bob", "bob@example.com", "secret123")
+	s.Do(t, http.MethodPost, "/api/v1/articles", map[string]string{"title": "t", "content": "c"}, bobToken).
+		AssertError(t, http.StatusForbidden, "EMAIL_NOT_VERIFIED")
+	s.Do(t, http.MethodPost, "/api/v1/articles/1/comments", map[string]string{"content": "c"}, bobToken).
+		AssertError(t, http.StatusForbidden, "EMAIL_NOT_VERIFIED")
+
+	// 重发后两封邮件中的链接都有效
+	s.Do(t, http.MethodPost, "/api/v1/auth/resend-verification", nil, bobToken).AssertOK(t)
+	if mails := s.Mails(t, "bob@example.com"); len(mails) != 2 {
+		t.Fatalf("期望 2 封验证邮件，实际 %d", len(mails))
+	}
+	token := s.MailToken(t, "bob@example.com")
+	verifyEmail(t, s, token[:len(token)-2]+"xx").AssertError(t, http.StatusBadRequest, "INVALID_INPUT")
+	resp := verifyEmail(t, s, token)
+	resp.AssertOK(t)
+	var user struct {
+		VerifiedAt *time.Time `json:"verified_at"`
+	}
+	resp.DecodeData(t, &user)
+	if user.VerifiedAt == nil {
+		t.Fatal("验证后 verified_at 不应为空")
+	}
+
+	createArticle(t, s, bobToken, "bob 的文章")
+	createComment(t, s, bobToken, a.ID, "评论")
+
+	verifyEmail(t, s, s.MailToken(t, "bob@example.com")).AssertOK(t)
+	s.Do(t, http.MethodPost, "/api/v1/auth/resend-verification", nil, bobToken).AssertError(t, http.StatusBadRequest, "INVALID_INPUT")
+	s.Do(t, http.MethodPost, "/api/v1/auth/resend-verification", nil, "").AssertError(t, http.StatusUnauthorized, "UNAUTHORIZED")
+}
+
+func TestVerifyEmailRejectsBadTokens(t *testing.T) {
+	cfg := testutil.Config()
+	cfg.Account.EmailVerifyExpire = time.Nanosecond
+	s := testutil.NewServerWithConfig(t, cfg)
+	registerUnverified(t, s, "bob", "bob@example.com", "secret123")
+
+	token := s.MailToken(t, "bob@example.com")
+	verifyEmail(t, s, token).AssertError(t, http.StatusBadRequest, "INVALID_INPUT")
+	verifyEmail(t, s, "garbage").AssertError(t, http.StatusBadRequest, "INVALID_INPUT")
+}
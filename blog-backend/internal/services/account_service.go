@@ -0,0 +1,437 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"blog-backend/config"
+	"blog-backend/internal/auth"
+	"blog-backend/internal/mail"
+	"blog-backend/internal/models"
+	"blog-backend/internal/repository"
+)
+
+// 签名链接的用途
+const (
+	purposeVerifyEmail = "verify_email"
+	purposeChangeEmail = "change_email"
+)
+
+var (
+	ErrInvalidResetToken  = newError(KindInvalidInput, "重置链接无效或已过期")
+	ErrInvalidVerifyToken = newError(KindInvalidInput, "验证链接无效或已过期")
+	ErrAlreadyVerified    = newError(KindInvalidInput, "邮箱已验证")
+	ErrWrongPassword      = newError(KindInvalidInput, "当前密码错误")
+	ErrSameEmail          = newError(KindInvalidInput, "新邮箱与当前邮箱相同")
+	ErrInvalidChangeToken = newError(KindInvalidInput, "确认链接无效或已过期")
+)
+
+// 邮箱变更确认链接中签名的内容
+type emailChange struct {
+	UserID uint `json:"u"`
+	// 发起变更的会话，确认后保留
+	SessionID uint   `json:"s"`
+	From      string `json:"f"`
+	To        string `json:"t"`
+}
+
+// 邮箱验证与账号找回
+type AccountService struct {
+	users    repository.UserRepository
+	tokens   repository.UserTokenRepository
+	sessions *SessionService
+	personal *PersonalTokenService
+	throttle *LoginThrottleService
+	mailer   mail.Mailer
+	signer   *auth.Signer
+	policy   *PasswordPolicy
+	cfg      config.AccountConfig
+}
+
+func NewAccountService(users repository.UserRepository, tokens repository.UserTokenRepository, sessions *SessionService, personal *PersonalTokenService, throttle *LoginThrottleService, mailer mail.Mailer, signer *auth.Signer, policy *PasswordPolicy, cfg config.AccountConfig) *AccountService {
+	return &AccountService{users: users, tokens: tokens, sessions: sessions, personal: personal, throttle: throttle, mailer: mailer, signer: signer, policy: policy, cfg: cfg}
+}
+
+// 发送邮箱验证邮件，链接与当前邮箱绑定，邮箱变更后旧链接失效
+func (s *AccountService) SendVerification(ctx context.Context, user *models.User) error {
+	expiresAt := time.Now().Add(s.cfg.EmailVerifyExpire)
+	token := s.signer.Sign(purposeVerifyEmail, verifySubject(user), expiresAt)
+
+	return s.mailer.Send(ctx, mail.Message{
+		To:      user.Email,
+		Subject: "验证邮箱",
+		Body: fmt.Sprintf("%s，你好：\n\n请在 %s 内打开以下链接验证你的邮箱，验证后即可发布文章和评论：\n\n%s\n\n如果你没有注册过账号，请忽略本邮件。\n",
+			user.Username, s.cfg.EmailVerifyExpire, s.link("/verify-email", token)),
+	})
+}
+
+// 重新发送验证邮件
+func (s *AccountService) ResendVerification(ctx context.Context, userID uint) error {
+	user, err := s.users.FindByID(ctx, userID)
+	if err == repository.ErrNotFound {
+		return ErrUserNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if user.Verified() {
+		return ErrAlreadyVerified
+	}
+	return s.SendVerification(ctx, user)
+}
+
+// 校验验证链接并标记邮箱已验证，重复验证直接返回成功
+func (s *AccountService) VerifyEmail(ctx context.Context, token string) (*models.User, error) {
+	subject, err := s.signer.Verify(purposeVerifyEmail, token, time.Now())
+	if err != nil {
+		return nil, ErrInvalidVerifyToken
+	}
+	idPart, email, _ := strings.Cut(subject, ":")
+	id, err := strconv.ParseUint(idPart, 10, 64)
+	if err != nil {
+		return nil, ErrInvalidVerifyToken
+	}
+
+	user, err := s.users.FindByID(ctx, uint(id))
+	if err == repository.ErrNotFound {
+		return nil, ErrInvalidVerifyToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	if user.Email != email {
+		return nil, ErrInvalidVerifyToken
+	}
+	if user.Verified() {
+		return user, nil
+	}
+
+	now := time.Now()
+	user.VerifiedAt = &now
+	if err := s.users.Update(ctx, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// 发送密码重置邮件，之前发出的重置链接随即失效
+//
+// 邮箱未注册时同样返回成功，避免泄露账号是否存在。
+func (s *AccountService) ForgotPassword(ctx context.Context, email string) error {
+	user, err := s.users.FindByEmail(ctx, email)
+	if err == repository.ErrNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := s.tokens.InvalidateForUser(ctx, user.ID, models.TokenPasswordReset); err != nil {
+		return err
+	}
+	plain, hash, err := auth.NewOpaqueToken()
+	if err != nil {
+		return err
+	}
+	err = s.tokens.Create(ctx, &models.UserToken{
+		UserID:    user.ID,
+		Purpose:   models.TokenPasswordReset,
+		TokenHash: hash,
+		ExpiresAt: time.Now().Add(s.cfg.PasswordResetExpire),
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.mailer.Send(ctx, mail.Message{
+		To:      user.Email,
+		Subject: "重置密码",
+		Body: fmt.Sprintf("%s，你好：\n\n我们收到了重置你账号密码的请求，请在 %s 内打开以下链接设置新密码：\n\n%s\n\n如果这不是你本人的操作，请忽略本邮件，你的密码不会改变。\n",
+			user.Username, s.cfg.PasswordResetExpire, s.link("/reset-password", plain)),
+	})
+}
+
+// 使用重置令牌设置新密码，并撤销该用户的全部登录会话和个人访问令牌
+func (s *AccountService) ResetPassword(ctx context.Context, token, password string) error {
+	record, err := s.tokens.FindByHash(ctx, models.TokenPasswordReset, auth.HashToken(token))
+	if err == repository.ErrNotFound {
+		return ErrInvalidResetToken
+	}
+	if err != nil {
+		return err
+	}
+	if record.UsedAt != nil || time.Now().After(record.ExpiresAt) {
+		return ErrInvalidResetToken
+	}
+
+	user, err := s.users.FindByID(ctx, record.UserID)
+	if err == repository.ErrNotFound {
+		return ErrInvalidResetToken
+	}
+	if err != nil {
+		return err
+	}
+	// 新密码不符合规则时保留重置令牌，用户可以换一个密码重试
+	if err := s.policy.Validate(password, user.Username, user.Email); err != nil {
+		return err
+	}
+
+	fresh, err := s.tokens.MarkUsed(ctx, record.ID)
+	if err != nil {
+		return err
+	}
+	if !fresh {
+		return ErrInvalidResetToken
+	}
+
+	hashed, err := auth.HashPassword(password)
+	if err != nil {
+		return err
+	}
+	user.Password = hashed
+	if err := s.users.Update(ctx, user); err != nil {
+		return err
+	}
+
+	if err := s.tokens.InvalidateForUser(ctx, user.ID, models.TokenPasswordReset); err != nil {
+		return err
+	}
+	if err := s.sessions.RevokeAll(ctx, user.ID, 0); err != nil {
+		return err
+	}
+	return s.personal.RevokeAll(ctx, user.ID)
+}
+
+// 前端页面链接
+func (s *AccountService) link(path, token string) string {
+	return strings.TrimRight(s.cfg.FrontendURL, "/") + path + "?token=" + url.QueryEscape(token)
+}
+
+func verifySubject(user *models.User) string {
+	return strconv.FormatUint(uint64(user.ID), 10) + ":" + user.Email
+}
+
+// 检查用户邮箱已验证，用于发布文章和评论前
+func ensureVerified(ctx context.Context, users repository.UserRepository, userID uint) error {
+	user, err := users.FindByID(ctx, userID)
+	if err == repository.ErrNotFound {
+		return ErrUserNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if !user.Verified() {
+		return ErrEmailNotVerified
+	}
+	return nil
+}
+
+// 修改密码，需要验证当前密码，成功后撤销除当前会话外的全部会话和全部个人访问令牌并通知用户
+func (s *AccountService) ChangePassword(ctx context.Context, userID, sessionID uint, current, password string, client ClientInfo) error {
+	user, err := reauthenticate(ctx, s.users, s.throttle, userID, current, client)
+	if err != nil {
+		return err
+	}
+	if err := s.policy.Validate(password, user.Username, user.Email); err != nil {
+		return err
+	}
+
+	hashed, err := auth.HashPassword(password)
+	if err != nil {
+		return err
+	}
+	user.Password = hashed
+	if err := s.users.Update(ctx, user); err != nil {
+		return err
+	}
+	if err := s.sessions.RevokeAll(ctx, user.ID, sessionID); err != nil {
+		return err
+	}
+	if err := s.personal.RevokeAll(ctx, user.ID); err != nil {
+		return err
+	}
+
+	s.notify(ctx, mail.Message{
+		To:      user.Email,
+		Subject: "密码已修改",
+		Body: fmt.Sprintf("%s，你好：\n\n你的账号密码刚刚被修改，其他设备上的登录已全部退出，个人访问令牌已全部撤销。\n\n如果这不是你本人的操作，请立即通过“忘记密码”重置密码。\n",
+			user.Username),
+	})
+	return nil
+}
+
+// 发起邮箱变更，需要验证当前密码，确认链接发送到新邮箱
+func (s *AccountService) RequestEmailChange(ctx context.Context, userID, sessionID uint, current, newEmail string, client ClientInfo) error {
+	user, err := reauthenticate(ctx, s.users, s.throttle, userID, current, client)
+	if err != nil {
+		return err
+	}
+	if strings.EqualFold(user.Email, newEmail) {
+		return ErrSameEmail
+	}
+	exists, err := s.users.ExistsByEmail(ctx, newEmail)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return ErrUserExists
+	}
+
+	subject, err := json.Marshal(emailChange{UserID: user.ID, SessionID: sessionID, From: user.Email, To: newEmail})
+	if err != nil {
+		return err
+	}
+	token := s.signer.Sign(purposeChangeEmail, string(subject), time.Now().Add(s.cfg.EmailVerifyExpire))
+
+	return s.mailer.Send(ctx, mail.Message{
+		To:      newEmail,
+		Subject: "确认新邮箱",
+		Body: fmt.Sprintf("%s，你好：\n\n你正在将账号邮箱修改为 %s，请在 %s 内打开以下链接完成修改：\n\n%s\n\n如果这不是你本人的操作，请忽略本邮件。\n",
+			user.Username, newEmail, s.cfg.EmailVerifyExpire, s.link("/confirm-email", token)),
+	})
+}
+
+// 确认邮箱变更，撤销除发起变更的会话外的全部会话并通知原邮箱
+//
+// 链接中包含原邮箱，邮箱变更后同一链接不能再次使用。
+func (s *AccountService) ConfirmEmailChange(ctx context.Context, token string) (*models.User, error) {
+	subject, err := s.signer.Verify(purposeChangeEmail, token, time.Now())
+	if err != nil {
+		return nil, ErrInvalidChangeToken
+	}
+	var change emailChange
+	if err := json.Unmarshal([]byte(subject), &change); err != nil {
+		return nil, ErrInvalidChangeToken
+	}
+
+	user, err := s.users.FindByID(ctx, change.UserID)
+	if err == repository.ErrNotFound {
+		return nil, ErrInvalidChangeToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	if user.Email != change.From {
+		return nil, ErrInvalidChangeToken
+	}
+	// 发出链接后新邮箱可能已被其他账号注册
+	exists, err := s.users.ExistsByEmail(ctx, change.To)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, ErrUserExists
+	}
+
+	now := time.Now()
+	user.Email = change.To
+	user.VerifiedAt = &now
+	if err := s.users.Update(ctx, user); err != nil {
+		return nil, err
+	}
+	if err := s.sessions.RevokeAll(ctx, user.ID, change.SessionID); err != nil {
+		return nil, err
+	}
+
+	s.notify(ctx, mail.Message{
+		To:      change.From,
+		Subject: "账号邮箱已修改",
+		Body: fmt.Sprintf("%s，你好：\n\n你的账号邮箱已修改为 %s，本邮箱将不再接收该账号的通知。\n\n如果这不是你本人的操作，请立即联系管理员。\n",
+			user.Username, change.To),
+	})
+	return user, nil
+}
+
+// 注销账号，需要验证当前密码，按 DeletionPolicy 匿名化或删除用户的文章和评论
+//
+// 匿名化时保留用户记录作为作者，清除用户名、邮箱、头像和两步验证等个人信息。
+// 两种方式都会删除全部登录会话、个人访问令牌和关联的外部身份。
+func (s *AccountService) DeleteAccount(ctx context.Context, userID uint, password string, client ClientInfo) error {
+	user, err := reauthenticate(ctx, s.users, s.throttle, userID, password, client)
+	if err != nil {
+		return err
+	}
+	email, username := user.Email, user.Username
+
+	if s.cfg.DeletionPolicy == config.DeletionCascade {
+		err = s.users.DeleteWithContent(ctx, user.ID)
+	} else {
+		err = s.anonymize(ctx, user)
+	}
+	if err == repository.ErrNotFound {
+		return ErrUserNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	s.notify(ctx, mail.Message{
+		To:      email,
+		Subject: "账号已注销",
+		Body:    fmt.Sprintf("%s，你好：\n\n你的账号已注销，个人信息已从系统中删除。\n", username),
+	})
+	return nil
+}
+
+func (s *AccountService) anonymize(ctx context.Context, user *models.User) error {
+	// 随机密码的哈希，原密码和任何密码都无法再登录
+	password, _, err := auth.NewOpaqueToken()
+	if err != nil {
+		return err
+	}
+	hashed, err := auth.HashPassword(password)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	user.Username = models.DeletedUsername(user.ID)
+	user.Email = models.DeletedUserEmail(user.ID)
+	user.Password = hashed
+	user.Avatar = ""
+	user.Role = models.RoleReader
+	user.VerifiedAt = nil
+	user.TOTPEnabledAt = nil
+	user.TOTPSecret = ""
+	user.DeletedAt = &now
+	return s.users.Anonymize(ctx, user)
+}
+
+// 验证当前密码，用于修改密码、邮箱等敏感操作前
+//
+// 密码错误与登录失败共用限流计数，防止持有会话的攻击者借此暴力破解密码。
+func reauthenticate(ctx context.Context, users repository.UserRepository, throttle *LoginThrottleService, userID uint, password string, client ClientInfo) (*models.User, error) {
+	user, err := users.FindByID(ctx, userID)
+	if err == repository.ErrNotFound {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := throttle.Check(ctx, user.Email, client.IP); err != nil {
+		return nil, err
+	}
+	if ok, _ := auth.CheckPassword(user.Password, password); !ok {
+		if err := throttle.Fail(ctx, user.Email, client.IP); err != nil {
+			return nil, err
+		}
+		return nil, ErrWrongPassword
+	}
+	if err := throttle.Reset(ctx, user.Email); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// 发送通知邮件，操作已经完成，发送失败只记录日志
+func (s *AccountService) notify(ctx context.Context, msg mail.Message) {
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		log.Printf("发送通知邮件失败: %v", err)
+	}
+}
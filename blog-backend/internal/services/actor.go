@@ -0,0 +1,16 @@
+package services
+
+import (
+	"blog-backend/internal/auth"
+)
+
+// 发起操作的用户
+type Actor struct {
+	UserID uint
+	Role   string
+}
+
+// 是否拥有指定权限
+func (a Actor) Can(perm auth.Permission) bool {
+	return auth.Can(a.Role, perm)
+}
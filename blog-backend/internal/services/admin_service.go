@@ -0,0 +1,101 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"blog-backend/internal/models"
+	"blog-backend/internal/repository"
+)
+
+var (
+	ErrInvalidRole   = newError(KindInvalidInput, "无效的角色")
+	ErrChangeOwnRole = newError(KindInvalidInput, "不能修改自己的角色")
+)
+
+// 用户管理与审计日志
+type AdminService struct {
+	users    repository.UserRepository
+	audit    repository.AuditLogRepository
+	sessions *SessionService
+	throttle *LoginThrottleService
+}
+
+func NewAdminService(users repository.UserRepository, audit repository.AuditLogRepository, sessions *SessionService, throttle *LoginThrottleService) *AdminService {
+	return &AdminService{users: users, audit: audit, sessions: sessions, throttle: throttle}
+}
+
+func (s *AdminService) ListUsers(ctx context.Context, offset, limit int) ([]models.User, int64, error) {
+	return s.users.List(ctx, offset, limit)
+}
+
+// 修改用户角色并撤销其全部会话，使新角色立即生效
+func (s *AdminService) ChangeRole(ctx context.Context, actor Actor, id uint, role string) (*models.User, error) {
+	if !models.ValidRole(role) {
+		return nil, ErrInvalidRole
+	}
+	if id == actor.UserID {
+		return nil, ErrChangeOwnRole
+	}
+
+	user, err := s.users.FindByID(ctx, id)
+	if err == repository.ErrNotFound {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if user.Role == role {
+		return user, nil
+	}
+
+	oldRole := user.Role
+	user.Role = role
+	if err := s.users.Update(ctx, user); err != nil {
+		return nil, err
+	}
+	if err := s.sessions.RevokeAll(ctx, user.ID, 0); err != nil {
+		return nil, err
+	}
+
+	err = s.audit.Create(ctx, &models.AuditLog{
+		ActorID:    actor.UserID,
+		ActorRole:  actor.Role,
+		Action:     models.AuditUserRole,
+		TargetType: "user",
+		TargetID:   user.ID,
+		OwnerID:    user.ID,
+		Detail:     fmt.Sprintf("%s -> %s", oldRole, role),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// 解除账号的登录锁定并清空失败次数
+func (s *AdminService) UnlockUser(ctx context.Context, actor Actor, id uint) error {
+	user, err := s.users.FindByID(ctx, id)
+	if err == repository.ErrNotFound {
+		return ErrUserNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := s.throttle.Reset(ctx, user.Email); err != nil {
+		return err
+	}
+	return s.audit.Create(ctx, &models.AuditLog{
+		ActorID:    actor.UserID,
+		ActorRole:  actor.Role,
+		Action:     models.AuditUserUnlock,
+		TargetType: "user",
+		TargetID:   user.ID,
+		OwnerID:    user.ID,
+	})
+}
+
+func (s *AdminService) ListAuditLogs(ctx context.Context, offset, limit int) ([]models.AuditLog, int64, error) {
+	return s.audit.List(ctx, offset, limit)
+}
@@ -0,0 +1,420 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"blog-backend/internal/auth"
+
+	"blog-backend/internal/models"
+	"blog-backend/internal/repository"
+	"blog-backend/internal/slug"
+)
+
+var (
+	ErrInvalidArticleStatus = newError(KindInvalidInput, "无效的文章状态")
+	ErrArticleArchived      = newError(KindForbidden, "文章已归档，不能评论")
+	ErrPublishAtInPast      = newError(KindInvalidInput, "定时发布时间必须晚于当前时间")
+	ErrScheduleNotDraft     = newError(KindInvalidInput, "只有草稿可以定时发布")
+)
+
+type CreateArticleParams struct {
+	Title   string
+	Content string
+	// 草稿或已发布，为空时直接发布
+	Status string
+	// 定时发布时间，设置后文章保存为草稿，到期后自动发布
+	PublishAt *time.Time
+	// 标签名称，不存在的标签自动创建
+	Tags []string
+	// 所属分类，为空或 0 时不分类
+	CategoryID *uint
+}
+
+type UpdateArticleParams struct {
+	Title   string
+	Content string
+	// 修改草稿的定时发布时间
+	PublishAt *time.Time
+	// 为 nil 时保持不变，为空列表时清除全部标签
+	Tags []string
+	// 为空时保持不变，为 0 时取消分类
+	CategoryID *uint
+}
+
+// 文章列表的查询条件
+type ArticleQuery struct {
+	// 为空时只返回已发布的文章
+	Status string
+	// 标签 slug
+	Tag string
+	// 分类 slug，包括其子分类中的文章
+	Category string
+}
+
+// 文章业务规则
+type ArticleService struct {
+	articles   repository.ArticleRepository
+	tags       *TagService
+	categories *CategoryService
+	users      repository.UserRepository
+	audit      repository.AuditLogRepository
+}
+
+func NewArticleService(articles repository.ArticleRepository, tags *TagService, categories *CategoryService, users repository.UserRepository, audit repository.AuditLogRepository) *ArticleService {
+	return &ArticleService{articles: articles, tags: tags, categories: categories, users: users, audit: audit}
+}
+
+// 按状态、标签和分类查询文章列表，标签或分类不存在时返回空列表
+//
+// 查询草稿需要登录，编辑和管理员可以看到全部草稿，其他用户只能看到自己的草稿。
+// viewer 为零值表示未登录。
+func (s *ArticleService) List(ctx context.Context, viewer Actor, query ArticleQuery, offset, limit int) ([]models.Article, int64, error) {
+	filter := repository.ArticleFilter{Status: query.Status}
+	if query.Status == "" {
+		filter.Status = models.ArticlePublished
+	}
+	if !models.ValidArticleStatus(filter.Status) {
+		return nil, 0, ErrInvalidArticleStatus
+	}
+	if filter.Status == models.ArticleDraft {
+		if viewer.UserID == 0 {
+			return nil, 0, ErrLoginRequired
+		}
+		if !viewer.Can(auth.PermEditAnyArticle) {
+			filter.AuthorID = viewer.UserID
+		}
+	}
+
+	if query.Tag != "" {
+		tag, err := s.tags.GetBySlug(ctx, query.Tag)
+		if err == ErrTagNotFound {
+			return []models.Article{}, 0, nil
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+		filter.TagID = tag.ID
+	}
+	if query.Category != "" {
+		ids, err := s.categories.Subtree(ctx, query.Category)
+		if err == ErrCategoryNotFound {
+			return []models.Article{}, 0, nil
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+		filter.CategoryIDs = ids
+	}
+	return s.articles.List(ctx, filter, offset, limit)
+}
+
+// 查询作者本人的草稿
+func (s *ArticleService) ListDrafts(ctx context.Context, authorID uint, offset, limit int) ([]models.Article, int64, error) {
+	return s.articles.List(ctx, repository.ArticleFilter{Status: models.ArticleDraft, AuthorID: authorID}, offset, limit)
+}
+
+// 查询文章，不存在时返回 ErrArticleNotFound
+func (s *ArticleService) Get(ctx context.Context, id uint) (*models.Article, error) {
+	article, err := s.articles.FindByID(ctx, id)
+	if err == repository.ErrNotFound {
+		return nil, ErrArticleNotFound
+	}
+	return article, err
+}
+
+// 查询 viewer 可以看到的文章，无权查看的草稿同样返回 ErrArticleNotFound
+func (s *ArticleService) GetVisible(ctx context.Context, viewer Actor, id uint) (*models.Article, error) {
+	article, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !visibleTo(article, viewer) {
+		return nil, ErrArticleNotFound
+	}
+	return article, nil
+}
+
+// 查看文章详情并增加浏览量，预览草稿不计入浏览量
+func (s *ArticleService) View(ctx context.Context, viewer Actor, id uint) (*models.Article, error) {
+	article, err := s.GetVisible(ctx, viewer, id)
+	if err != nil {
+		return nil, err
+	}
+	if article.Draft() {
+		return article, nil
+	}
+
+	if err := s.articles.IncrementViews(ctx, id); err != nil {
+		return nil, err
+	}
+	article.Views++
+	return article, nil
+}
+
+// 按 slug 查看文章并增加浏览量
+//
+// slug 为文章以前使用过的 slug 时不增加浏览量，moved 为 true，调用方应重定向到 article.Slug。
+func (s *ArticleService) ViewBySlug(ctx context.Context, viewer Actor, articleSlug string) (article *models.Article, moved bool, err error) {
+	article, err = s.articles.FindBySlug(ctx, articleSlug)
+	if err == repository.ErrNotFound {
+		old, err := s.articles.FindOldSlug(ctx, articleSlug)
+		if err == repository.ErrNotFound {
+			return nil, false, ErrArticleNotFound
+		}
+		if err != nil {
+			return nil, false, err
+		}
+		article, err = s.GetVisible(ctx, viewer, old.ArticleID)
+		return article, true, err
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	article, err = s.View(ctx, viewer, article.ID)
+	return article, false, err
+}
+
+// 创建文章，作者邮箱必须已验证
+func (s *ArticleService) Create(ctx context.Context, authorID uint, params CreateArticleParams) (*models.Article, error) {
+	status := params.Status
+	if status == "" {
+		status = models.ArticlePublished
+		if params.PublishAt != nil {
+			status = models.ArticleDraft
+		}
+	}
+	if status != models.ArticleDraft && status != models.ArticlePublished {
+		return nil, ErrInvalidArticleStatus
+	}
+	publishAt, err := normalizePublishAt(params.PublishAt)
+	if err != nil {
+		return nil, err
+	}
+	if publishAt != nil && status != models.ArticleDraft {
+		return nil, ErrScheduleNotDraft
+	}
+	if err := ensureVerified(ctx, s.users, authorID); err != nil {
+		return nil, err
+	}
+	tags, err := s.tags.Resolve(ctx, params.Tags)
+	if err != nil {
+		return nil, err
+	}
+	category, err := s.category(ctx, params.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	articleSlug, err := s.uniqueSlug(ctx, params.Title, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	article := &models.Article{
+		Slug:      articleSlug,
+		Title:     params.Title,
+		Content:   params.Content,
+		AuthorID:  authorID,
+		Status:    status,
+		PublishAt: publishAt,
+		Tags:      tags,
+		Category:  category,
+	}
+	if category != nil {
+		article.CategoryID = &category.ID
+	}
+	if status == models.ArticlePublished {
+		now := time.Now()
+		article.PublishedAt = &now
+	}
+	if err := s.articles.Create(ctx, article); err != nil {
+		return nil, err
+	}
+	return article, nil
+}
+
+// 更新文章，作者本人或编辑、管理员可操作，空字段保持不变
+func (s *ArticleService) Update(ctx context.Context, actor Actor, id uint, params UpdateArticleParams) (*models.Article, error) {
+	article, override, err := s.getOwned(ctx, actor, id, auth.PermEditAnyArticle)
+	if err != nil {
+		return nil, err
+	}
+	oldTitle, oldSlug := article.Title, article.Slug
+
+	if params.Title != "" && params.Title != oldTitle {
+		article.Title = params.Title
+		if article.Slug, err = s.uniqueSlug(ctx, params.Title, article.ID); err != nil {
+			return nil, err
+		}
+	}
+	if params.Content != "" {
+		article.Content = params.Content
+	}
+	if params.PublishAt != nil {
+		if !article.Draft() {
+			return nil, ErrScheduleNotDraft
+		}
+		if article.PublishAt, err = normalizePublishAt(params.PublishAt); err != nil {
+			return nil, err
+		}
+	}
+	if params.Tags != nil {
+		if article.Tags, err = s.tags.Resolve(ctx, params.Tags); err != nil {
+			return nil, err
+		}
+	}
+	if params.CategoryID != nil {
+		if article.Category, err = s.category(ctx, params.CategoryID); err != nil {
+			return nil, err
+		}
+		article.CategoryID = nil
+		if article.Category != nil {
+			article.CategoryID = &article.Category.ID
+		}
+	}
+
+	if article.Slug != oldSlug {
+		err = s.articles.Rename(ctx, article, oldSlug)
+	} else {
+		err = s.articles.Update(ctx, article)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if override {
+		detail := fmt.Sprintf("修改文章《%s》", oldTitle)
+		if err := s.record(ctx, actor, models.AuditArticleUpdate, article, detail); err != nil {
+			return nil, err
+		}
+	}
+	return article, nil
+}
+
+// 发布文章，作者本人或编辑、管理员可操作，首次发布时记录发布时间
+func (s *ArticleService) Publish(ctx context.Context, actor Actor, id uint) (*models.Article, error) {
+	return s.setStatus(ctx, actor, id, models.ArticlePublished)
+}
+
+// 撤回为草稿，同时取消定时发布
+func (s *ArticleService) Unpublish(ctx context.Context, actor Actor, id uint) (*models.Article, error) {
+	return s.setStatus(ctx, actor, id, models.ArticleDraft)
+}
+
+// 归档文章，归档后不再出现在文章列表中，也不能再评论
+func (s *ArticleService) Archive(ctx context.Context, actor Actor, id uint) (*models.Article, error) {
+	return s.setStatus(ctx, actor, id, models.ArticleArchived)
+}
+
+// 修改文章状态并取消定时发布，状态未变化且没有定时发布时直接返回
+func (s *ArticleService) setStatus(ctx context.Context, actor Actor, id uint, status string) (*models.Article, error) {
+	article, override, err := s.getOwned(ctx, actor, id, auth.PermEditAnyArticle)
+	if err != nil {
+		return nil, err
+	}
+	if article.Status == status && article.PublishAt == nil {
+		return article, nil
+	}
+	oldStatus := article.Status
+
+	article.Status = status
+	article.PublishAt = nil
+	if status == models.ArticlePublished && article.PublishedAt == nil {
+		now := time.Now()
+		article.PublishedAt = &now
+	}
+	if err := s.articles.Update(ctx, article); err != nil {
+		return nil, err
+	}
+	if override {
+		detail := fmt.Sprintf("文章《%s》状态 %s -> %s", article.Title, oldStatus, status)
+		if err := s.record(ctx, actor, models.AuditArticleStatus, article, detail); err != nil {
+			return nil, err
+		}
+	}
+	return article, nil
+}
+
+// 删除文章，作者本人或管理员可操作
+func (s *ArticleService) Delete(ctx context.Context, actor Actor, id uint) error {
+	article, override, err := s.getOwned(ctx, actor, id, auth.PermDeleteAnyArticle)
+	if err != nil {
+		return err
+	}
+	if err := s.articles.Delete(ctx, id); err != nil {
+		return err
+	}
+	if override {
+		detail := fmt.Sprintf("删除文章《%s》", article.Title)
+		return s.record(ctx, actor, models.AuditArticleDelete, article, detail)
+	}
+	return nil
+}
+
+// 查询文章并检查操作权限，非作者本人时需要 perm 权限，override 表示越过了所有权检查
+func (s *ArticleService) getOwned(ctx context.Context, actor Actor, id uint, perm auth.Permission) (article *models.Article, override bool, err error) {
+	article, err = s.GetVisible(ctx, actor, id)
+	if err != nil {
+		return nil, false, err
+	}
+	if article.AuthorID == actor.UserID {
+		return article, false, nil
+	}
+	if !actor.Can(perm) {
+		return nil, false, ErrForbidden
+	}
+	return article, true, nil
+}
+
+// 查询文章的分类，id 为空或 0 时返回 nil，分类不存在时返回 ErrUnknownCategory
+func (s *ArticleService) category(ctx context.Context, id *uint) (*models.Category, error) {
+	if id == nil || *id == 0 {
+		return nil, nil
+	}
+	category, err := s.categories.Get(ctx, *id)
+	if err == ErrCategoryNotFound {
+		return nil, ErrUnknownCategory
+	}
+	return category, err
+}
+
+// 由标题生成未被其他文章占用的 slug，articleID 为正在修改的文章，新建时为 0
+func (s *ArticleService) uniqueSlug(ctx context.Context, title string, articleID uint) (string, error) {
+	return slug.Unique(slug.Make(title), func(candidate string) (bool, error) {
+		return s.articles.SlugTaken(ctx, candidate, articleID)
+	})
+}
+
+// 校验定时发布时间并转换为本地时区，与其他时间字段保持一致 (SQLite 按字符串比较时间)
+func normalizePublishAt(at *time.Time) (*time.Time, error) {
+	if at == nil {
+		return nil, nil
+	}
+	if !at.After(time.Now()) {
+		return nil, ErrPublishAtInPast
+	}
+	local := at.Local()
+	return &local, nil
+}
+
+// 草稿只有作者本人和可以修改任意文章的编辑、管理员可见
+func visibleTo(article *models.Article, viewer Actor) bool {
+	if !article.Draft() {
+		return true
+	}
+	return viewer.UserID != 0 && (article.AuthorID == viewer.UserID || viewer.Can(auth.PermEditAnyArticle))
+}
+
+// 记录越权操作
+func (s *ArticleService) record(ctx context.Context, actor Actor, action string, article *models.Article, detail string) error {
+	return s.audit.Create(ctx, &models.AuditLog{
+		ActorID:    actor.UserID,
+		ActorRole:  actor.Role,
+		Action:     action,
+		TargetType: "article",
+		TargetID:   article.ID,
+		OwnerID:    article.AuthorID,
+		Detail:     detail,
+	})
+}
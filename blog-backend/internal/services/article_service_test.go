@@ -0,0 +1,184 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"blog-backend/internal/models"
+	"blog-backend/internal/services"
+)
+
+var (
+	alice  = services.Actor{UserID: 1, Role: models.RoleAuthor}
+	bob    = services.Actor{UserID: 2, Role: models.RoleAuthor}
+	editor = services.Actor{UserID: 3, Role: models.RoleEditor}
+	admin  = services.Actor{UserID: 4, Role: models.RoleAdmin}
+	// 未登录
+	anonymous = services.Actor{}
+)
+
+// alice 的已发布文章 #1 和草稿 #2
+func newArticleService(t *testing.T) (*services.ArticleService, *fakeArticles, *fakeAuditLogs) {
+	t.Helper()
+	articles := newFakeArticles(
+		models.Article{ID: 1, Title: "已发布", Slug: "published", AuthorID: alice.UserID, Status: models.ArticlePublished, Views: 10},
+		models.Article{ID: 2, Title: "草稿", Slug: "draft", AuthorID: alice.UserID, Status: models.ArticleDraft},
+	)
+	audit := &fakeAuditLogs{}
+	// 测试不涉及标签和分类
+	return services.NewArticleService(articles, nil, nil, newFakeUsers(), audit), articles, audit
+}
+
+func TestArticleUpdateOwnership(t *testing.T) {
+	ctx := context.Background()
+	s, articles, audit := newArticleService(t)
+
+	if _, err := s.Update(ctx, bob, 1, services.UpdateArticleParams{Content: "bob"}); err != services.ErrForbidden {
+		t.Fatalf("其他作者修改应返回 ErrForbidden，实际 %v", err)
+	}
+	if got := articles.get(1).Content; got != "" {
+		t.Fatalf("被拒绝的修改不应写入，实际内容 %q", got)
+	}
+
+	if _, err := s.Update(ctx, alice, 1, services.UpdateArticleParams{Content: "alice"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(audit.all()) != 0 {
+		t.Fatal("作者本人的修改不应记录审计日志")
+	}
+
+	if _, err := s.Update(ctx, editor, 1, services.UpdateArticleParams{Content: "editor"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := articles.get(1).Content; got != "editor" {
+		t.Fatalf("编辑的修改未写入，实际内容 %q", got)
+	}
+	logs := audit.all()
+	if len(logs) != 1 {
+		t.Fatalf("期望 1 条审计日志，实际 %d", len(logs))
+	}
+	if l := logs[0]; l.ActorID != editor.UserID || l.Action != models.AuditArticleUpdate || l.TargetID != 1 || l.OwnerID != alice.UserID {
+		t.Fatalf("审计日志不符: %+v", l)
+	}
+}
+
+func TestArticleDeleteOwnership(t *testing.T) {
+	ctx := context.Background()
+	s, articles, audit := newArticleService(t)
+
+	// 编辑可以修改但不能删除他人的文章
+	for _, actor := range []services.Actor{bob, editor} {
+		if err := s.Delete(ctx, actor, 1); err != services.ErrForbidden {
+			t.Fatalf("%s 删除他人文章应返回 ErrForbidden，实际 %v", actor.Role, err)
+		}
+	}
+	if articles.get(1).ID == 0 {
+		t.Fatal("被拒绝的删除不应生效")
+	}
+
+	if err := s.Delete(ctx, admin, 1); err != nil {
+		t.Fatal(err)
+	}
+	if articles.get(1).ID != 0 {
+		t.Fatal("管理员删除后文章仍存在")
+	}
+	logs := audit.all()
+	if len(logs) != 1 || logs[0].Action != models.AuditArticleDelete || logs[0].OwnerID != alice.UserID {
+		t.Fatalf("审计日志不符: %+v", logs)
+	}
+
+	if err := s.Delete(ctx, alice, 2); err != nil {
+		t.Fatal(err)
+	}
+	if len(audit.all()) != 1 {
+		t.Fatal("作者本人的删除不应记录审计日志")
+	}
+}
+
+// 无权查看的草稿按不存在处理，不暴露草稿是否存在
+func TestArticleDraftOwnership(t *testing.T) {
+	ctx := context.Background()
+	s, _, _ := newArticleService(t)
+
+	if _, err := s.Update(ctx, bob, 2, services.UpdateArticleParams{Content: "bob"}); err != services.ErrArticleNotFound {
+		t.Fatalf("修改他人草稿应返回 ErrArticleNotFound，实际 %v", err)
+	}
+	if _, err := s.Publish(ctx, bob, 2); err != services.ErrArticleNotFound {
+		t.Fatalf("发布他人草稿应返回 ErrArticleNotFound，实际 %v", err)
+	}
+	if _, err := s.Publish(ctx, editor, 2); err != nil {
+		t.Fatalf("编辑应可以发布他人草稿: %v", err)
+	}
+	if err := s.Delete(ctx, bob, 404); err != services.ErrArticleNotFound {
+		t.Fatalf("删除不存在的文章应返回 ErrArticleNotFound，实际 %v", err)
+	}
+}
+
+func TestArticleViewCount(t *testing.T) {
+	ctx := context.Background()
+	s, articles, _ := newArticleService(t)
+
+	article, err := s.View(ctx, anonymous, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if article.Views != 11 || articles.get(1).Views != 11 {
+		t.Fatalf("查看已发布文章应增加浏览量，返回 %d，保存 %d", article.Views, articles.get(1).Views)
+	}
+
+	// 作者预览草稿不计入浏览量
+	article, err = s.View(ctx, alice, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if article.Views != 0 || articles.get(2).Views != 0 {
+		t.Fatalf("预览草稿不应增加浏览量，返回 %d，保存 %d", article.Views, articles.get(2).Views)
+	}
+
+	// 无权查看的草稿不计入浏览量
+	for _, viewer := range []services.Actor{anonymous, bob} {
+		if _, err := s.View(ctx, viewer, 2); err != services.ErrArticleNotFound {
+			t.Fatalf("无权查看的草稿应返回 ErrArticleNotFound，实际 %v", err)
+		}
+	}
+	if got := articles.get(2).Views; got != 0 {
+		t.Fatalf("无权查看的草稿浏览量应为 0，实际 %d", got)
+	}
+}
+
+func TestArticleViewBySlugCount(t *testing.T) {
+	ctx := context.Background()
+	s, articles, _ := newArticleService(t)
+
+	if _, err := s.Update(ctx, alice, 1, services.UpdateArticleParams{Title: "新标题"}); err != nil {
+		t.Fatal(err)
+	}
+	current := articles.get(1).Slug
+	if current == "published" {
+		t.Fatal("修改标题后 slug 应随之变化")
+	}
+
+	article, moved, err := s.ViewBySlug(ctx, anonymous, current)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if moved || article.Views != 11 {
+		t.Fatalf("按当前 slug 查看应增加浏览量，moved=%v views=%d", moved, article.Views)
+	}
+
+	// 旧 slug 只用于重定向，不计入浏览量
+	article, moved, err = s.ViewBySlug(ctx, anonymous, "published")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !moved || article.Slug != current {
+		t.Fatalf("旧 slug 应重定向到 %q，moved=%v slug=%q", current, moved, article.Slug)
+	}
+	if got := articles.get(1).Views; got != 11 {
+		t.Fatalf("按旧 slug 查看不应增加浏览量，实际 %d", got)
+	}
+
+	if _, _, err := s.ViewBySlug(ctx, anonymous, "missing"); err != services.ErrArticleNotFound {
+		t.Fatalf("不存在的 slug 应返回 ErrArticleNotFound，实际 %v", err)
+	}
+}
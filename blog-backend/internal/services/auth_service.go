@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"log"
+
+	"blog-backend/internal/auth"
+	"blog-backend/internal/models"
+	"blog-backend/internal/repository"
+)
+
+type RegisterParams struct {
+	Username string
+	Email    string
+	Password string
+}
+
+// 登录结果，开启两步验证的用户只返回 Challenge，完成验证后才签发令牌
+type LoginResult struct {
+	User      *models.User
+	Tokens    *TokenPair
+	Challenge *TwoFactorChallenge
+}
+
+// 注册与登录
+type AuthService struct {
+	users     repository.UserRepository
+	sessions  *SessionService
+	accounts  *AccountService
+	twoFactor *TwoFactorService
+	throttle  *LoginThrottleService
+	policy    *PasswordPolicy
+}
+
+func NewAuthService(users repository.UserRepository, sessions *SessionService, accounts *AccountService, twoFactor *TwoFactorService, throttle *LoginThrottleService, policy *PasswordPolicy) *AuthService {
+	return &AuthService{users: users, sessions: sessions, accounts: accounts, twoFactor: twoFactor, throttle: throttle, policy: policy}
+}
+
+// 注册新用户，发送验证邮件并创建登录会话
+func (s *AuthService) Register(ctx context.Context, params RegisterParams, client ClientInfo) (*models.User, *TokenPair, error) {
+	if models.ReservedUsername(params.Username) {
+		return nil, nil, ErrUsernameReserved
+	}
+	if err := s.policy.Validate(params.Password, params.Username, params.Email); err != nil {
+		return nil, nil, err
+	}
+
+	// 检查用户是否已存在
+	exists, err := s.users.ExistsByEmailOrUsername(ctx, params.Email, params.Username)
+	if err != nil {
+		return nil, nil, err
+	}
+	if exists {
+		return nil, nil, ErrUserExists
+	}
+
+	hashedPassword, err := auth.HashPassword(params.Password)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	user := &models.User{
+		Username: params.Username,
+		Email:    params.Email,
+		Password: hashedPassword,
+		Role:     models.RoleAuthor,
+	}
+	if err := s.users.Create(ctx, user); err != nil {
+		return nil, nil, err
+	}
+
+	// 用户已创建，邮件发送失败时可以通过重发接口再次发送
+	if err := s.accounts.SendVerification(ctx, user); err != nil {
+		log.Printf("发送验证邮件失败: %v", err)
+	}
+
+	tokens, err := s.sessions.Start(ctx, user, client)
+	if err != nil {
+		return nil, nil, err
+	}
+	return user, tokens, nil
+}
+
+// 校验邮箱和密码并创建登录会话，开启两步验证时返回登录挑战
+//
+// 账号或 IP 失败次数过多时在校验密码前直接拒绝。
+func (s *AuthService) Login(ctx context.Context, email, password string, client ClientInfo) (*LoginResult, error) {
+	if err := s.throttle.Check(ctx, email, client.IP); err != nil {
+		return nil, err
+	}
+
+	user, err := s.users.FindByEmail(ctx, email)
+	if err == repository.ErrNotFound {
+		return nil, s.loginFailed(ctx, email, client)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ok, rehash := auth.CheckPassword(user.Password, password)
+	if !ok {
+		return nil, s.loginFailed(ctx, email, client)
+	}
+	if rehash {
+		s.rehashPassword(ctx, user, password)
+	}
+
+	// 两步验证完成后才清除失败记录，避免通过反复登录重置验证码的尝试次数
+	if user.TwoFactorEnabled() {
+		return &LoginResult{User: user, Challenge: s.twoFactor.Challenge(user)}, nil
+	}
+	if err := s.throttle.Reset(ctx, email); err != nil {
+		return nil, err
+	}
+
+	tokens, err := s.sessions.Start(ctx, user, client)
+	if err != nil {
+		return nil, err
+	}
+	return &LoginResult{User: user, Tokens: tokens}, nil
+}
+
+// 将旧算法或旧参数的密码哈希升级为当前算法，失败时只记录日志，下次登录再试
+func (s *AuthService) rehashPassword(ctx context.Context, user *models.User, password string) {
+	hashed, err := auth.HashPassword(password)
+	if err == nil {
+		user.Password = hashed
+		err = s.users.Update(ctx, user)
+	}
+	if err != nil {
+		log.Printf("升级用户 %d 的密码哈希失败: %v", user.ID, err)
+	}
+}
+
+// 记录登录失败并返回凭证错误
+func (s *AuthService) loginFailed(ctx context.Context, email string, client ClientInfo) error {
+	if err := s.throttle.Fail(ctx, email, client.IP); err != nil {
+		return err
+	}
+	return ErrInvalidCredentials
+}
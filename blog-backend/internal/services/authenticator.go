@@ -0,0 +1,24 @@
+package services
+
+import (
+	"context"
+
+	"blog-backend/internal/auth"
+)
+
+// 访问令牌认证，按前缀区分个人访问令牌和 JWT
+type Authenticator struct {
+	sessions *SessionService
+	tokens   *PersonalTokenService
+}
+
+func NewAuthenticator(sessions *SessionService, tokens *PersonalTokenService) *Authenticator {
+	return &Authenticator{sessions: sessions, tokens: tokens}
+}
+
+func (a *Authenticator) Authenticate(ctx context.Context, token string) (*auth.Claims, error) {
+	if auth.IsPersonalToken(token) {
+		return a.tokens.Authenticate(ctx, token)
+	}
+	return a.sessions.Authenticate(ctx, token)
+}
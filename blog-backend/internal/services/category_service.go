@@ -0,0 +1,280 @@
+package services
+
+import (
+	"context"
+	"unicode/utf8"
+
+	"blog-backend/internal/models"
+	"blog-backend/internal/repository"
+	"blog-backend/internal/slug"
+)
+
+var (
+	ErrInvalidCategoryName = newError(KindInvalidInput, "分类名称不能为空且不能超过50个字符")
+	ErrDescriptionTooLong  = newError(KindInvalidInput, "分类描述不能超过500个字符")
+	ErrInvalidSlug         = newError(KindInvalidInput, "slug 只能包含小写字母、数字和连字符，且不能超过80个字符")
+	ErrInvalidParent       = newError(KindInvalidInput, "上级分类不能是分类本身或其子分类")
+	ErrUnknownCategory     = newError(KindInvalidInput, "分类不存在")
+	ErrCategoryNotFound    = newError(KindNotFound, "分类不存在")
+	ErrCategoryExists      = newError(KindConflict, "分类 slug 已存在")
+	ErrCategoryHasChildren = newError(KindConflict, "请先删除或移动子分类")
+)
+
+type CreateCategoryParams struct {
+	Name string
+	// 为空时由名称生成
+	Slug        string
+	Description string
+	// 上级分类，为空时为顶级分类
+	ParentID *uint
+}
+
+type UpdateCategoryParams struct {
+	Name        string
+	Slug        string
+	Description string
+	// 为空时保持不变，为 0 时移动为顶级分类
+	ParentID *uint
+}
+
+// 分类树的节点，ArticleCount 包括子分类中已发布的文章
+type CategoryNode struct {
+	models.Category
+	ArticleCount int64           `json:"article_count"`
+	Children     []*CategoryNode `json:"children"`
+}
+
+// 分类管理
+type CategoryService struct {
+	categories repository.CategoryRepository
+	articles   repository.ArticleRepository
+}
+
+func NewCategoryService(categories repository.CategoryRepository, articles repository.ArticleRepository) *CategoryService {
+	return &CategoryService{categories: categories, articles: articles}
+}
+
+// 完整的分类树，同级分类按名称排列
+func (s *CategoryService) Tree(ctx context.Context) ([]*CategoryNode, error) {
+	categories, err := s.categories.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	counts, err := s.articles.CountByCategory(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := make(map[uint]*CategoryNode, len(categories))
+	for _, c := range categories {
+		nodes[c.ID] = &CategoryNode{Category: c, ArticleCount: counts[c.ID], Children: []*CategoryNode{}}
+	}
+	roots := []*CategoryNode{}
+	for _, c := range categories {
+		node := nodes[c.ID]
+		if parent, ok := nodes[parentOf(c)]; ok {
+			parent.Children = append(parent.Children, node)
+		} else {
+			roots = append(roots, node)
+		}
+	}
+
+	var sum func(*CategoryNode) int64
+	sum = func(node *CategoryNode) int64 {
+		for _, child := range node.Children {
+			node.ArticleCount += sum(child)
+		}
+		return node.ArticleCount
+	}
+	for _, root := range roots {
+		sum(root)
+	}
+	return roots, nil
+}
+
+// 查询分类，不存在时返回 ErrCategoryNotFound
+func (s *CategoryService) Get(ctx context.Context, id uint) (*models.Category, error) {
+	category, err := s.categories.FindByID(ctx, id)
+	if err == repository.ErrNotFound {
+		return nil, ErrCategoryNotFound
+	}
+	return category, err
+}
+
+// 按 slug 查询分类及其全部子分类的ID
+func (s *CategoryService) Subtree(ctx context.Context, categorySlug string) ([]uint, error) {
+	root, err := s.categories.FindBySlug(ctx, categorySlug)
+	if err == repository.ErrNotFound {
+		return nil, ErrCategoryNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	categories, err := s.categories.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return descendants(categories, root.ID), nil
+}
+
+func (s *CategoryService) Create(ctx context.Context, params CreateCategoryParams) (*models.Category, error) {
+	if err := validateCategory(params.Name, params.Description); err != nil {
+		return nil, err
+	}
+	category := &models.Category{Name: params.Name, Description: params.Description}
+	if params.ParentID != nil && *params.ParentID != 0 {
+		if _, err := s.parent(ctx, *params.ParentID); err != nil {
+			return nil, err
+		}
+		category.ParentID = params.ParentID
+	}
+
+	var err error
+	if category.Slug, err = s.slugFor(ctx, params.Name, params.Slug, 0); err != nil {
+		return nil, err
+	}
+	if err := s.categories.Create(ctx, category); err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+// 修改分类，空字段保持不变；修改名称不会改变 slug
+func (s *CategoryService) Update(ctx context.Context, id uint, params UpdateCategoryParams) (*models.Category, error) {
+	category, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if params.Name != "" {
+		category.Name = params.Name
+	}
+	if params.Description != "" {
+		category.Description = params.Description
+	}
+	if err := validateCategory(category.Name, category.Description); err != nil {
+		return nil, err
+	}
+	if params.Slug != "" && params.Slug != category.Slug {
+		if category.Slug, err = s.slugFor(ctx, category.Name, params.Slug, category.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	if params.ParentID != nil {
+		if *params.ParentID == 0 {
+			category.ParentID = nil
+		} else {
+			if _, err := s.parent(ctx, *params.ParentID); err != nil {
+				return nil, err
+			}
+			categories, err := s.categories.List(ctx)
+			if err != nil {
+				return nil, err
+			}
+			for _, d := range descendants(categories, category.ID) {
+				if d == *params.ParentID {
+					return nil, ErrInvalidParent
+				}
+			}
+			category.ParentID = params.ParentID
+		}
+	}
+
+	if err := s.categories.Update(ctx, category); err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+// 删除没有子分类的分类，其中的文章变为未分类
+func (s *CategoryService) Delete(ctx context.Context, id uint) error {
+	categories, err := s.categories.List(ctx)
+	if err != nil {
+		return err
+	}
+	found := false
+	for _, c := range categories {
+		if c.ID == id {
+			found = true
+		}
+		if parentOf(c) == id {
+			return ErrCategoryHasChildren
+		}
+	}
+	if !found {
+		return ErrCategoryNotFound
+	}
+
+	err = s.categories.Delete(ctx, id)
+	if err == repository.ErrNotFound {
+		return ErrCategoryNotFound
+	}
+	return err
+}
+
+// 查询上级分类，不存在时返回 ErrUnknownCategory
+func (s *CategoryService) parent(ctx context.Context, id uint) (*models.Category, error) {
+	category, err := s.categories.FindByID(ctx, id)
+	if err == repository.ErrNotFound {
+		return nil, ErrUnknownCategory
+	}
+	return category, err
+}
+
+// 校验指定的 slug，未指定时由名称生成未被占用的 slug
+func (s *CategoryService) slugFor(ctx context.Context, name, requested string, id uint) (string, error) {
+	taken := func(candidate string) (bool, error) {
+		return s.categories.SlugTaken(ctx, candidate, id)
+	}
+	if requested == "" {
+		base := slug.Make(name)
+		if base == "" {
+			base = "category"
+		}
+		return slug.Unique(base, taken)
+	}
+
+	// 合法的 slug 经过转换后保持不变
+	if slug.Make(requested) != requested {
+		return "", ErrInvalidSlug
+	}
+	used, err := taken(requested)
+	if err != nil {
+		return "", err
+	}
+	if used {
+		return "", ErrCategoryExists
+	}
+	return requested, nil
+}
+
+func validateCategory(name, description string) error {
+	if name == "" || utf8.RuneCountInString(name) > 50 {
+		return ErrInvalidCategoryName
+	}
+	if utf8.RuneCountInString(description) > 500 {
+		return ErrDescriptionTooLong
+	}
+	return nil
+}
+
+// 上级分类ID，顶级分类为 0
+func parentOf(c models.Category) uint {
+	if c.ParentID == nil {
+		return 0
+	}
+	return *c.ParentID
+}
+
+// rootID 及其全部子分类的ID
+func descendants(categories []models.Category, rootID uint) []uint {
+	children := make(map[uint][]uint)
+	for _, c := range categories {
+		children[parentOf(c)] = append(children[parentOf(c)], c.ID)
+	}
+	ids := []uint{rootID}
+	for i := 0; i < len(ids); i++ {
+		ids = append(ids, children[ids[i]]...)
+	}
+	return ids
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+
+	"blog-backend/internal/auth"
+
+	"blog-backend/internal/models"
+	"blog-backend/internal/repository"
+)
+
+// 评论业务规则
+type CommentService struct {
+	comments repository.CommentRepository
+	articles repository.ArticleRepository
+	users    repository.UserRepository
+	audit    repository.AuditLogRepository
+}
+
+func NewCommentService(comments repository.CommentRepository, articles repository.ArticleRepository, users repository.UserRepository, audit repository.AuditLogRepository) *CommentService {
+	return &CommentService{comments: comments, articles: articles, users: users, audit: audit}
+}
+
+// 查询文章评论，viewer 无权查看的草稿返回 ErrArticleNotFound
+func (s *CommentService) ListByArticle(ctx context.Context, viewer Actor, articleID uint, offset, limit int) ([]models.Comment, int64, error) {
+	article, err := s.findArticle(ctx, articleID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if !visibleTo(article, viewer) {
+		return nil, 0, ErrArticleNotFound
+	}
+	return s.comments.ListByArticle(ctx, articleID, offset, limit)
+}
+
+// 发表评论，文章必须已发布且评论者邮箱已验证
+func (s *CommentService) Create(ctx context.Context, authorID, articleID uint, content string) (*models.Comment, error) {
+	article, err := s.findArticle(ctx, articleID)
+	if err != nil {
+		return nil, err
+	}
+	switch article.Status {
+	case models.ArticleDraft:
+		return nil, ErrArticleNotFound
+	case models.ArticleArchived:
+		return nil, ErrArticleArchived
+	}
+	if err := ensureVerified(ctx, s.users, authorID); err != nil {
+		return nil, err
+	}
+
+	comment := &models.Comment{
+		Content:   content,
+		ArticleID: articleID,
+		AuthorID:  authorID,
+	}
+	if err := s.comments.Create(ctx, comment); err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+// 删除评论，评论作者、文章作者或管理员可操作
+func (s *CommentService) Delete(ctx context.Context, actor Actor, id uint) error {
+	comment, err := s.comments.FindByID(ctx, id)
+	if err == repository.ErrNotFound {
+		return ErrCommentNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	override := false
+	if comment.AuthorID != actor.UserID {
+		article, err := s.findArticle(ctx, comment.ArticleID)
+		if err != nil && err != ErrArticleNotFound {
+			return err
+		}
+		if article == nil || article.AuthorID != actor.UserID {
+			if !actor.Can(auth.PermDeleteAnyComment) {
+				return ErrForbidden
+			}
+			override = true
+		}
+	}
+
+	if err := s.comments.Delete(ctx, id); err != nil {
+		return err
+	}
+	if !override {
+		return nil
+	}
+	return s.audit.Create(ctx, &models.AuditLog{
+		ActorID:    actor.UserID,
+		ActorRole:  actor.Role,
+		Action:     models.AuditCommentDelete,
+		TargetType: "comment",
+		TargetID:   comment.ID,
+		OwnerID:    comment.AuthorID,
+		Detail:     truncate(comment.Content, 200),
+	})
+}
+
+func (s *CommentService) findArticle(ctx context.Context, id uint) (*models.Article, error) {
+	article, err := s.articles.FindByID(ctx, id)
+	if err == repository.ErrNotFound {
+		return nil, ErrArticleNotFound
+	}
+	return article, err
+}
@@ -0,0 +1,111 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"blog-backend/internal/models"
+	"blog-backend/internal/services"
+)
+
+// alice 的已发布文章 #1、草稿 #2 和归档文章 #3，bob 在文章 #1 下的评论 #1
+func newCommentService(t *testing.T) (*services.CommentService, *fakeComments, *fakeAuditLogs) {
+	t.Helper()
+	articles := newFakeArticles(
+		models.Article{ID: 1, AuthorID: alice.UserID, Status: models.ArticlePublished},
+		models.Article{ID: 2, AuthorID: alice.UserID, Status: models.ArticleDraft},
+		models.Article{ID: 3, AuthorID: alice.UserID, Status: models.ArticleArchived},
+	)
+	comments := newFakeComments(models.Comment{ID: 1, ArticleID: 1, AuthorID: bob.UserID, Content: "bob 的评论"})
+	verified := time.Now()
+	users := newFakeUsers(
+		models.User{ID: alice.UserID, VerifiedAt: &verified},
+		models.User{ID: bob.UserID, VerifiedAt: &verified},
+		// 未验证邮箱
+		models.User{ID: editor.UserID},
+	)
+	audit := &fakeAuditLogs{}
+	return services.NewCommentService(comments, articles, users, audit), comments, audit
+}
+
+func TestCommentDeleteOwnership(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		actor   services.Actor
+		wantErr error
+		// 越权删除时记录审计日志
+		audited bool
+	}{
+		{"评论作者", bob, nil, false},
+		{"文章作者", alice, nil, false},
+		// 编辑没有删除任意评论的权限
+		{"其他用户", editor, services.ErrForbidden, false},
+		{"管理员", admin, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, comments, audit := newCommentService(t)
+
+			if err := s.Delete(ctx, tt.actor, 1); err != tt.wantErr {
+				t.Fatalf("期望 %v，实际 %v", tt.wantErr, err)
+			}
+			if deleted := !comments.exists(1); deleted != (tt.wantErr == nil) {
+				t.Fatalf("评论删除状态不符: deleted=%v", deleted)
+			}
+
+			logs := audit.all()
+			if !tt.audited {
+				if len(logs) != 0 {
+					t.Fatalf("不应记录审计日志: %+v", logs)
+				}
+				return
+			}
+			if len(logs) != 1 {
+				t.Fatalf("期望 1 条审计日志，实际 %d", len(logs))
+			}
+			if l := logs[0]; l.ActorID != tt.actor.UserID || l.Action != models.AuditCommentDelete || l.TargetID != 1 || l.OwnerID != bob.UserID {
+				t.Fatalf("审计日志不符: %+v", l)
+			}
+		})
+	}
+}
+
+func TestCommentDeleteMissing(t *testing.T) {
+	s, _, _ := newCommentService(t)
+	if err := s.Delete(context.Background(), admin, 404); err != services.ErrCommentNotFound {
+		t.Fatalf("删除不存在的评论应返回 ErrCommentNotFound，实际 %v", err)
+	}
+}
+
+func TestCommentCreate(t *testing.T) {
+	ctx := context.Background()
+	s, comments, _ := newCommentService(t)
+
+	tests := []struct {
+		name      string
+		authorID  uint
+		articleID uint
+		wantErr   error
+	}{
+		{"已发布文章", bob.UserID, 1, nil},
+		// 草稿对评论者按不存在处理
+		{"草稿", alice.UserID, 2, services.ErrArticleNotFound},
+		{"归档文章", bob.UserID, 3, services.ErrArticleArchived},
+		{"文章不存在", bob.UserID, 404, services.ErrArticleNotFound},
+		{"邮箱未验证", editor.UserID, 1, services.ErrEmailNotVerified},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			comment, err := s.Create(ctx, tt.authorID, tt.articleID, "评论")
+			if err != tt.wantErr {
+				t.Fatalf("期望 %v，实际 %v", tt.wantErr, err)
+			}
+			if err == nil && !comments.exists(comment.ID) {
+				t.Fatal("评论未写入")
+			}
+		})
+	}
+}
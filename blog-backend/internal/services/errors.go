@@ -0,0 +1,58 @@
+package services
+
+import (
+	"errors"
+	"time"
+)
+
+// 业务错误类型，由控制器映射为 HTTP 状态码和 error_code
+type Kind int
+
+const (
+	KindInvalidInput Kind = iota + 1
+	KindUnauthorized
+	KindForbidden
+	KindNotFound
+	KindConflict
+	// 邮箱未验证，不能执行发布类操作
+	KindNotVerified
+	// 尝试次数过多，需要等待 RetryAfter 后重试
+	KindTooManyRequests
+)
+
+// 业务错误，Message 可直接返回给客户端
+type Error struct {
+	Kind    Kind
+	Message string
+	// 仅 KindTooManyRequests 使用
+	RetryAfter time.Duration
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func newError(kind Kind, message string) *Error {
+	return &Error{Kind: kind, Message: message}
+}
+
+var (
+	ErrUserNotFound       = newError(KindNotFound, "用户不存在")
+	ErrArticleNotFound    = newError(KindNotFound, "文章不存在")
+	ErrCommentNotFound    = newError(KindNotFound, "评论不存在")
+	ErrUserExists         = newError(KindConflict, "用户名或邮箱已存在")
+	ErrUsernameReserved   = newError(KindInvalidInput, "用户名不能以 deleted- 开头")
+	ErrInvalidCredentials = newError(KindUnauthorized, "邮箱或密码错误")
+	ErrForbidden          = newError(KindForbidden, "权限不足")
+	ErrEmailNotVerified   = newError(KindNotVerified, "请先验证邮箱")
+	ErrLoginRequired      = newError(KindUnauthorized, "请先登录")
+)
+
+// 取出业务错误，非业务错误返回 nil
+func AsError(err error) *Error {
+	var e *Error
+	if errors.As(err, &e) {
+		return e
+	}
+	return nil
+}
@@ -0,0 +1,210 @@
+package services
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"blog-backend/internal/models"
+	"blog-backend/internal/repository"
+)
+
+// 导出的个人资料
+type exportProfile struct {
+	ID               uint       `json:"id"`
+	Username         string     `json:"username"`
+	Email            string     `json:"email"`
+	Avatar           string     `json:"avatar"`
+	Role             string     `json:"role"`
+	CreatedAt        time.Time  `json:"created_at"`
+	VerifiedAt       *time.Time `json:"verified_at"`
+	TwoFactorEnabled bool       `json:"two_factor_enabled"`
+}
+
+type exportArticle struct {
+	ID          uint       `json:"id"`
+	Title       string     `json:"title"`
+	Slug        string     `json:"slug"`
+	Content     string     `json:"content"`
+	Status      string     `json:"status"`
+	Views       int        `json:"views"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	PublishedAt *time.Time `json:"published_at"`
+	PublishAt   *time.Time `json:"publish_at"`
+	Tags        []string   `json:"tags"`
+	// 所属分类的名称，未分类时为空
+	Category string `json:"category"`
+}
+
+type exportSeries struct {
+	ID          uint      `json:"id"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	// 按顺序排列的文章ID
+	ArticleIDs []uint `json:"article_ids"`
+}
+
+type exportComment struct {
+	ID           uint      `json:"id"`
+	ArticleID    uint      `json:"article_id"`
+	ArticleTitle string    `json:"article_title"`
+	Content      string    `json:"content"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// 用户的个人数据
+type UserExport struct {
+	ExportedAt time.Time
+	User       *models.User
+	Articles   []models.Article
+	Series     []SeriesDetail
+	Comments   []models.Comment
+}
+
+// 个人数据导出
+type ExportService struct {
+	users    repository.UserRepository
+	articles repository.ArticleRepository
+	series   repository.SeriesRepository
+	comments repository.CommentRepository
+}
+
+func NewExportService(users repository.UserRepository, articles repository.ArticleRepository, series repository.SeriesRepository, comments repository.CommentRepository) *ExportService {
+	return &ExportService{users: users, articles: articles, series: series, comments: comments}
+}
+
+// 读取用户的个人资料、文章、系列和评论
+func (s *ExportService) Export(ctx context.Context, userID uint) (*UserExport, error) {
+	user, err := s.users.FindByID(ctx, userID)
+	if err == repository.ErrNotFound {
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	articles, err := s.articles.ListByAuthor(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	series, err := s.series.ListByAuthor(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	details := make([]SeriesDetail, 0, len(series))
+	for _, sr := range series {
+		parts, err := s.series.Articles(ctx, sr.ID)
+		if err != nil {
+			return nil, err
+		}
+		details = append(details, SeriesDetail{Series: sr, Articles: seriesParts(parts, Actor{UserID: userID})})
+	}
+	comments, err := s.comments.ListByAuthor(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &UserExport{ExportedAt: time.Now(), User: user, Articles: articles, Series: details, Comments: comments}, nil
+}
+
+// 压缩包文件名
+func (e *UserExport) Filename() string {
+	return fmt.Sprintf("blog-export-%d-%s.zip", e.User.ID, e.ExportedAt.Format("20060102"))
+}
+
+// 写入 zip 压缩包：JSON 格式的资料、文章、系列和评论，以及便于阅读的 Markdown 文件
+func (e *UserExport) WriteArchive(w io.Writer) error {
+	zw := zip.NewWriter(w)
+
+	profile := exportProfile{
+		ID:               e.User.ID,
+		Username:         e.User.Username,
+		Email:            e.User.Email,
+		Avatar:           e.User.Avatar,
+		Role:             e.User.Role,
+		CreatedAt:        e.User.CreatedAt,
+		VerifiedAt:       e.User.VerifiedAt,
+		TwoFactorEnabled: e.User.TwoFactorEnabled(),
+	}
+	articles := make([]exportArticle, 0, len(e.Articles))
+	for _, a := range e.Articles {
+		exported := exportArticle{ID: a.ID, Title: a.Title, Slug: a.Slug, Content: a.Content, Status: a.Status, Views: a.Views,
+			CreatedAt: a.CreatedAt, UpdatedAt: a.UpdatedAt, PublishedAt: a.PublishedAt, PublishAt: a.PublishAt, Tags: []string{}}
+		for _, tag := range a.Tags {
+			exported.Tags = append(exported.Tags, tag.Name)
+		}
+		if a.Category != nil {
+			exported.Category = a.Category.Name
+		}
+		articles = append(articles, exported)
+	}
+	series := make([]exportSeries, 0, len(e.Series))
+	for _, sr := range e.Series {
+		exported := exportSeries{ID: sr.ID, Title: sr.Title, Description: sr.Description, CreatedAt: sr.CreatedAt, ArticleIDs: []uint{}}
+		for _, part := range sr.Articles {
+			exported.ArticleIDs = append(exported.ArticleIDs, part.ID)
+		}
+		series = append(series, exported)
+	}
+	comments := make([]exportComment, 0, len(e.Comments))
+	for _, c := range e.Comments {
+		comments = append(comments, exportComment{ID: c.ID, ArticleID: c.ArticleID, ArticleTitle: c.Article.Title, Content: c.Content, CreatedAt: c.CreatedAt})
+	}
+
+	files := []archiveFile{
+		{"profile.json", writeJSON(profile)},
+		{"articles.json", writeJSON(articles)},
+		{"series.json", writeJSON(series)},
+		{"comments.json", writeJSON(comments)},
+		{"comments.md", func(w io.Writer) error { return writeCommentsMarkdown(w, comments) }},
+	}
+	for _, a := range articles {
+		files = append(files, archiveFile{fmt.Sprintf("articles/%d.md", a.ID), func(w io.Writer) error { return writeArticleMarkdown(w, a) }})
+	}
+
+	for _, f := range files {
+		fw, err := zw.CreateHeader(&zip.FileHeader{Name: f.name, Method: zip.Deflate, Modified: e.ExportedAt})
+		if err != nil {
+			return err
+		}
+		if err := f.write(fw); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// 压缩包中的一个文件
+type archiveFile struct {
+	name  string
+	write func(io.Writer) error
+}
+
+func writeJSON(v interface{}) func(io.Writer) error {
+	return func(w io.Writer) error {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	}
+}
+
+func writeArticleMarkdown(w io.Writer, a exportArticle) error {
+	_, err := fmt.Fprintf(w, "# %s\n\n> 发布于 %s，更新于 %s\n\n%s\n",
+		a.Title, a.CreatedAt.Format(time.RFC3339), a.UpdatedAt.Format(time.RFC3339), a.Content)
+	return err
+}
+
+func writeCommentsMarkdown(w io.Writer, comments []exportComment) error {
+	var b strings.Builder
+	b.WriteString("# 我的评论\n")
+	for _, c := range comments {
+		fmt.Fprintf(&b, "\n## %s\n\n> 评论于 %s (文章 #%d)\n\n%s\n", c.ArticleTitle, c.CreatedAt.Format(time.RFC3339), c.ArticleID, c.Content)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,228 @@
+package services_test
+
+import (
+	"context"
+	"sync"
+
+	"blog-backend/internal/models"
+	"blog-backend/internal/repository"
+)
+
+// 内存中的仓储实现，只实现服务单元测试用到的方法，其余方法通过嵌入的接口调用时会 panic
+//
+// 查询返回副本，服务修改返回的对象后必须调用 Update 等方法才会写回，与数据库仓储的行为一致。
+
+type fakeArticles struct {
+	repository.ArticleRepository
+
+	mu       sync.Mutex
+	articles map[uint]models.Article
+	oldSlugs map[string]uint
+}
+
+func newFakeArticles(articles ...models.Article) *fakeArticles {
+	f := &fakeArticles{articles: make(map[uint]models.Article), oldSlugs: make(map[string]uint)}
+	for _, a := range articles {
+		f.articles[a.ID] = a
+	}
+	return f
+}
+
+// 当前保存的文章，测试中用于检查写入结果
+func (f *fakeArticles) get(id uint) models.Article {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.articles[id]
+}
+
+func (f *fakeArticles) FindByID(ctx context.Context, id uint) (*models.Article, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	a, ok := f.articles[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return &a, nil
+}
+
+func (f *fakeArticles) FindBySlug(ctx context.Context, slug string) (*models.Article, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, a := range f.articles {
+		if a.Slug == slug {
+			return &a, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (f *fakeArticles) FindOldSlug(ctx context.Context, slug string) (*models.ArticleSlug, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	id, ok := f.oldSlugs[slug]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return &models.ArticleSlug{ArticleID: id, Slug: slug}, nil
+}
+
+func (f *fakeArticles) SlugTaken(ctx context.Context, slug string, exceptArticleID uint) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, a := range f.articles {
+		if a.ID != exceptArticleID && a.Slug == slug {
+			return true, nil
+		}
+	}
+	id, ok := f.oldSlugs[slug]
+	return ok && id != exceptArticleID, nil
+}
+
+func (f *fakeArticles) Update(ctx context.Context, article *models.Article) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.articles[article.ID]; !ok {
+		return repository.ErrNotFound
+	}
+	f.articles[article.ID] = *article
+	return nil
+}
+
+func (f *fakeArticles) Rename(ctx context.Context, article *models.Article, oldSlug string) error {
+	if err := f.Update(ctx, article); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.oldSlugs[oldSlug] = article.ID
+	return nil
+}
+
+func (f *fakeArticles) Delete(ctx context.Context, id uint) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.articles[id]; !ok {
+		return repository.ErrNotFound
+	}
+	delete(f.articles, id)
+	for slug, articleID := range f.oldSlugs {
+		if articleID == id {
+			delete(f.oldSlugs, slug)
+		}
+	}
+	return nil
+}
+
+func (f *fakeArticles) IncrementViews(ctx context.Context, id uint) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	a, ok := f.articles[id]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	a.Views++
+	f.articles[id] = a
+	return nil
+}
+
+type fakeComments struct {
+	repository.CommentRepository
+
+	mu       sync.Mutex
+	comments map[uint]models.Comment
+	nextID   uint
+}
+
+func newFakeComments(comments ...models.Comment) *fakeComments {
+	f := &fakeComments{comments: make(map[uint]models.Comment)}
+	for _, c := range comments {
+		f.comments[c.ID] = c
+		if c.ID > f.nextID {
+			f.nextID = c.ID
+		}
+	}
+	return f
+}
+
+func (f *fakeComments) exists(id uint) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	_, ok := f.comments[id]
+	return ok
+}
+
+func (f *fakeComments) FindByID(ctx context.Context, id uint) (*models.Comment, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	c, ok := f.comments[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return &c, nil
+}
+
+func (f *fakeComments) Create(ctx context.Context, comment *models.Comment) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.nextID++
+	comment.ID = f.nextID
+	f.comments[comment.ID] = *comment
+	return nil
+}
+
+func (f *fakeComments) Delete(ctx context.Context, id uint) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.comments[id]; !ok {
+		return repository.ErrNotFound
+	}
+	delete(f.comments, id)
+	return nil
+}
+
+type fakeUsers struct {
+	repository.UserRepository
+
+	mu    sync.Mutex
+	users map[uint]models.User
+}
+
+func newFakeUsers(users ...models.User) *fakeUsers {
+	f := &fakeUsers{users: make(map[uint]models.User)}
+	for _, u := range users {
+		f.users[u.ID] = u
+	}
+	return f
+}
+
+func (f *fakeUsers) FindByID(ctx context.Context, id uint) (*models.User, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	u, ok := f.users[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return &u, nil
+}
+
+type fakeAuditLogs struct {
+	repository.AuditLogRepository
+
+	mu   sync.Mutex
+	logs []models.AuditLog
+}
+
+func (f *fakeAuditLogs) Create(ctx context.Context, log *models.AuditLog) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	log.ID = uint(len(f.logs) + 1)
+	f.logs = append(f.logs, *log)
+	return nil
+}
+
+// 已记录的审计日志
+func (f *fakeAuditLogs) all() []models.AuditLog {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]models.AuditLog(nil), f.logs...)
+}
@@ -0,0 +1,94 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"blog-backend/config"
+	"blog-backend/internal/models"
+	"blog-backend/internal/repository"
+)
+
+// 超过该时间没有新的失败记录时重新计数
+const throttleResetAfter = 24 * time.Hour
+
+// 登录失败次数过多
+func tooManyAttempts(retryAfter time.Duration) *Error {
+	return &Error{Kind: KindTooManyRequests, Message: "登录失败次数过多，请稍后再试", RetryAfter: retryAfter}
+}
+
+// 登录限流，按账号和客户端 IP 分别统计失败次数，达到阈值后锁定并按失败次数指数延长锁定时间
+type LoginThrottleService struct {
+	throttles repository.LoginThrottleRepository
+	cfg       config.AccountConfig
+}
+
+func NewLoginThrottleService(throttles repository.LoginThrottleRepository, cfg config.AccountConfig) *LoginThrottleService {
+	return &LoginThrottleService{throttles: throttles, cfg: cfg}
+}
+
+// 账号或 IP 处于锁定期时返回需要等待的时间
+func (s *LoginThrottleService) Check(ctx context.Context, email, ip string) error {
+	now := time.Now()
+	var wait time.Duration
+	for _, subject := range throttleSubjects(email, ip) {
+		throttle, err := s.throttles.Find(ctx, subject)
+		if err == repository.ErrNotFound {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if throttle.Locked(now) && throttle.LockedUntil.Sub(now) > wait {
+			wait = throttle.LockedUntil.Sub(now)
+		}
+	}
+	if wait > 0 {
+		return tooManyAttempts(wait)
+	}
+	return nil
+}
+
+// 记录一次登录失败
+func (s *LoginThrottleService) Fail(ctx context.Context, email, ip string) error {
+	now := time.Now()
+	if err := s.fail(ctx, models.AccountThrottle(email), s.cfg.LoginMaxFailures, now); err != nil {
+		return err
+	}
+	if ip == "" {
+		return nil
+	}
+	return s.fail(ctx, models.IPThrottle(ip), s.cfg.LoginIPMaxFailures, now)
+}
+
+// 清除账号的失败记录，用于登录成功和管理员解锁，IP 的失败记录只能等待过期
+func (s *LoginThrottleService) Reset(ctx context.Context, email string) error {
+	return s.throttles.Delete(ctx, models.AccountThrottle(email))
+}
+
+func (s *LoginThrottleService) fail(ctx context.Context, subject string, limit int, now time.Time) error {
+	throttle, err := s.throttles.Fail(ctx, subject, now, now.Add(-throttleResetAfter))
+	if err != nil {
+		return err
+	}
+	if throttle.Failures < limit {
+		return nil
+	}
+
+	lockout := s.cfg.LoginLockout
+	for i := limit; i < throttle.Failures && lockout < s.cfg.LoginMaxLockout; i++ {
+		lockout *= 2
+	}
+	if lockout > s.cfg.LoginMaxLockout {
+		lockout = s.cfg.LoginMaxLockout
+	}
+	return s.throttles.Lock(ctx, throttle.ID, now.Add(lockout))
+}
+
+func throttleSubjects(email, ip string) []string {
+	subjects := []string{models.AccountThrottle(email)}
+	if ip != "" {
+		subjects = append(subjects, models.IPThrottle(ip))
+	}
+	return subjects
+}
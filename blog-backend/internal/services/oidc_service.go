@@ -0,0 +1,220 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"log"
+	"math/big"
+	"regexp"
+	"strings"
+	"time"
+
+	"blog-backend/internal/auth"
+	"blog-backend/internal/models"
+	"blog-backend/internal/oidc"
+	"blog-backend/internal/repository"
+)
+
+// OIDC 登录请求的有效期
+const oidcStateExpire = 10 * time.Minute
+
+var (
+	ErrInvalidOIDCState  = newError(KindUnauthorized, "登录请求已过期，请重新登录")
+	ErrOIDCLoginFailed   = newError(KindUnauthorized, "第三方登录失败")
+	ErrOIDCEmailRequired = newError(KindForbidden, "身份提供方未提供已验证的邮箱")
+	// 未验证的账号可能是他人抢注的，关联后抢注者的密码、会话和令牌仍然有效
+	ErrOIDCAccountUnverified = newError(KindConflict, "该邮箱已注册但尚未验证，请先登录该账号完成邮箱验证，或通过忘记密码重置密码后再验证")
+)
+
+// 自动生成用户名时去掉的字符
+var usernameUnsafePattern = regexp.MustCompile(`[^\p{L}\p{N}_.-]+`)
+
+// OpenID Connect 登录，外部身份按已验证的邮箱关联到已验证邮箱的用户，邮箱未注册时自动创建用户
+type OIDCService struct {
+	provider   *oidc.Provider
+	users      repository.UserRepository
+	identities repository.IdentityRepository
+	sessions   *SessionService
+	twoFactor  *TwoFactorService
+}
+
+func NewOIDCService(provider *oidc.Provider, users repository.UserRepository, identities repository.IdentityRepository, sessions *SessionService, twoFactor *TwoFactorService) *OIDCService {
+	return &OIDCService{provider: provider, users: users, identities: identities, sessions: sessions, twoFactor: twoFactor}
+}
+
+// 创建登录请求，返回身份提供方的授权地址
+func (s *OIDCService) Begin(ctx context.Context) (string, error) {
+	now := time.Now()
+	if err := s.identities.DeleteExpiredStates(ctx, now); err != nil {
+		return "", err
+	}
+
+	state, stateHash, err := auth.NewOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+	nonce, _, err := auth.NewOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+	verifier, err := oidc.NewVerifier()
+	if err != nil {
+		return "", err
+	}
+
+	authURL, err := s.provider.AuthCodeURL(ctx, state, nonce, verifier)
+	if err != nil {
+		return "", err
+	}
+	err = s.identities.CreateState(ctx, &models.PendingLogin{
+		StateHash:    stateHash,
+		CodeVerifier: verifier,
+		Nonce:        nonce,
+		ExpiresAt:    now.Add(oidcStateExpire),
+	})
+	if err != nil {
+		return "", err
+	}
+	return authURL, nil
+}
+
+// 使用回调中的授权码完成登录，开启两步验证的用户返回登录挑战
+func (s *OIDCService) Complete(ctx context.Context, code, state string, client ClientInfo) (*LoginResult, error) {
+	pending, err := s.identities.ConsumeState(ctx, auth.HashToken(state))
+	if err == repository.ErrNotFound {
+		return nil, ErrInvalidOIDCState
+	}
+	if err != nil {
+		return nil, err
+	}
+	if time.Now().After(pending.ExpiresAt) {
+		return nil, ErrInvalidOIDCState
+	}
+
+	rawIDToken, err := s.provider.Exchange(ctx, code, pending.CodeVerifier)
+	if err != nil {
+		log.Printf("OIDC 换取令牌失败: %v", err)
+		return nil, ErrOIDCLoginFailed
+	}
+	identity, err := s.provider.Verify(ctx, rawIDToken, pending.Nonce)
+	if err != nil {
+		log.Printf("OIDC ID Token 校验失败: %v", err)
+		return nil, ErrOIDCLoginFailed
+	}
+
+	user, err := s.resolveUser(ctx, identity)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.TwoFactorEnabled() {
+		return &LoginResult{User: user, Challenge: s.twoFactor.Challenge(user)}, nil
+	}
+	tokens, err := s.sessions.Start(ctx, user, client)
+	if err != nil {
+		return nil, err
+	}
+	return &LoginResult{User: user, Tokens: tokens}, nil
+}
+
+// 查找外部身份关联的用户，首次登录时按邮箱关联或创建用户
+func (s *OIDCService) resolveUser(ctx context.Context, identity *oidc.Identity) (*models.User, error) {
+	issuer := s.provider.Issuer()
+	link, err := s.identities.Find(ctx, issuer, identity.Subject)
+	if err == nil {
+		user, err := s.users.FindByID(ctx, link.UserID)
+		if err == repository.ErrNotFound {
+			return nil, ErrUserNotFound
+		}
+		return user, err
+	}
+	if err != repository.ErrNotFound {
+		return nil, err
+	}
+
+	// 只有身份提供方验证过的邮箱才能关联已有账号，避免通过伪造邮箱接管账号
+	if identity.Email == "" || !identity.EmailVerified {
+		return nil, ErrOIDCEmailRequired
+	}
+
+	user, err := s.users.FindByEmail(ctx, identity.Email)
+	if err == nil && !user.Verified() {
+		return nil, ErrOIDCAccountUnverified
+	}
+	if err == repository.ErrNotFound {
+		user, err = s.createUser(ctx, identity)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.identities.Create(ctx, &models.UserIdentity{
+		UserID:  user.ID,
+		Issuer:  issuer,
+		Subject: identity.Subject,
+		Email:   identity.Email,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// 为首次登录的外部身份创建用户，密码随机生成，需要时可通过忘记密码设置
+func (s *OIDCService) createUser(ctx context.Context, identity *oidc.Identity) (*models.User, error) {
+	username, err := s.availableUsername(ctx, identity)
+	if err != nil {
+		return nil, err
+	}
+	password, _, err := auth.NewOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+	hashed, err := auth.HashPassword(password)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	user := &models.User{
+		Username:   username,
+		Email:      identity.Email,
+		Password:   hashed,
+		Role:       models.RoleAuthor,
+		VerifiedAt: &now,
+	}
+	if err := s.users.Create(ctx, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// 以 preferred_username 或邮箱前缀为基础生成未被占用的用户名
+func (s *OIDCService) availableUsername(ctx context.Context, identity *oidc.Identity) (string, error) {
+	base := identity.Username
+	if base == "" {
+		base = strings.SplitN(identity.Email, "@", 2)[0]
+	}
+	base = truncate(usernameUnsafePattern.ReplaceAllString(base, ""), 40)
+	if base == "" || models.ReservedUsername(base) {
+		base = "user"
+	}
+
+	candidate := base
+	for i := 0; i < 5; i++ {
+		exists, err := s.users.ExistsByUsername(ctx, candidate)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			return candidate, nil
+		}
+		n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+		if err != nil {
+			return "", err
+		}
+		candidate = fmt.Sprintf("%s-%06d", base, n.Int64())
+	}
+	return "", ErrUserExists
+}
@@ -0,0 +1,102 @@
+package services
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"blog-backend/config"
+)
+
+var (
+	ErrPasswordBreached = newError(KindInvalidInput, "该密码已在公开的泄露数据中出现，请换一个密码")
+	ErrPasswordPersonal = newError(KindInvalidInput, "密码不能与用户名或邮箱相同")
+)
+
+// 泄露密码列表最多读入的条数，列表整个保存在内存中，
+// 应使用最常见的若干密码，而不是完整的泄露数据库
+const MaxBreachedPasswords = 1000000
+
+// 泄露密码列表中 SHA-1 摘要格式的行，可带 ":次数" 后缀
+var sha1LinePattern = regexp.MustCompile(`^[0-9A-Fa-f]{40}(:\d+)?$`)
+
+// 设置新密码时的密码规则
+type PasswordPolicy struct {
+	minLength int
+	maxLength int
+	// 已泄露的密码，明文和大写的 SHA-1 摘要
+	breached map[string]struct{}
+}
+
+// 创建密码规则，配置了泄露密码列表时在启动时一次性读入内存，
+// 超过 MaxBreachedPasswords 条时拒绝启动
+func NewPasswordPolicy(cfg config.AccountConfig) (*PasswordPolicy, error) {
+	p := &PasswordPolicy{minLength: cfg.PasswordMinLength, maxLength: cfg.PasswordMaxLength, breached: make(map[string]struct{})}
+	if cfg.PasswordBreachedFile == "" {
+		return p, nil
+	}
+
+	f, err := os.Open(cfg.PasswordBreachedFile)
+	if err != nil {
+		return nil, fmt.Errorf("读取泄露密码列表失败: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" {
+			continue
+		}
+		if len(p.breached) >= MaxBreachedPasswords {
+			return nil, fmt.Errorf("泄露密码列表超过 %d 条，请只保留最常见的密码", MaxBreachedPasswords)
+		}
+		if sha1LinePattern.MatchString(line) {
+			digest, _, _ := strings.Cut(line, ":")
+			line = strings.ToUpper(digest)
+		}
+		p.breached[line] = struct{}{}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("读取泄露密码列表失败: %w", err)
+	}
+	return p, nil
+}
+
+// 校验新密码，personal 为用户名、邮箱等不能直接用作密码的个人信息
+func (p *PasswordPolicy) Validate(password string, personal ...string) error {
+	n := utf8.RuneCountInString(password)
+	if n < p.minLength {
+		return newError(KindInvalidInput, fmt.Sprintf("密码长度不能少于 %d 个字符", p.minLength))
+	}
+	if n > p.maxLength {
+		return newError(KindInvalidInput, fmt.Sprintf("密码长度不能超过 %d 个字符", p.maxLength))
+	}
+	for _, info := range personal {
+		if info != "" && strings.EqualFold(password, info) {
+			return ErrPasswordPersonal
+		}
+	}
+	if p.Breached(password) {
+		return ErrPasswordBreached
+	}
+	return nil
+}
+
+// 密码是否在泄露密码列表中
+func (p *PasswordPolicy) Breached(password string) bool {
+	if len(p.breached) == 0 {
+		return false
+	}
+	if _, ok := p.breached[password]; ok {
+		return true
+	}
+	sum := sha1.Sum([]byte(password))
+	_, ok := p.breached[strings.ToUpper(hex.EncodeToString(sum[:]))]
+	return ok
+}
@@ -0,0 +1,49 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"blog-backend/internal/repository"
+)
+
+// 每批处理的到期文章数量
+const publishBatchSize = 100
+
+// 定时发布，由后台任务周期性调用
+type ScheduledPublisher struct {
+	articles repository.ArticleRepository
+}
+
+func NewScheduledPublisher(articles repository.ArticleRepository) *ScheduledPublisher {
+	return &ScheduledPublisher{articles: articles}
+}
+
+// 发布定时发布时间不晚于 now 的草稿，返回本次发布的文章数量
+//
+// 可以在多个实例上同时执行：每篇文章通过带条件的更新发布，已被其他实例发布的文章会被跳过，
+// 重复执行也不会产生副作用。
+func (p *ScheduledPublisher) PublishDue(ctx context.Context, now time.Time) (int, error) {
+	now = now.Local()
+	published := 0
+	for {
+		due, err := p.articles.ListDue(ctx, now, publishBatchSize)
+		if err != nil {
+			return published, err
+		}
+		for _, article := range due {
+			ok, err := p.articles.PublishScheduled(ctx, article.ID, now)
+			if err != nil {
+				return published, err
+			}
+			if ok {
+				published++
+				log.Printf("已定时发布文章 %d《%s》", article.ID, article.Title)
+			}
+		}
+		if len(due) < publishBatchSize {
+			return published, nil
+		}
+	}
+}
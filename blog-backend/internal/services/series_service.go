@@ -0,0 +1,321 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"unicode/utf8"
+
+	"blog-backend/internal/auth"
+	"blog-backend/internal/models"
+	"blog-backend/internal/repository"
+)
+
+// 每个系列最多的文章数量
+const MaxSeriesArticles = 100
+
+var (
+	ErrInvalidSeriesTitle       = newError(KindInvalidInput, "系列标题不能为空且不能超过200个字符")
+	ErrSeriesDescriptionTooLong = newError(KindInvalidInput, "系列简介不能超过500个字符")
+	ErrTooManySeriesArticles    = newError(KindInvalidInput, "每个系列最多100篇文章")
+	ErrDuplicateSeriesArticle   = newError(KindInvalidInput, "系列中的文章不能重复")
+	ErrUnknownArticle           = newError(KindInvalidInput, "文章不存在")
+	ErrSeriesArticleNotOwned    = newError(KindInvalidInput, "系列只能包含系列作者的文章")
+	ErrSeriesNotFound           = newError(KindNotFound, "系列不存在")
+	ErrArticleInOtherSeries     = newError(KindConflict, "文章已属于其他系列")
+)
+
+type CreateSeriesParams struct {
+	Title       string
+	Description string
+	// 按顺序排列的文章ID
+	ArticleIDs []uint
+}
+
+// 空字段保持不变
+type UpdateSeriesParams struct {
+	Title       string
+	Description string
+}
+
+// 系列中的一篇文章
+type SeriesPart struct {
+	ID     uint   `json:"id"`
+	Title  string `json:"title"`
+	Slug   string `json:"slug"`
+	Status string `json:"status"`
+	// 在系列中的序号，从 1 开始，只计算查看者可见的文章
+	Position int `json:"position"`
+}
+
+// 系列及其中查看者可见的文章
+type SeriesDetail struct {
+	models.Series
+	Articles []SeriesPart `json:"articles"`
+}
+
+// 文章详情中的系列导航，第一篇没有上一篇，最后一篇没有下一篇
+type SeriesNavigation struct {
+	ID       uint        `json:"id"`
+	Title    string      `json:"title"`
+	Position int         `json:"position"`
+	Total    int         `json:"total"`
+	Prev     *SeriesPart `json:"prev"`
+	Next     *SeriesPart `json:"next"`
+}
+
+// 系列文章业务规则，所有权检查与文章相同
+type SeriesService struct {
+	series   repository.SeriesRepository
+	articles repository.ArticleRepository
+	users    repository.UserRepository
+	audit    repository.AuditLogRepository
+}
+
+func NewSeriesService(series repository.SeriesRepository, articles repository.ArticleRepository, users repository.UserRepository, audit repository.AuditLogRepository) *SeriesService {
+	return &SeriesService{series: series, articles: articles, users: users, audit: audit}
+}
+
+func (s *SeriesService) List(ctx context.Context, offset, limit int) ([]models.Series, int64, error) {
+	return s.series.List(ctx, offset, limit)
+}
+
+// 查询系列及其中 viewer 可见的文章，无权查看的草稿不会出现在列表中
+func (s *SeriesService) Get(ctx context.Context, viewer Actor, id uint) (*SeriesDetail, error) {
+	series, err := s.find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	parts, err := s.parts(ctx, viewer, id)
+	if err != nil {
+		return nil, err
+	}
+	return &SeriesDetail{Series: *series, Articles: parts}, nil
+}
+
+// 文章所属系列的导航，文章不属于任何系列时返回 nil
+func (s *SeriesService) Navigation(ctx context.Context, viewer Actor, articleID uint) (*SeriesNavigation, error) {
+	link, err := s.series.FindByArticle(ctx, articleID)
+	if err == repository.ErrNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	series, err := s.find(ctx, link.SeriesID)
+	if err != nil {
+		return nil, err
+	}
+	parts, err := s.parts(ctx, viewer, series.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range parts {
+		if parts[i].ID != articleID {
+			continue
+		}
+		nav := &SeriesNavigation{ID: series.ID, Title: series.Title, Position: parts[i].Position, Total: len(parts)}
+		if i > 0 {
+			nav.Prev = &parts[i-1]
+		}
+		if i < len(parts)-1 {
+			nav.Next = &parts[i+1]
+		}
+		return nav, nil
+	}
+	// 文章对 viewer 不可见
+	return nil, nil
+}
+
+// 创建系列，作者邮箱必须已验证，系列只能包含作者本人的文章
+func (s *SeriesService) Create(ctx context.Context, actor Actor, params CreateSeriesParams) (*SeriesDetail, error) {
+	if err := validateSeries(params.Title, params.Description); err != nil {
+		return nil, err
+	}
+	if err := ensureVerified(ctx, s.users, actor.UserID); err != nil {
+		return nil, err
+	}
+	series := &models.Series{Title: params.Title, Description: params.Description, AuthorID: actor.UserID}
+	if err := s.checkArticles(ctx, actor, series, params.ArticleIDs); err != nil {
+		return nil, err
+	}
+
+	if err := s.series.Create(ctx, series, params.ArticleIDs); err != nil {
+		return nil, err
+	}
+	return s.Get(ctx, actor, series.ID)
+}
+
+// 修改系列标题和简介，系列作者或编辑、管理员可操作
+func (s *SeriesService) Update(ctx context.Context, actor Actor, id uint, params UpdateSeriesParams) (*models.Series, error) {
+	series, override, err := s.getOwned(ctx, actor, id, auth.PermEditAnyArticle)
+	if err != nil {
+		return nil, err
+	}
+	oldTitle := series.Title
+
+	if params.Title != "" {
+		series.Title = params.Title
+	}
+	if params.Description != "" {
+		series.Description = params.Description
+	}
+	if err := validateSeries(series.Title, series.Description); err != nil {
+		return nil, err
+	}
+	if err := s.series.Update(ctx, series); err != nil {
+		return nil, err
+	}
+	if override {
+		detail := fmt.Sprintf("修改系列《%s》", oldTitle)
+		if err := s.record(ctx, actor, models.AuditSeriesUpdate, series, detail); err != nil {
+			return nil, err
+		}
+	}
+	return series, nil
+}
+
+// 按 articleIDs 的顺序替换系列中的文章，可用于调整顺序、加入和移出文章
+func (s *SeriesService) SetArticles(ctx context.Context, actor Actor, id uint, articleIDs []uint) (*SeriesDetail, error) {
+	series, override, err := s.getOwned(ctx, actor, id, auth.PermEditAnyArticle)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkArticles(ctx, actor, series, articleIDs); err != nil {
+		return nil, err
+	}
+
+	if err := s.series.SetArticles(ctx, series.ID, articleIDs); err != nil {
+		return nil, err
+	}
+	if override {
+		detail := fmt.Sprintf("调整系列《%s》的文章", series.Title)
+		if err := s.record(ctx, actor, models.AuditSeriesUpdate, series, detail); err != nil {
+			return nil, err
+		}
+	}
+	return s.Get(ctx, actor, series.ID)
+}
+
+// 删除系列，其中的文章保留，系列作者或管理员可操作
+func (s *SeriesService) Delete(ctx context.Context, actor Actor, id uint) error {
+	series, override, err := s.getOwned(ctx, actor, id, auth.PermDeleteAnyArticle)
+	if err != nil {
+		return err
+	}
+	err = s.series.Delete(ctx, id)
+	if err == repository.ErrNotFound {
+		return ErrSeriesNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if override {
+		detail := fmt.Sprintf("删除系列《%s》", series.Title)
+		return s.record(ctx, actor, models.AuditSeriesDelete, series, detail)
+	}
+	return nil
+}
+
+// 查询系列，不存在时返回 ErrSeriesNotFound
+func (s *SeriesService) find(ctx context.Context, id uint) (*models.Series, error) {
+	series, err := s.series.FindByID(ctx, id)
+	if err == repository.ErrNotFound {
+		return nil, ErrSeriesNotFound
+	}
+	return series, err
+}
+
+// 查询系列并检查操作权限，非系列作者时需要 perm 权限，override 表示越过了所有权检查
+func (s *SeriesService) getOwned(ctx context.Context, actor Actor, id uint, perm auth.Permission) (series *models.Series, override bool, err error) {
+	series, err = s.find(ctx, id)
+	if err != nil {
+		return nil, false, err
+	}
+	if series.AuthorID == actor.UserID {
+		return series, false, nil
+	}
+	if !actor.Can(perm) {
+		return nil, false, ErrForbidden
+	}
+	return series, true, nil
+}
+
+// 系列中 viewer 可见的文章
+func (s *SeriesService) parts(ctx context.Context, viewer Actor, seriesID uint) ([]SeriesPart, error) {
+	articles, err := s.series.Articles(ctx, seriesID)
+	if err != nil {
+		return nil, err
+	}
+	return seriesParts(articles, viewer), nil
+}
+
+// 校验要加入系列的文章：不能重复，actor 必须可见，作者必须是系列作者，且不属于其他系列
+func (s *SeriesService) checkArticles(ctx context.Context, actor Actor, series *models.Series, articleIDs []uint) error {
+	if len(articleIDs) > MaxSeriesArticles {
+		return ErrTooManySeriesArticles
+	}
+	seen := make(map[uint]bool, len(articleIDs))
+	for _, id := range articleIDs {
+		if seen[id] {
+			return ErrDuplicateSeriesArticle
+		}
+		seen[id] = true
+
+		article, err := s.articles.FindByID(ctx, id)
+		if err == repository.ErrNotFound || (err == nil && !visibleTo(article, actor)) {
+			return ErrUnknownArticle
+		}
+		if err != nil {
+			return err
+		}
+		if article.AuthorID != series.AuthorID {
+			return ErrSeriesArticleNotOwned
+		}
+
+		link, err := s.series.FindByArticle(ctx, id)
+		if err == nil && link.SeriesID != series.ID {
+			return ErrArticleInOtherSeries
+		}
+		if err != nil && err != repository.ErrNotFound {
+			return err
+		}
+	}
+	return nil
+}
+
+// 记录越权操作
+func (s *SeriesService) record(ctx context.Context, actor Actor, action string, series *models.Series, detail string) error {
+	return s.audit.Create(ctx, &models.AuditLog{
+		ActorID:    actor.UserID,
+		ActorRole:  actor.Role,
+		Action:     action,
+		TargetType: "series",
+		TargetID:   series.ID,
+		OwnerID:    series.AuthorID,
+		Detail:     detail,
+	})
+}
+
+// 按顺序过滤出 viewer 可见的文章并重新编号
+func seriesParts(articles []models.Article, viewer Actor) []SeriesPart {
+	parts := []SeriesPart{}
+	for i := range articles {
+		if !visibleTo(&articles[i], viewer) {
+			continue
+		}
+		a := articles[i]
+		parts = append(parts, SeriesPart{ID: a.ID, Title: a.Title, Slug: a.Slug, Status: a.Status, Position: len(parts) + 1})
+	}
+	return parts
+}
+
+func validateSeries(title, description string) error {
+	if title == "" || utf8.RuneCountInString(title) > 200 {
+		return ErrInvalidSeriesTitle
+	}
+	if utf8.RuneCountInString(description) > 500 {
+		return ErrSeriesDescriptionTooLong
+	}
+	return nil
+}
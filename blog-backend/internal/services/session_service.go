@@ -0,0 +1,213 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"blog-backend/internal/auth"
+	"blog-backend/internal/models"
+	"blog-backend/internal/repository"
+)
+
+// 最后活跃时间的更新间隔，避免每个请求都写数据库
+const touchInterval = time.Minute
+
+var (
+	ErrInvalidRefreshToken = newError(KindUnauthorized, "无效的刷新令牌")
+	ErrSessionRevoked      = newError(KindUnauthorized, "登录已失效，请重新登录")
+	ErrSessionNotFound     = newError(KindNotFound, "会话不存在")
+)
+
+// 发起请求的客户端信息
+type ClientInfo struct {
+	UserAgent string
+	IP        string
+}
+
+// 签发给客户端的令牌
+type TokenPair struct {
+	AccessToken  string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	// 访问令牌有效期 (秒)
+	ExpiresIn int64 `json:"expires_in"`
+}
+
+// 登录会话与令牌轮换
+type SessionService struct {
+	sessions      repository.SessionRepository
+	users         repository.UserRepository
+	tokens        *auth.TokenManager
+	refreshExpire time.Duration
+}
+
+func NewSessionService(sessions repository.SessionRepository, users repository.UserRepository, tokens *auth.TokenManager, refreshExpire time.Duration) *SessionService {
+	return &SessionService{sessions: sessions, users: users, tokens: tokens, refreshExpire: refreshExpire}
+}
+
+// 为用户创建新的登录会话
+func (s *SessionService) Start(ctx context.Context, user *models.User, client ClientInfo) (*TokenPair, error) {
+	session := &models.Session{UserID: user.ID}
+	s.extend(session, client, time.Now())
+	if err := s.sessions.Create(ctx, session); err != nil {
+		return nil, err
+	}
+	return s.issue(ctx, session, user.Role)
+}
+
+// 使用刷新令牌换取新的令牌对，旧的刷新令牌随即失效
+//
+// 已使用过的刷新令牌再次出现说明令牌可能已泄露，此时撤销整个会话。
+func (s *SessionService) Refresh(ctx context.Context, refreshToken string, client ClientInfo) (*TokenPair, error) {
+	token, err := s.sessions.FindRefreshTokenByHash(ctx, auth.HashToken(refreshToken))
+	if err == repository.ErrNotFound {
+		return nil, ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := s.sessions.FindByID(ctx, token.SessionID)
+	if err == repository.ErrNotFound {
+		return nil, ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return nil, err
+	}
+	if session.Revoked() {
+		return nil, ErrSessionRevoked
+	}
+
+	fresh, err := s.sessions.MarkRefreshTokenUsed(ctx, token.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !fresh {
+		if err := s.sessions.Revoke(ctx, session.ID); err != nil {
+			return nil, err
+		}
+		return nil, ErrSessionRevoked
+	}
+
+	now := time.Now()
+	if now.After(token.ExpiresAt) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	// 每次刷新都重新读取角色，角色变更在下一次刷新后生效
+	user, err := s.users.FindByID(ctx, session.UserID)
+	if err == repository.ErrNotFound {
+		return nil, ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s.extend(session, client, now)
+	active, err := s.sessions.Extend(ctx, session)
+	if err != nil {
+		return nil, err
+	}
+	// 会话在读取之后被撤销
+	if !active {
+		return nil, ErrSessionRevoked
+	}
+	return s.issue(ctx, session, user.Role)
+}
+
+// 撤销会话，会话中的访问令牌和刷新令牌全部失效
+func (s *SessionService) Revoke(ctx context.Context, sessionID uint) error {
+	return s.sessions.Revoke(ctx, sessionID)
+}
+
+// 用户的有效会话
+func (s *SessionService) List(ctx context.Context, userID uint) ([]models.Session, error) {
+	return s.sessions.ListActiveByUser(ctx, userID, time.Now())
+}
+
+// 撤销用户自己的某个会话
+func (s *SessionService) RevokeForUser(ctx context.Context, userID, sessionID uint) error {
+	session, err := s.sessions.FindByID(ctx, sessionID)
+	if err == repository.ErrNotFound || (err == nil && session.UserID != userID) {
+		return ErrSessionNotFound
+	}
+	if err != nil {
+		return err
+	}
+	return s.sessions.Revoke(ctx, sessionID)
+}
+
+// 撤销用户的全部会话，exceptID 不为 0 时保留该会话
+func (s *SessionService) RevokeAll(ctx context.Context, userID, exceptID uint) error {
+	return s.sessions.RevokeAllForUser(ctx, userID, exceptID)
+}
+
+// 校验访问令牌及其所属会话
+func (s *SessionService) Authenticate(ctx context.Context, accessToken string) (*auth.Claims, error) {
+	claims, err := s.tokens.Parse(accessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := s.sessions.FindByID(ctx, claims.SessionID)
+	if err == repository.ErrNotFound {
+		return nil, auth.ErrInvalidToken
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if !session.Active(now) || session.UserID != claims.UserID {
+		return nil, auth.ErrInvalidToken
+	}
+	if now.Sub(session.LastSeenAt) > touchInterval {
+		if err := s.sessions.Touch(ctx, session.ID, now); err != nil {
+			return nil, err
+		}
+	}
+	return claims, nil
+}
+
+// 记录客户端信息并延长会话有效期
+func (s *SessionService) extend(session *models.Session, client ClientInfo, now time.Time) {
+	session.UserAgent = truncate(client.UserAgent, 255)
+	session.IP = truncate(client.IP, 45)
+	session.LastSeenAt = now
+	session.ExpiresAt = now.Add(s.refreshExpire)
+}
+
+// 签发访问令牌和新的刷新令牌
+func (s *SessionService) issue(ctx context.Context, session *models.Session, role string) (*TokenPair, error) {
+	accessToken, err := s.tokens.Generate(session.UserID, session.ID, role)
+	if err != nil {
+		return nil, err
+	}
+
+	plain, hash, err := auth.NewOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+	err = s.sessions.CreateRefreshToken(ctx, &models.RefreshToken{
+		SessionID: session.ID,
+		TokenHash: hash,
+		ExpiresAt: session.ExpiresAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: plain,
+		ExpiresIn:    int64(s.tokens.Expire().Seconds()),
+	}, nil
+}
+
+// 按字符截断字符串
+func truncate(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max])
+}
@@ -0,0 +1,168 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"unicode/utf8"
+
+	"blog-backend/internal/models"
+	"blog-backend/internal/repository"
+	"blog-backend/internal/slug"
+)
+
+// 每篇文章最多的标签数量
+const MaxArticleTags = 10
+
+var (
+	ErrInvalidTagName = newError(KindInvalidInput, "标签名称不能为空且不能超过30个字符")
+	ErrTooManyTags    = newError(KindInvalidInput, "每篇文章最多10个标签")
+	ErrTagNotFound    = newError(KindNotFound, "标签不存在")
+	ErrTagExists      = newError(KindConflict, "标签已存在")
+)
+
+// 标签管理，文章使用的标签不存在时自动创建
+type TagService struct {
+	tags repository.TagRepository
+}
+
+func NewTagService(tags repository.TagRepository) *TagService {
+	return &TagService{tags: tags}
+}
+
+// 标签云：全部标签及其已发布文章数量
+func (s *TagService) List(ctx context.Context) ([]repository.TagCount, error) {
+	return s.tags.ListWithCounts(ctx)
+}
+
+// 按 slug 查询标签，不存在时返回 ErrTagNotFound
+func (s *TagService) GetBySlug(ctx context.Context, tagSlug string) (*models.Tag, error) {
+	tag, err := s.tags.FindBySlug(ctx, tagSlug)
+	if err == repository.ErrNotFound {
+		return nil, ErrTagNotFound
+	}
+	return tag, err
+}
+
+// 创建标签，名称不区分大小写，已存在时返回 ErrTagExists
+func (s *TagService) Create(ctx context.Context, name string) (*models.Tag, error) {
+	name, err := normalizeTagName(name)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.tags.FindByName(ctx, name); err == nil {
+		return nil, ErrTagExists
+	} else if err != repository.ErrNotFound {
+		return nil, err
+	}
+	return s.create(ctx, name)
+}
+
+// 重命名标签并重新生成 slug
+func (s *TagService) Rename(ctx context.Context, id uint, name string) (*models.Tag, error) {
+	name, err := normalizeTagName(name)
+	if err != nil {
+		return nil, err
+	}
+	tag, err := s.tags.FindByID(ctx, id)
+	if err == repository.ErrNotFound {
+		return nil, ErrTagNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if other, err := s.tags.FindByName(ctx, name); err == nil && other.ID != tag.ID {
+		return nil, ErrTagExists
+	} else if err != nil && err != repository.ErrNotFound {
+		return nil, err
+	}
+
+	tag.Name = name
+	if tag.Slug, err = s.uniqueSlug(ctx, name, tag.ID); err != nil {
+		return nil, err
+	}
+	if err := s.tags.Update(ctx, tag); err != nil {
+		return nil, err
+	}
+	return tag, nil
+}
+
+// 删除标签，同时从所有文章中移除
+func (s *TagService) Delete(ctx context.Context, id uint) error {
+	err := s.tags.Delete(ctx, id)
+	if err == repository.ErrNotFound {
+		return ErrTagNotFound
+	}
+	return err
+}
+
+// 将文章的标签名称转换为标签，忽略重复的名称，不存在的标签自动创建
+//
+// 全部名称校验通过后才会创建标签。
+func (s *TagService) Resolve(ctx context.Context, names []string) ([]models.Tag, error) {
+	var normalized []string
+	seen := make(map[string]bool)
+	for _, name := range names {
+		name, err := normalizeTagName(name)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[strings.ToLower(name)] {
+			seen[strings.ToLower(name)] = true
+			normalized = append(normalized, name)
+		}
+	}
+	if len(normalized) > MaxArticleTags {
+		return nil, ErrTooManyTags
+	}
+
+	tags := make([]models.Tag, 0, len(normalized))
+	for _, name := range normalized {
+		tag, err := s.tags.FindByName(ctx, name)
+		if err == repository.ErrNotFound {
+			tag, err = s.create(ctx, name)
+			if err != nil {
+				// 同名标签可能刚被并发的请求创建
+				if existing, findErr := s.tags.FindByName(ctx, name); findErr == nil {
+					tag, err = existing, nil
+				}
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, *tag)
+	}
+	return tags, nil
+}
+
+func (s *TagService) create(ctx context.Context, name string) (*models.Tag, error) {
+	tagSlug, err := s.uniqueSlug(ctx, name, 0)
+	if err != nil {
+		return nil, err
+	}
+	tag := &models.Tag{Name: name, Slug: tagSlug}
+	if err := s.tags.Create(ctx, tag); err != nil {
+		return nil, err
+	}
+	return tag, nil
+}
+
+// 由名称生成未被其他标签占用的 slug，id 为正在修改的标签，新建时为 0
+func (s *TagService) uniqueSlug(ctx context.Context, name string, id uint) (string, error) {
+	base := slug.Make(name)
+	if base == "" {
+		base = "tag"
+	}
+	return slug.Unique(base, func(candidate string) (bool, error) {
+		return s.tags.SlugTaken(ctx, candidate, id)
+	})
+}
+
+// 去掉首尾空白并合并连续的空白，名称为空或超过30个字符时返回 ErrInvalidTagName
+func normalizeTagName(name string) (string, error) {
+	name = strings.Join(strings.Fields(name), " ")
+	if name == "" || utf8.RuneCountInString(name) > 30 {
+		return "", ErrInvalidTagName
+	}
+	return name, nil
+}
@@ -0,0 +1,238 @@
+package services
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"blog-backend/internal/auth"
+	"blog-backend/internal/models"
+	"blog-backend/internal/repository"
+)
+
+const (
+	purposeTwoFactorChallenge = "2fa_challenge"
+	// 登录挑战令牌有效期
+	challengeExpire = 5 * time.Minute
+	// 每次生成的恢复码数量
+	recoveryCodeCount = 10
+)
+
+var (
+	ErrTwoFactorEnabled     = newError(KindInvalidInput, "两步验证已开启")
+	ErrTwoFactorNotEnabled  = newError(KindInvalidInput, "两步验证未开启")
+	ErrTwoFactorNotEnrolled = newError(KindInvalidInput, "请先获取两步验证密钥")
+	ErrInvalidTOTPCode      = newError(KindInvalidInput, "验证码错误")
+	ErrInvalidChallenge     = newError(KindUnauthorized, "登录验证已过期，请重新登录")
+)
+
+// 两步验证登录挑战
+type TwoFactorChallenge struct {
+	Token string `json:"challenge_token"`
+	// 有效期 (秒)
+	ExpiresIn int64 `json:"expires_in"`
+}
+
+// 两步验证开启信息，密钥只在开启时返回一次
+type TwoFactorEnrollment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"otpauth_uri"`
+}
+
+// TOTP 两步验证
+type TwoFactorService struct {
+	users    repository.UserRepository
+	codes    repository.RecoveryCodeRepository
+	sessions *SessionService
+	throttle *LoginThrottleService
+	signer   *auth.Signer
+	issuer   string
+}
+
+func NewTwoFactorService(users repository.UserRepository, codes repository.RecoveryCodeRepository, sessions *SessionService, throttle *LoginThrottleService, signer *auth.Signer, issuer string) *TwoFactorService {
+	return &TwoFactorService{users: users, codes: codes, sessions: sessions, throttle: throttle, signer: signer, issuer: issuer}
+}
+
+// 生成待确认的密钥，需要使用身份验证器中的验证码确认后才会开启
+func (s *TwoFactorService) Enroll(ctx context.Context, userID uint, password string, client ClientInfo) (*TwoFactorEnrollment, error) {
+	user, err := reauthenticate(ctx, s.users, s.throttle, userID, password, client)
+	if err != nil {
+		return nil, err
+	}
+	if user.TwoFactorEnabled() {
+		return nil, ErrTwoFactorEnabled
+	}
+
+	secret, err := auth.NewTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+	user.TOTPSecret = secret
+	if err := s.users.Update(ctx, user); err != nil {
+		return nil, err
+	}
+	return &TwoFactorEnrollment{Secret: secret, URI: auth.TOTPURI(s.issuer, user.Email, secret)}, nil
+}
+
+// 使用第一个验证码确认并开启两步验证，返回恢复码
+func (s *TwoFactorService) Confirm(ctx context.Context, userID uint, code string) ([]string, error) {
+	user, err := s.findUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TwoFactorEnabled() {
+		return nil, ErrTwoFactorEnabled
+	}
+	if user.TOTPSecret == "" {
+		return nil, ErrTwoFactorNotEnrolled
+	}
+	if err := s.checkTOTP(ctx, user, code); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	user.TOTPEnabledAt = &now
+	if err := s.users.Update(ctx, user); err != nil {
+		return nil, err
+	}
+	return s.newRecoveryCodes(ctx, user.ID)
+}
+
+// 关闭两步验证，需要验证当前密码
+func (s *TwoFactorService) Disable(ctx context.Context, userID uint, password string, client ClientInfo) error {
+	user, err := reauthenticate(ctx, s.users, s.throttle, userID, password, client)
+	if err != nil {
+		return err
+	}
+	if !user.TwoFactorEnabled() {
+		return ErrTwoFactorNotEnabled
+	}
+
+	user.TOTPEnabledAt = nil
+	user.TOTPSecret = ""
+	if err := s.users.Update(ctx, user); err != nil {
+		return err
+	}
+	return s.codes.DeleteByUser(ctx, user.ID)
+}
+
+// 重新生成恢复码，之前的恢复码全部作废
+func (s *TwoFactorService) RegenerateRecoveryCodes(ctx context.Context, userID uint, password string, client ClientInfo) ([]string, error) {
+	user, err := reauthenticate(ctx, s.users, s.throttle, userID, password, client)
+	if err != nil {
+		return nil, err
+	}
+	if !user.TwoFactorEnabled() {
+		return nil, ErrTwoFactorNotEnabled
+	}
+	return s.newRecoveryCodes(ctx, user.ID)
+}
+
+// 为已通过密码校验的用户生成登录挑战
+func (s *TwoFactorService) Challenge(user *models.User) *TwoFactorChallenge {
+	subject := strconv.FormatUint(uint64(user.ID), 10)
+	return &TwoFactorChallenge{
+		Token:     s.signer.Sign(purposeTwoFactorChallenge, subject, time.Now().Add(challengeExpire)),
+		ExpiresIn: int64(challengeExpire.Seconds()),
+	}
+}
+
+// 使用验证码或恢复码完成登录挑战并创建登录会话
+func (s *TwoFactorService) CompleteLogin(ctx context.Context, challenge, code string, client ClientInfo) (*models.User, *TokenPair, error) {
+	subject, err := s.signer.Verify(purposeTwoFactorChallenge, challenge, time.Now())
+	if err != nil {
+		return nil, nil, ErrInvalidChallenge
+	}
+	id, err := strconv.ParseUint(subject, 10, 64)
+	if err != nil {
+		return nil, nil, ErrInvalidChallenge
+	}
+	user, err := s.users.FindByID(ctx, uint(id))
+	if err == repository.ErrNotFound {
+		return nil, nil, ErrInvalidChallenge
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if !user.TwoFactorEnabled() {
+		return nil, nil, ErrInvalidChallenge
+	}
+
+	// 验证码错误与密码错误共用失败计数
+	if err := s.throttle.Check(ctx, user.Email, client.IP); err != nil {
+		return nil, nil, err
+	}
+	if err := s.checkCode(ctx, user, code); err != nil {
+		if err == ErrInvalidTOTPCode {
+			if err := s.throttle.Fail(ctx, user.Email, client.IP); err != nil {
+				return nil, nil, err
+			}
+		}
+		return nil, nil, err
+	}
+	if err := s.throttle.Reset(ctx, user.Email); err != nil {
+		return nil, nil, err
+	}
+
+	tokens, err := s.sessions.Start(ctx, user, client)
+	if err != nil {
+		return nil, nil, err
+	}
+	return user, tokens, nil
+}
+
+// 校验验证码或恢复码，恢复码包含连字符，验证码为纯数字
+func (s *TwoFactorService) checkCode(ctx context.Context, user *models.User, code string) error {
+	if !strings.Contains(code, "-") && len(strings.TrimSpace(code)) <= 6 {
+		return s.checkTOTP(ctx, user, code)
+	}
+	used, err := s.codes.Use(ctx, user.ID, auth.HashRecoveryCode(code))
+	if err != nil {
+		return err
+	}
+	if !used {
+		return ErrInvalidTOTPCode
+	}
+	return nil
+}
+
+// 校验验证码，同一时间步的验证码只能使用一次
+func (s *TwoFactorService) checkTOTP(ctx context.Context, user *models.User, code string) error {
+	step, ok := auth.ValidateTOTP(user.TOTPSecret, code, time.Now())
+	if !ok {
+		return ErrInvalidTOTPCode
+	}
+	fresh, err := s.users.AdvanceTOTPStep(ctx, user.ID, step)
+	if err != nil {
+		return err
+	}
+	if !fresh {
+		return ErrInvalidTOTPCode
+	}
+	user.TOTPLastStep = step
+	return nil
+}
+
+func (s *TwoFactorService) newRecoveryCodes(ctx context.Context, userID uint) ([]string, error) {
+	codes, err := auth.NewRecoveryCodes(recoveryCodeCount)
+	if err != nil {
+		return nil, err
+	}
+	hashes := make([]string, len(codes))
+	for i, code := range codes {
+		hashes[i] = auth.HashRecoveryCode(code)
+	}
+	if err := s.codes.Replace(ctx, userID, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+func (s *TwoFactorService) findUser(ctx context.Context, id uint) (*models.User, error) {
+	user, err := s.users.FindByID(ctx, id)
+	if err == repository.ErrNotFound {
+		return nil, ErrUserNotFound
+	}
+	return user, err
+}
@@ -0,0 +1,60 @@
+package services
+
+import (
+	"context"
+
+	"blog-backend/internal/models"
+	"blog-backend/internal/repository"
+)
+
+type UpdateProfileParams struct {
+	Username string
+	Avatar   string
+}
+
+// 用户资料
+type UserService struct {
+	users repository.UserRepository
+}
+
+func NewUserService(users repository.UserRepository) *UserService {
+	return &UserService{users: users}
+}
+
+func (s *UserService) Get(ctx context.Context, id uint) (*models.User, error) {
+	user, err := s.users.FindByID(ctx, id)
+	if err == repository.ErrNotFound {
+		return nil, ErrUserNotFound
+	}
+	return user, err
+}
+
+// 更新用户资料，空字段保持不变
+func (s *UserService) UpdateProfile(ctx context.Context, id uint, params UpdateProfileParams) (*models.User, error) {
+	user, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if params.Username != "" && params.Username != user.Username {
+		if models.ReservedUsername(params.Username) {
+			return nil, ErrUsernameReserved
+		}
+		exists, err := s.users.ExistsByUsername(ctx, params.Username)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			return nil, ErrUserExists
+		}
+		user.Username = params.Username
+	}
+	if params.Avatar != "" {
+		user.Avatar = params.Avatar
+	}
+
+	if err := s.users.Update(ctx, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
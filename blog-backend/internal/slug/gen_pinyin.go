@@ -0,0 +1,54 @@
+//go:build ignore
+
+// 生成 pinyin.txt：U+4E00-U+9FFF 中每个汉字一行，内容为不带声调的拼音，没有读音时为空行。
+// 使用 ICU 的 uconv 逐字转写，不考虑多音字的上下文。
+//
+//	go run gen_pinyin.go
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+)
+
+const first, last = 0x4E00, 0x9FFF
+
+func main() {
+	var in bytes.Buffer
+	for r := rune(first); r <= last; r++ {
+		in.WriteString(string(r))
+		in.WriteByte('\n')
+	}
+
+	cmd := exec.Command("uconv", "-x", "Han-Latin; Latin-ASCII; Lower")
+	cmd.Stdin = &in
+	out, err := cmd.Output()
+	if err != nil {
+		log.Fatalf("执行 uconv 失败: %v", err)
+	}
+
+	valid := regexp.MustCompile(`^[a-z]+$`)
+	var b strings.Builder
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	n := 0
+	for scanner.Scan() {
+		if line := scanner.Text(); valid.MatchString(line) {
+			b.WriteString(line)
+		}
+		b.WriteByte('\n')
+		n++
+	}
+	if n != last-first+1 {
+		log.Fatalf("期望 %d 行，实际 %d 行", last-first+1, n)
+	}
+	if err := os.WriteFile("pinyin.txt", []byte(b.String()), 0o644); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("已生成 %d 个汉字的拼音\n", n)
+}
@@ -0,0 +1,116 @@
+// Package slug 由文章标题生成 URL 中使用的 slug
+package slug
+
+import (
+	_ "embed"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+//go:generate go run gen_pinyin.go
+
+// slug 的最大长度，超出时在单词边界截断
+const MaxLength = 80
+
+// 标题中没有可用字符时使用的 slug
+const Fallback = "article"
+
+// U+4E00-U+9FFF 中每个汉字一行的拼音，由 gen_pinyin.go 生成
+//
+//go:embed pinyin.txt
+var pinyinData string
+
+const firstHan, lastHan = 0x4E00, 0x9FFF
+
+var pinyin = sync.OnceValue(func() []string {
+	return strings.Split(pinyinData, "\n")[:lastHan-firstHan+1]
+})
+
+// 不能通过 Unicode 分解去掉变音符号的拉丁字母
+var latin = map[rune]string{
+	'ß': "ss", 'æ': "ae", 'Æ': "ae", 'ø': "o", 'Ø': "o", 'œ': "oe", 'Œ': "oe",
+	'đ': "d", 'Đ': "d", 'ł': "l", 'Ł': "l", 'þ': "th", 'Þ': "th", 'ð': "d", 'Ð': "d",
+}
+
+// 由标题生成 slug：只包含小写字母、数字和连字符，汉字转为不带声调的拼音，拉丁字母去掉变音符号
+//
+// 无法转写的字符视为分隔符，结果可能为空字符串。
+func Make(title string) string {
+	var words []string
+	var word strings.Builder
+	flush := func() {
+		if word.Len() > 0 {
+			words = append(words, word.String())
+			word.Reset()
+		}
+	}
+
+	for _, r := range norm.NFKD.String(title) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			word.WriteRune(r)
+		case r >= 'A' && r <= 'Z':
+			word.WriteRune(unicode.ToLower(r))
+		case unicode.Is(unicode.Mn, r):
+			// 分解后的变音符号
+		case r >= firstHan && r <= lastHan:
+			// 每个汉字的拼音作为一个单词
+			flush()
+			if p := pinyin()[r-firstHan]; p != "" {
+				words = append(words, p)
+			}
+		case r == '\'' || r == '’':
+			// don't -> dont
+		default:
+			if s, ok := latin[r]; ok {
+				word.WriteString(s)
+				continue
+			}
+			flush()
+		}
+	}
+	flush()
+
+	return truncate(words, MaxLength)
+}
+
+// 用连字符连接单词，总长度不超过 max，第一个单词过长时直接截断
+func truncate(words []string, max int) string {
+	var b strings.Builder
+	for _, w := range words {
+		if b.Len() > 0 {
+			if b.Len()+1+len(w) > max {
+				break
+			}
+			b.WriteByte('-')
+		}
+		if len(w) > max {
+			w = w[:max]
+		}
+		b.WriteString(w)
+	}
+	return b.String()
+}
+
+// 生成未被占用的 slug，base 被占用时依次尝试 base-2、base-3 ...
+func Unique(base string, taken func(string) (bool, error)) (string, error) {
+	if base == "" {
+		base = Fallback
+	}
+	candidate := base
+	for n := 2; ; n++ {
+		used, err := taken(candidate)
+		if err != nil {
+			return "", err
+		}
+		if !used {
+			return candidate, nil
+		}
+		suffix := "-" + strconv.Itoa(n)
+		candidate = strings.TrimRight(base[:min(len(base), MaxLength-len(suffix))], "-") + suffix
+	}
+}
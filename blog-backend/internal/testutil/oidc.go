@@ -0,0 +1,185 @@
+package testutil
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+
+	"blog-backend/config"
+)
+
+// 身份提供方中的用户
+type IdPUser struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Username      string
+}
+
+// 授权码对应的登录信息
+type idpGrant struct {
+	user        IdPUser
+	nonce       string
+	challenge   string
+	redirectURI string
+}
+
+// 本地 OIDC 身份提供方，支持发现文档、JWKS 和授权码 + PKCE 换取 ID Token
+type MockIdP struct {
+	Server       *httptest.Server
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	// 签发前修改 ID Token 的声明，用于测试校验失败的情况
+	Tamper func(claims jwt.MapClaims)
+
+	key    *rsa.PrivateKey
+	mu     sync.Mutex
+	grants map[string]idpGrant
+}
+
+func NewMockIdP(t *testing.T) *MockIdP {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	idp := &MockIdP{
+		ClientID:     "blog",
+		ClientSecret: "blog-secret",
+		RedirectURL:  "http://localhost:3000/oidc/callback",
+		key:          key,
+		grants:       make(map[string]idpGrant),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", idp.discovery)
+	mux.HandleFunc("/jwks", idp.jwks)
+	mux.HandleFunc("/token", idp.token)
+	idp.Server = httptest.NewServer(mux)
+	t.Cleanup(idp.Server.Close)
+	return idp
+}
+
+func (m *MockIdP) Issuer() string {
+	return m.Server.URL
+}
+
+// 将服务配置为使用该身份提供方
+func (m *MockIdP) Configure(cfg *config.Config) {
+	cfg.OIDC.Issuer = m.Issuer()
+	cfg.OIDC.ClientID = m.ClientID
+	cfg.OIDC.ClientSecret = m.ClientSecret
+	cfg.OIDC.RedirectURL = m.RedirectURL
+}
+
+// 模拟用户在身份提供方登录并同意授权，返回回调中的 code 和 state
+func (m *MockIdP) Authorize(t *testing.T, authURL string, user IdPUser) (string, string) {
+	t.Helper()
+	u, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := u.Query()
+	if q.Get("response_type") != "code" || q.Get("client_id") != m.ClientID || q.Get("redirect_uri") != m.RedirectURL {
+		t.Fatalf("授权请求参数错误: %s", authURL)
+	}
+	if q.Get("code_challenge_method") != "S256" || q.Get("code_challenge") == "" || q.Get("state") == "" || q.Get("nonce") == "" {
+		t.Fatalf("授权请求缺少 PKCE 或 state/nonce: %s", authURL)
+	}
+
+	code := rand.Text()
+	m.mu.Lock()
+	m.grants[code] = idpGrant{user: user, nonce: q.Get("nonce"), challenge: q.Get("code_challenge"), redirectURI: q.Get("redirect_uri")}
+	m.mu.Unlock()
+	return code, q.Get("state")
+}
+
+func (m *MockIdP) discovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{
+		"issuer":                 m.Issuer(),
+		"authorization_endpoint": m.Issuer() + "/authorize",
+		"token_endpoint":         m.Issuer() + "/token",
+		"jwks_uri":               m.Issuer() + "/jwks",
+	})
+}
+
+func (m *MockIdP) jwks(w http.ResponseWriter, r *http.Request) {
+	pub := m.key.PublicKey
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": "mock-key",
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		}},
+	})
+}
+
+// 校验客户端凭证和 PKCE 后签发 ID Token，授权码只能使用一次
+func (m *MockIdP) token(w http.ResponseWriter, r *http.Request) {
+	id, secret, ok := r.BasicAuth()
+	if !ok || id != m.ClientID || secret != m.ClientSecret {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+	if err := r.ParseForm(); err != nil || r.PostForm.Get("grant_type") != "authorization_code" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "unsupported_grant_type"})
+		return
+	}
+
+	code := r.PostForm.Get("code")
+	m.mu.Lock()
+	grant, ok := m.grants[code]
+	delete(m.grants, code)
+	m.mu.Unlock()
+
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if !ok || grant.redirectURI != r.PostForm.Get("redirect_uri") || base64.RawURLEncoding.EncodeToString(sum[:]) != grant.challenge {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":                m.Issuer(),
+		"aud":                m.ClientID,
+		"sub":                grant.user.Subject,
+		"email":              grant.user.Email,
+		"email_verified":     grant.user.EmailVerified,
+		"preferred_username": grant.user.Username,
+		"nonce":              grant.nonce,
+		"iat":                now.Unix(),
+		"exp":                now.Add(5 * time.Minute).Unix(),
+	}
+	if m.Tamper != nil {
+		m.Tamper(claims)
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = "mock-key"
+	idToken, err := token.SignedString(m.key)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"access_token": rand.Text(), "token_type": "Bearer", "id_token": idToken})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
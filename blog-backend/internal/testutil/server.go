@@ -0,0 +1,248 @@
+// Package testutil 提供集成测试使用的 HTTP 测试服务器
+package testutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"blog-backend/config"
+	"blog-backend/internal/database"
+	"blog-backend/internal/migrations"
+	"blog-backend/internal/models"
+	"blog-backend/internal/routes"
+)
+
+// 统一响应格式
+type Envelope struct {
+	Success   *bool           `json:"success"`
+	Message   string          `json:"message"`
+	ErrorCode string          `json:"error_code"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// 测试请求的响应
+type Response struct {
+	Status int
+	Header http.Header
+	Body   []byte
+	Envelope
+}
+
+// 将 data 字段解码到 v
+func (r *Response) DecodeData(t *testing.T, v interface{}) {
+	t.Helper()
+	if err := json.Unmarshal(r.Data, v); err != nil {
+		t.Fatalf("解析响应 data 失败: %v\n%s", err, r.Body)
+	}
+}
+
+// 断言请求成功
+func (r *Response) AssertOK(t *testing.T) {
+	t.Helper()
+	if r.Status != http.StatusOK || !*r.Success {
+		t.Fatalf("期望请求成功，实际状态码 %d: %s", r.Status, r.Body)
+	}
+}
+
+// 断言请求失败且返回指定的状态码和 error_code
+func (r *Response) AssertError(t *testing.T, status int, code string) {
+	t.Helper()
+	if r.Status != status || *r.Success || r.ErrorCode != code {
+		t.Fatalf("期望 %d %s，实际状态码 %d: %s", status, code, r.Status, r.Body)
+	}
+}
+
+// 基于内存 SQLite 的完整服务
+type Server struct {
+	Engine *gin.Engine
+	DB     *gorm.DB
+	Config *config.Config
+}
+
+// 测试配置，使用内存数据库
+func Config() *config.Config {
+	cfg := config.Default()
+	cfg.Database.Driver = config.DriverSQLite
+	cfg.Database.Name = ":memory:"
+	cfg.JWT.Secret = "test_secret"
+	cfg.JWT.Expire = time.Hour
+	cfg.Mail.Driver = config.MailDriverFile
+	return cfg
+}
+
+// 启动测试服务，每次调用使用独立的内存数据库
+func NewServer(t *testing.T) *Server {
+	t.Helper()
+	return NewServerWithConfig(t, Config())
+}
+
+func NewServerWithConfig(t *testing.T, cfg *config.Config) *Server {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	if cfg.Mail.Driver == config.MailDriverFile {
+		cfg.Mail.Dir = t.TempDir()
+	}
+
+	db, err := database.Open(cfg.Database)
+	if err != nil {
+		t.Fatalf("打开测试数据库失败: %v", err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	if _, err := migrations.New(db).Up(); err != nil {
+		t.Fatalf("执行迁移失败: %v", err)
+	}
+
+	r := gin.New()
+	// 与 serve 一致，按配置信任代理
+	if err := r.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		t.Fatalf("设置可信代理失败: %v", err)
+	}
+	if err := routes.SetupRoutes(r, db, cfg); err != nil {
+		t.Fatalf("初始化路由失败: %v", err)
+	}
+	return &Server{Engine: r, DB: db, Config: cfg}
+}
+
+// 发送请求，body 不为 nil 时编码为 JSON，token 不为空时添加认证头
+//
+// 所有响应都必须符合统一的 success/error_code 格式，否则测试失败。
+func (s *Server) Do(t *testing.T, method, path string, body interface{}, token string) *Response {
+	t.Helper()
+
+	var reader *bytes.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			t.Fatalf("编码请求失败: %v", err)
+		}
+		reader = bytes.NewReader(data)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+
+	req := httptest.NewRequest(method, path, reader)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	rec := httptest.NewRecorder()
+	s.Engine.ServeHTTP(rec, req)
+
+	resp := &Response{Status: rec.Code, Header: rec.Header(), Body: rec.Body.Bytes()}
+	if err := json.Unmarshal(resp.Body, &resp.Envelope); err != nil {
+		t.Fatalf("%s %s 返回了非 JSON 响应 (%d): %s", method, path, rec.Code, resp.Body)
+	}
+	if resp.Success == nil {
+		t.Fatalf("%s %s 响应缺少 success 字段: %s", method, path, resp.Body)
+	}
+	if !*resp.Success && resp.ErrorCode == "" {
+		t.Fatalf("%s %s 失败响应缺少 error_code 字段: %s", method, path, resp.Body)
+	}
+	if *resp.Success && (rec.Code < 200 || rec.Code >= 300) {
+		t.Fatalf("%s %s 返回 success=true 但状态码为 %d", method, path, rec.Code)
+	}
+	return resp
+}
+
+// 注册用户并通过邮件中的链接验证邮箱，登录后返回 token 和用户ID
+func (s *Server) Register(t *testing.T, username, email, password string) (string, uint) {
+	t.Helper()
+
+	s.Do(t, http.MethodPost, "/api/v1/auth/register", map[string]string{
+		"username": username,
+		"email":    email,
+		"password": password,
+	}, "").AssertOK(t)
+	s.Do(t, http.MethodPost, "/api/v1/auth/verify-email", map[string]string{"token": s.MailToken(t, email)}, "").AssertOK(t)
+
+	return s.Login(t, email, password)
+}
+
+// 登录并返回访问令牌和用户ID
+func (s *Server) Login(t *testing.T, email, password string) (string, uint) {
+	t.Helper()
+
+	resp := s.Do(t, http.MethodPost, "/api/v1/auth/login", map[string]string{
+		"email":    email,
+		"password": password,
+	}, "")
+	resp.AssertOK(t)
+
+	var data struct {
+		Token string `json:"token"`
+		User  struct {
+			ID uint `json:"id"`
+		} `json:"user"`
+	}
+	resp.DecodeData(t, &data)
+	return data.Token, data.User.ID
+}
+
+// 注册用户并直接在数据库中设置角色，返回以该角色重新登录后的访问令牌和用户ID
+func (s *Server) RegisterWithRole(t *testing.T, username, email, password, role string) (string, uint) {
+	t.Helper()
+
+	_, userID := s.Register(t, username, email, password)
+	if err := s.DB.Model(&models.User{}).Where("id = ?", userID).Update("role", role).Error; err != nil {
+		t.Fatalf("设置角色失败: %v", err)
+	}
+	token, _ := s.Login(t, email, password)
+	return token, userID
+}
+
+// 发送到指定地址的邮件，按发送顺序排列
+func (s *Server) Mails(t *testing.T, to string) []string {
+	t.Helper()
+
+	entries, err := os.ReadDir(s.Config.Mail.Dir)
+	if err != nil && !os.IsNotExist(err) {
+		t.Fatalf("读取邮件目录失败: %v", err)
+	}
+	var mails []string
+	for _, entry := range entries {
+		data, err := os.ReadFile(filepath.Join(s.Config.Mail.Dir, entry.Name()))
+		if err != nil {
+			t.Fatalf("读取邮件失败: %v", err)
+		}
+		if strings.Contains(string(data), "\r\nTo: "+to+"\r\n") {
+			mails = append(mails, string(data))
+		}
+	}
+	return mails
+}
+
+var tokenPattern = regexp.MustCompile(`[?&]token=([A-Za-z0-9_.-]+)`)
+
+// 从最近一封发送到指定地址的邮件中提取链接里的令牌
+func (s *Server) MailToken(t *testing.T, to string) string {
+	t.Helper()
+
+	mails := s.Mails(t, to)
+	if len(mails) == 0 {
+		t.Fatalf("没有发送到 %s 的邮件", to)
+	}
+	match := tokenPattern.FindStringSubmatch(mails[len(mails)-1])
+	if match == nil {
+		t.Fatalf("邮件中没有令牌链接:\n%s", mails[len(mails)-1])
+	}
+	return match[1]
+}
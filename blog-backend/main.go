@@ -1,59 +0,0 @@
-package main
-
-import (
-	"log"
-	"os"
-
-	"github.com/gin-gonic/gin"
-	"github.com/joho/godotenv"
-	"gorm.io/driver/mysql"
-	"gorm.io/gorm"
-
-	"blog-backend/config"
-	"blog-backend/internal/models"
-	"blog-backend/internal/routes"
-)
-
-func main() {
-	// 加载环境变量
-	if err := godotenv.Load(); err != nil {
-		log.Println("未找到 .env 文件")
-	}
-
-	// 初始化数据库
-	dsn := os.Getenv("DB_USER") + ":" + os.Getenv("DB_PASSWORD") + "@tcp(" + os.Getenv("DB_HOST") + ":" + os.Getenv("DB_PORT") + ")/" + os.Getenv("DB_NAME") + "?charset=utf8mb4&parseTime=True&loc=Local"
-	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{})
-	if err != nil {
-		log.Fatal("无法连接到数据库:", err)
-	}
-
-	// 自动迁移数据库
-	db.AutoMigrate(&models.User{}, &models.Article{}, &models.Comment{})
-
-	// 初始化配置
-	config.DB = db
-
-	// 设置路由
-	r := gin.Default()
-	// 添加 CORS 中间件
-	r.Use(func(c *gin.Context) {
-		c.Header("Access-Control-Allow-Origin", "http://localhost:3000")
-		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Authorization")
-		c.Header("Access-Control-Allow-Credentials", "true")
-
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(204)
-			return
-		}
-		c.Next()
-	})
-	routes.SetupRoutes(r)
-
-	// 启动服务器
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
-	}
-	r.Run(":" + port)
-}
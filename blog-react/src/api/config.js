@@ -22,18 +22,57 @@ api.interceptors.request.use(
   }
 );
 
+// 清除登录状态并跳转到登录页
+const redirectToLogin = () => {
+  localStorage.removeItem('token');
+  localStorage.removeItem('refresh_token');
+  localStorage.removeItem('user');
+  window.location.href = '/login';
+};
+
+// 正在进行的刷新请求，多个请求同时过期时共用一次刷新
+let refreshing = null;
+
+const refreshToken = () => {
+  if (!refreshing) {
+    refreshing = axios
+      .post(`${api.defaults.baseURL}/auth/refresh`, {
+        refresh_token: localStorage.getItem('refresh_token'),
+      })
+      .then(({ data }) => {
+        localStorage.setItem('token', data.data.token);
+        localStorage.setItem('refresh_token', data.data.refresh_token);
+        return data.data.token;
+      })
+      .finally(() => {
+        refreshing = null;
+      });
+  }
+  return refreshing;
+};
+
 // 响应拦截器
 api.interceptors.response.use(
   (response) => {
     // 只返回数据部分
     return response.data;
   },
-  (error) => {
+  async (error) => {
+    const original = error.config;
     if (error.response?.status === 401) {
-      // token 过期或无效，清除本地存储并跳转到登录页
-      localStorage.removeItem('token');
-      localStorage.removeItem('user');
-      window.location.href = '/login';
+      // 访问令牌过期时使用刷新令牌重试一次，失败则跳转到登录页
+      if (!original._retried && localStorage.getItem('refresh_token')) {
+        original._retried = true;
+        try {
+          const token = await refreshToken();
+          original.headers.Authorization = `Bearer ${token}`;
+          return api(original);
+        } catch (refreshError) {
+          redirectToLogin();
+          return Promise.reject(refreshError);
+        }
+      }
+      redirectToLogin();
     }
     return Promise.reject(error);
   }
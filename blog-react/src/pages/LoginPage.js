@@ -19,6 +19,7 @@ const LoginPage = () => {
       if (response.success) {
         // 保存 token 和用户信息到 localStorage
         localStorage.setItem('token', response.data.token);
+        localStorage.setItem('refresh_token', response.data.refresh_token);
         localStorage.setItem('user', JSON.stringify(response.data.user));
         message.success('登录成功');
         navigate('/'); // 跳转到首页
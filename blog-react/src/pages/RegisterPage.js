@@ -19,6 +19,7 @@ const RegisterPage = () => {
       if (response.success) {
         // 保存 token 到 localStorage
         localStorage.setItem('token', response.data.token);
+        localStorage.setItem('refresh_token', response.data.refresh_token);
         message.success('注册成功');
         navigate('/'); // 跳转到首页
       } else {